@@ -0,0 +1,203 @@
+package alerts
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/memstore"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/synonyms"
+)
+
+// fakeNotifier records the alerts, it fails while err is set
+type fakeNotifier struct {
+	mu     sync.Mutex
+	alerts []Alert
+	err    error
+}
+
+func (f *fakeNotifier) Notify(_ context.Context, a Alert) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.alerts = append(f.alerts, a)
+	return nil
+}
+
+// take returns the alerts recorded since the last call
+func (f *fakeNotifier) take() []Alert {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	alerts := f.alerts
+	f.alerts = nil
+	return alerts
+}
+
+func newTestLogger() *logging.Logger {
+	return logging.New(logging.Config{Output: io.Discard})
+}
+
+func TestMatcher(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	seller := models.Seller{Name: "Seller A", Location: "IND"}
+	if err := store.CreateSeller(ctx, &seller); err != nil {
+		t.Fatalf("CreateSeller: %v", err)
+	}
+	dictionary := synonyms.New(store)
+	if err := dictionary.CreateSynonymGroup(ctx, &models.SynonymGroup{Terms: []string{"earphones", "earbuds"}}); err != nil {
+		t.Fatalf("CreateSynonymGroup: %v", err)
+	}
+	searches := []models.SavedSearch{
+		{BuyerID: 1, ProductName: "earphones", Location: "ind"},
+		{BuyerID: 2, ProductName: "earbuds", DesiredQty: 10},
+		{BuyerID: 3, ProductName: "drone"},
+		{BuyerID: 4, MaxPrice: 50},
+	}
+	for i := range searches {
+		if err := store.CreateSavedSearch(ctx, &searches[i]); err != nil {
+			t.Fatalf("CreateSavedSearch: %v", err)
+		}
+	}
+
+	notifier := &fakeNotifier{}
+	matcher := NewMatcher(store, store, dictionary, notifier, 10, newTestLogger())
+	defer matcher.Close(ctx)
+	products := Watch(store, matcher)
+
+	buyers := func() []int {
+		t.Helper()
+		if err := matcher.Flush(ctx); err != nil {
+			t.Fatalf("Flush: %v", err)
+		}
+		var ids []int
+		for _, a := range notifier.take() {
+			ids = append(ids, a.BuyerID)
+		}
+		return ids
+	}
+
+	earbuds := models.Product{SellerID: seller.ID, ProductName: "Wireless Earbuds", Price: 80, Quantity: 5}
+	if err := products.CreateProduct(ctx, &earbuds); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	if got := buyers(); len(got) != 1 || got[0] != 1 {
+		t.Errorf("Expected buyer 1 to be alerted of the new earbuds, got %v", got)
+	}
+
+	price := 40.0
+	if _, err := products.UpdateProduct(ctx, earbuds.ID, models.ProductUpdate{Price: &price}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	if got := buyers(); len(got) != 0 {
+		t.Errorf("Expected a price change not to alert, got %v", got)
+	}
+
+	quantity := 12
+	if _, err := products.UpdateProduct(ctx, earbuds.ID, models.ProductUpdate{Quantity: &quantity}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	// buyer 1 was satisfied by the former stock and buyer 4 by the former price
+	if got := buyers(); len(got) != 1 || got[0] != 2 {
+		t.Errorf("Expected only buyer 2 to be alerted of the restock, got %v", got)
+	}
+
+	quantity = 0
+	if _, err := products.UpdateProduct(ctx, earbuds.ID, models.ProductUpdate{Quantity: &quantity}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	quantity = 3
+	if _, err := products.UpdateProduct(ctx, earbuds.ID, models.ProductUpdate{Quantity: &quantity}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	if got := buyers(); len(got) != 2 || got[0] != 1 || got[1] != 4 {
+		t.Errorf("Expected buyers 1 and 4 to be alerted when the sold out earbuds are back, got %v", got)
+	}
+}
+
+func TestDeduplicator(t *testing.T) {
+	ctx := context.Background()
+	notifier := &fakeNotifier{}
+	d := Deduplicate(notifier, time.Hour)
+	now := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+	d.now = func() time.Time { return now }
+
+	alert := Alert{BuyerID: 1, Product: models.Product{ID: 7}}
+	notify := func() int {
+		t.Helper()
+		if err := d.Notify(ctx, alert); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+		return len(notifier.take())
+	}
+	if n := notify(); n != 1 {
+		t.Fatalf("Expected the first alert to be sent, got %d", n)
+	}
+	now = now.Add(30 * time.Minute)
+	if n := notify(); n != 0 {
+		t.Errorf("Expected a duplicate within the window to be dropped, got %d", n)
+	}
+	alert.BuyerID = 2
+	if n := notify(); n != 1 {
+		t.Errorf("Expected another buyer to be alerted, got %d", n)
+	}
+	alert.BuyerID = 1
+	now = now.Add(time.Hour)
+	if n := notify(); n != 1 {
+		t.Errorf("Expected the alert to be sent again after the window, got %d", n)
+	}
+
+	notifier.err = errors.New("unreachable")
+	alert.Product.ID = 8
+	if err := d.Notify(ctx, alert); err == nil {
+		t.Fatal("Expected the error of the notifier")
+	}
+	notifier.err = nil
+	if n := notify(); n != 1 {
+		t.Errorf("Expected an alert that failed to be sent again, got %d", n)
+	}
+}
+
+func TestFileNotifier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alerts.jsonl")
+	n, err := OpenFileNotifier(path)
+	if err != nil {
+		t.Fatalf("OpenFileNotifier: %v", err)
+	}
+	for id := 1; id <= 2; id++ {
+		if err := n.Notify(context.Background(), Alert{BuyerID: id, Product: models.Product{ID: 7, ProductName: "Drone"}}); err != nil {
+			t.Fatalf("Notify: %v", err)
+		}
+	}
+	if err := n.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	defer file.Close()
+	var buyers []int
+	for lines := bufio.NewScanner(file); lines.Scan(); {
+		var a Alert
+		if err := json.Unmarshal(lines.Bytes(), &a); err != nil {
+			t.Fatalf("Expected a JSON alert per line, got %q: %v", lines.Text(), err)
+		}
+		buyers = append(buyers, a.BuyerID)
+	}
+	if len(buyers) != 2 || buyers[0] != 1 || buyers[1] != 2 {
+		t.Errorf("Expected the alerts of buyers 1 and 2, got %v", buyers)
+	}
+}
@@ -0,0 +1,178 @@
+// Package alerts - alerts the buyers of the products newly matching their saved searches: the
+// products created, and those restocked, through a WatchingStore are matched against the saved
+// searches by a goroutine and the alerts are handed to a Notifier.
+//
+// Like the search analytics, the products to match are queued in memory so the writes never
+// wait on the matching, and those not fitting in the queue are dropped and counted.
+package alerts
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/synonyms"
+)
+
+// matchTimeout bounds the matching of one product, the notifications included
+const matchTimeout = 10 * time.Second
+
+// change is a product to match, previous is its version before a restock, nil for a new product
+type change struct {
+	product  models.Product
+	previous *models.Product
+}
+
+// Matcher matches the products written against the saved searches in the background
+type Matcher struct {
+	searches   models.SavedSearchStore
+	sellers    models.SellerStore
+	dictionary *synonyms.Dictionary
+	notifier   Notifier
+	logger     *logging.Logger
+	changes    chan change
+	// flush receives a channel closed once the products queued so far are matched
+	flush chan chan struct{}
+	done  chan struct{}
+	// dropped counts the products dropped since the last one matched
+	dropped uint64
+}
+
+// NewMatcher starts a Matcher queuing up to bufferSize products, Close stops it. The product
+// names of the saved searches are expanded by the synonyms of the dictionary, which may be nil
+func NewMatcher(searches models.SavedSearchStore, sellers models.SellerStore, dictionary *synonyms.Dictionary,
+	notifier Notifier, bufferSize int, logger *logging.Logger) *Matcher {
+	m := &Matcher{
+		searches:   searches,
+		sellers:    sellers,
+		dictionary: dictionary,
+		notifier:   notifier,
+		logger:     logger,
+		changes:    make(chan change, bufferSize),
+		flush:      make(chan chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go m.run()
+	return m
+}
+
+// Created queues a product just created, the saved searches it matches are alerted
+func (m *Matcher) Created(p models.Product) {
+	m.enqueue(change{product: p})
+}
+
+// Restocked queues a product whose stock rose from that of previous, the saved searches it
+// matches and previous did not, those wanting more than its former stock, are alerted
+func (m *Matcher) Restocked(p, previous models.Product) {
+	m.enqueue(change{product: p, previous: &previous})
+}
+
+// enqueue queues the change without waiting, it is dropped when the queue is full. It must
+// not be called after Close
+func (m *Matcher) enqueue(c change) {
+	select {
+	case m.changes <- c:
+	default:
+		atomic.AddUint64(&m.dropped, 1)
+	}
+}
+
+// Flush returns once the products queued before it are matched and their alerts sent
+func (m *Matcher) Flush(ctx context.Context) error {
+	matched := make(chan struct{})
+	select {
+	case m.flush <- matched:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-matched:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close matches the products still queued and stops the matcher, or gives up when ctx is done
+func (m *Matcher) Close(ctx context.Context) error {
+	close(m.changes)
+	select {
+	case <-m.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *Matcher) run() {
+	defer close(m.done)
+	for {
+		select {
+		case c, ok := <-m.changes:
+			if !ok {
+				return
+			}
+			m.match(c)
+		case matched := <-m.flush:
+			// the products queued before the flush are in the queue already
+			for drained := false; !drained; {
+				select {
+				case c, ok := <-m.changes:
+					if ok {
+						m.match(c)
+					} else {
+						drained = true
+					}
+				default:
+					drained = true
+				}
+			}
+			close(matched)
+		}
+	}
+}
+
+// match alerts the saved searches the change newly matches, the alerts are lost when the
+// store or the notifier fail
+func (m *Matcher) match(c change) {
+	if dropped := atomic.SwapUint64(&m.dropped, 0); dropped > 0 {
+		m.logger.Warnw("Products dropped from the saved search alerts, the queue is full", "dropped", dropped)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), matchTimeout)
+	defer cancel()
+
+	seller, err := m.sellers.GetSellerByID(ctx, c.product.SellerID)
+	if err != nil {
+		m.logger.Errorw("Failed to match the saved searches", "productId", c.product.ID, "err", err)
+		return
+	}
+	candidates, err := m.searches.CandidateSavedSearches(ctx, c.product, seller)
+	if err != nil {
+		m.logger.Errorw("Failed to match the saved searches", "productId", c.product.ID, "err", err)
+		return
+	}
+	var dictionary models.Synonyms
+	if m.dictionary != nil {
+		dictionary = m.dictionary.Synonyms()
+	}
+	for _, search := range candidates {
+		if !search.Matches(c.product, seller, dictionary) {
+			continue
+		}
+		if c.previous != nil && search.Matches(*c.previous, seller, dictionary) {
+			continue
+		}
+		alert := Alert{
+			At:          time.Now().UTC(),
+			BuyerID:     search.BuyerID,
+			SavedSearch: search,
+			Product:     c.product,
+			Restocked:   c.previous != nil,
+		}
+		if err := m.notifier.Notify(ctx, alert); err != nil {
+			m.logger.Errorw("Failed to send a saved search alert", "savedSearchId", search.ID, "productId", c.product.ID, "err", err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+)
+
+// Alert tells a buyer that a product newly matches one of their saved searches
+type Alert struct {
+	At          time.Time          `json:"at"`
+	BuyerID     int                `json:"buyerId"`
+	SavedSearch models.SavedSearch `json:"savedSearch"`
+	Product     models.Product     `json:"product"`
+	// Restocked is set when the stock of the product rose, the product was created otherwise
+	Restocked bool `json:"restocked"`
+}
+
+// Notifier delivers the alerts to the buyers
+type Notifier interface {
+	Notify(ctx context.Context, a Alert) error
+}
+
+// LogNotifier writes the alerts to the log, for local use
+type LogNotifier struct {
+	logger *logging.Logger
+}
+
+// NewLogNotifier creates a LogNotifier writing to the logger
+func NewLogNotifier(logger *logging.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// Notify logs the alert
+func (n *LogNotifier) Notify(_ context.Context, a Alert) error {
+	n.logger.Infow("Saved search alert", "buyerId", a.BuyerID, "savedSearchId", a.SavedSearch.ID,
+		"productId", a.Product.ID, "productName", a.Product.ProductName, "restocked", a.Restocked)
+	return nil
+}
+
+// FileNotifier appends the alerts to a file as JSON lines, for local use
+type FileNotifier struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// OpenFileNotifier opens the file the alerts are appended to, creating it if needed
+func OpenFileNotifier(path string) (*FileNotifier, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileNotifier{file: file}, nil
+}
+
+// Notify appends the alert to the file as one line of JSON
+func (n *FileNotifier) Notify(_ context.Context, a Alert) error {
+	line, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, err = n.file.Write(append(line, '\n'))
+	return err
+}
+
+// Close closes the file
+func (n *FileNotifier) Close() error {
+	return n.file.Close()
+}
+
+// dedupKey identifies the alerts that are duplicates of each other
+type dedupKey struct {
+	buyerID   int
+	productID int
+}
+
+// Deduplicator is a Notifier passing on to another one at most one alert per buyer and product
+// within a window, so a buyer whose saved searches overlap, or a product restocked again and
+// again, does not flood them. Alerts failing to be delivered do not count
+type Deduplicator struct {
+	next   Notifier
+	window time.Duration
+	// now is replaced by the tests
+	now func() time.Time
+
+	mu   sync.Mutex
+	sent map[dedupKey]time.Time
+	// pruned is when the expired keys were last dropped from sent
+	pruned time.Time
+}
+
+// Deduplicate wraps the notifier so it gets at most one alert per buyer and product per window
+func Deduplicate(next Notifier, window time.Duration) *Deduplicator {
+	return &Deduplicator{next: next, window: window, now: time.Now, sent: make(map[dedupKey]time.Time)}
+}
+
+// Notify passes the alert on unless the buyer was alerted about the product within the window
+func (d *Deduplicator) Notify(ctx context.Context, a Alert) error {
+	key := dedupKey{buyerID: a.BuyerID, productID: a.Product.ID}
+	now := d.now()
+	d.mu.Lock()
+	if sent, ok := d.sent[key]; ok && now.Sub(sent) < d.window {
+		d.mu.Unlock()
+		return nil
+	}
+	d.sent[key] = now
+	if now.Sub(d.pruned) >= d.window {
+		d.prune(now)
+	}
+	d.mu.Unlock()
+
+	if err := d.next.Notify(ctx, a); err != nil {
+		d.mu.Lock()
+		if d.sent[key].Equal(now) {
+			delete(d.sent, key)
+		}
+		d.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// prune drops the keys whose window is over. Callers hold the lock
+func (d *Deduplicator) prune(now time.Time) {
+	for key, sent := range d.sent {
+		if now.Sub(sent) >= d.window {
+			delete(d.sent, key)
+		}
+	}
+	d.pruned = now
+}
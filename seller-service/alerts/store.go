@@ -0,0 +1,47 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// WatchingStore is a models.ProductStore handing the products created and restocked through
+// it to a Matcher
+type WatchingStore struct {
+	models.ProductStore
+	matcher *Matcher
+}
+
+// Watch wraps the store so the products created and restocked through it are matched
+func Watch(store models.ProductStore, matcher *Matcher) *WatchingStore {
+	return &WatchingStore{ProductStore: store, matcher: matcher}
+}
+
+// CreateProduct saves the product and queues it for matching
+func (s *WatchingStore) CreateProduct(ctx context.Context, p *models.Product) error {
+	if err := s.ProductStore.CreateProduct(ctx, p); err != nil {
+		return err
+	}
+	s.matcher.Created(*p)
+	return nil
+}
+
+// UpdateProduct applies the update and queues the product for matching when its stock rose
+func (s *WatchingStore) UpdateProduct(ctx context.Context, id int, update models.ProductUpdate) (models.Product, error) {
+	if update.Quantity == nil {
+		return s.ProductStore.UpdateProduct(ctx, id, update)
+	}
+	previous, err := s.ProductStore.GetProductByID(ctx, id)
+	if err != nil {
+		return models.Product{}, err
+	}
+	product, err := s.ProductStore.UpdateProduct(ctx, id, update)
+	if err != nil {
+		return product, err
+	}
+	if product.Quantity > previous.Quantity {
+		s.matcher.Restocked(product, previous)
+	}
+	return product, nil
+}
@@ -0,0 +1,146 @@
+// Package analytics - records the product searches served by the API without slowing them
+// down: the events are queued in memory and written to the store in batches by a goroutine.
+//
+// The queue is bounded, when the store cannot keep up the events that do not fit are dropped
+// and counted rather than making the searches wait.
+package analytics
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+)
+
+const (
+	// maxBatch is the number of events written at once
+	maxBatch = 100
+	// flushInterval is the longest an event waits in the queue for its batch to fill
+	flushInterval = time.Second
+	// writeTimeout bounds the write of a batch
+	writeTimeout = 5 * time.Second
+)
+
+// Recorder writes the search events to the store in the background
+type Recorder struct {
+	store  models.SearchLogStore
+	logger *logging.Logger
+	events chan models.SearchEvent
+	// flush receives a channel closed once the events queued so far are written
+	flush chan chan struct{}
+	done  chan struct{}
+	// dropped counts the events dropped since the last batch written
+	dropped uint64
+}
+
+// NewRecorder starts a Recorder queuing up to bufferSize events, Close stops it
+func NewRecorder(store models.SearchLogStore, bufferSize int, logger *logging.Logger) *Recorder {
+	r := &Recorder{
+		store:  store,
+		logger: logger,
+		events: make(chan models.SearchEvent, bufferSize),
+		flush:  make(chan chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// Record queues the event without waiting, it is dropped when the queue is full. It must not
+// be called after Close
+func (r *Recorder) Record(e models.SearchEvent) {
+	select {
+	case r.events <- e:
+	default:
+		atomic.AddUint64(&r.dropped, 1)
+	}
+}
+
+// Flush returns once the events recorded before it are written
+func (r *Recorder) Flush(ctx context.Context) error {
+	written := make(chan struct{})
+	select {
+	case r.flush <- written:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-written:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close writes the events still queued and stops the recorder, or gives up when ctx is done
+func (r *Recorder) Close(ctx context.Context) error {
+	close(r.events)
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *Recorder) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]models.SearchEvent, 0, maxBatch)
+	for {
+		var written chan struct{}
+		select {
+		case e, ok := <-r.events:
+			if !ok {
+				r.write(batch)
+				return
+			}
+			if batch = append(batch, e); len(batch) < maxBatch {
+				continue
+			}
+		case <-ticker.C:
+		case written = <-r.flush:
+			// the events recorded before the flush are in the queue already
+			batch = r.drain(batch)
+		}
+		r.write(batch)
+		batch = batch[:0]
+		if written != nil {
+			close(written)
+		}
+	}
+}
+
+// drain appends the events waiting in the queue to the batch
+func (r *Recorder) drain(batch []models.SearchEvent) []models.SearchEvent {
+	for {
+		select {
+		case e, ok := <-r.events:
+			if !ok {
+				return batch
+			}
+			batch = append(batch, e)
+		default:
+			return batch
+		}
+	}
+}
+
+// write saves the batch, the events are lost when the store fails
+func (r *Recorder) write(batch []models.SearchEvent) {
+	if dropped := atomic.SwapUint64(&r.dropped, 0); dropped > 0 {
+		r.logger.Warnw("Search events dropped, the queue is full", "dropped", dropped)
+	}
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+	if err := r.store.RecordSearches(ctx, batch); err != nil {
+		r.logger.Errorw("Failed to record search events", "events", len(batch), "err", err)
+	}
+}
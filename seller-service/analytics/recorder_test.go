@@ -0,0 +1,101 @@
+package analytics
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+)
+
+// fakeLog records the batches written, each write waits for release when it is set
+type fakeLog struct {
+	models.SearchLogStore
+	mu      sync.Mutex
+	batches [][]models.SearchEvent
+	release chan struct{}
+}
+
+func (f *fakeLog) RecordSearches(_ context.Context, events []models.SearchEvent) error {
+	if f.release != nil {
+		<-f.release
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batches = append(f.batches, append([]models.SearchEvent(nil), events...))
+	return nil
+}
+
+func (f *fakeLog) recorded() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, batch := range f.batches {
+		n += len(batch)
+	}
+	return n
+}
+
+func newTestLogger() *logging.Logger {
+	return logging.New(logging.Config{Output: io.Discard})
+}
+
+func TestRecorder_FlushAndClose(t *testing.T) {
+	store := &fakeLog{}
+	r := NewRecorder(store, 1000, newTestLogger())
+	for i := 0; i < 250; i++ {
+		r.Record(models.SearchEvent{Query: "drone", At: time.Now()})
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if n := store.recorded(); n != 250 {
+		t.Errorf("Expected 250 events written after the flush, got %d", n)
+	}
+
+	r.Record(models.SearchEvent{Query: "camera", At: time.Now()})
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if n := store.recorded(); n != 251 {
+		t.Errorf("Expected the queued event to be written on close, got %d events", n)
+	}
+}
+
+func TestRecorder_DropsWhenFull(t *testing.T) {
+	store := &fakeLog{release: make(chan struct{})}
+	r := NewRecorder(store, 2, newTestLogger())
+	// the first flush holds the writer in the store, the queue then fills up
+	r.Record(models.SearchEvent{Query: "first"})
+	flushed := make(chan error)
+	go func() { flushed <- r.Flush(context.Background()) }()
+	for len(r.events) > 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			r.Record(models.SearchEvent{Query: "more"})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a full queue")
+	}
+	close(store.release)
+	if err := <-flushed; err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if err := r.Close(context.Background()); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if n := store.recorded(); n != 3 {
+		t.Errorf("Expected the first event and the 2 queued ones, got %d", n)
+	}
+}
@@ -0,0 +1,429 @@
+// Package config - loads and validates the configuration of the service.
+//
+// Every setting can come from an optional YAML/JSON file, an environment variable
+// or a command line flag. When a setting is given more than once the flag wins over
+// the environment, which wins over the file, which wins over the default.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces the value of secret settings when the config is printed
+const redacted = "******"
+
+// Config is the complete configuration of the service
+type Config struct {
+	File        string
+	ServiceName string
+	DevMode     bool
+	LogLevel    string
+	LogFormat   string
+	SyslogAddr  string
+	StoreDriver string
+	SchemaCheck string
+	HTTP        HTTP
+	MySQL       MySQL
+	// AdminToken is the bearer token of the /api/v1/admin endpoints, they are disabled without it
+	AdminToken string
+	// SearchEventBuffer is the number of search events queued for the analytics before new
+	// ones are dropped
+	SearchEventBuffer int
+	Alerts            Alerts
+}
+
+// Alerts configures the alerts of the saved searches
+type Alerts struct {
+	// Notifier delivers the alerts: log writes them to the log, file appends them to File
+	Notifier string
+	File     string
+	// DedupWindow is the time during which a buyer is alerted about a product at most once
+	DedupWindow time.Duration
+	// Buffer is the number of products queued for matching before new ones are dropped
+	Buffer int
+}
+
+// HTTP configures the http server
+type HTTP struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	RequestTimeout    time.Duration
+	ShutdownTimeout   time.Duration
+}
+
+// MySQL configures the connection and the connection pool to the database
+type MySQL struct {
+	User            string
+	Password        string
+	Host            string
+	Port            string
+	Database        string
+	Params          string // extra DSN parameters in query string form, e.g. charset=utf8mb4&loc=UTC
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// Default returns the configuration used for every setting that is not given
+func Default() *Config {
+	return &Config{
+		ServiceName: "seller-service",
+		LogLevel:    "debug",
+		LogFormat:   "logfmt",
+		SyslogAddr:  "localhost:514",
+		StoreDriver: "mysql",
+		SchemaCheck: "fail",
+		HTTP: HTTP{
+			Addr:              ":8080",
+			ReadTimeout:       10 * time.Second,
+			ReadHeaderTimeout: 5 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       2 * time.Minute,
+			RequestTimeout:    15 * time.Second,
+			ShutdownTimeout:   5 * time.Second,
+		},
+		MySQL: MySQL{
+			Port:            "3306",
+			MaxOpenConns:    25,
+			MaxIdleConns:    25,
+			ConnMaxLifetime: 5 * time.Minute,
+			ConnMaxIdleTime: time.Minute,
+		},
+		SearchEventBuffer: 10000,
+		Alerts: Alerts{
+			Notifier:    "log",
+			File:        "alerts.jsonl",
+			DedupWindow: 24 * time.Hour,
+			Buffer:      1000,
+		},
+	}
+}
+
+// setting binds one field of the Config to its key, which is both the flag name and
+// the dotted path in the config file, and to its environment variable
+type setting struct {
+	key    string
+	env    string
+	usage  string
+	secret bool
+	ptr    interface{}
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{key: "service.name", env: "SERVICE_NAME", usage: "Name of service", ptr: &c.ServiceName},
+		{key: "dev.mode", env: "ENABLE_DEV_MODE", usage: "log to stdout instead of syslog", ptr: &c.DevMode},
+		{key: "log.level", env: "LOG_LEVEL", usage: "minimum severity logged: debug, info, warn or error", ptr: &c.LogLevel},
+		{key: "log.format", env: "LOG_FORMAT", usage: "format of the log records: logfmt or json", ptr: &c.LogFormat},
+		{key: "syslog.address", env: "SYSLOG_ADDRESS", usage: "default location for the syslogger", ptr: &c.SyslogAddr},
+		{key: "store.driver", env: "STORE_DRIVER", usage: "datastore backing the service: mysql or memory", ptr: &c.StoreDriver},
+		{key: "schema.check", env: "SCHEMA_CHECK", usage: "what to do when the database schema is behind the binary: fail, warn or migrate", ptr: &c.SchemaCheck},
+		{key: "http.addr", env: "HTTP_ADDR", usage: "address the http server listens on", ptr: &c.HTTP.Addr},
+		{key: "http.readTimeout", env: "HTTP_READ_TIMEOUT", usage: "maximum duration for reading a request, including the body", ptr: &c.HTTP.ReadTimeout},
+		{key: "http.readHeaderTimeout", env: "HTTP_READ_HEADER_TIMEOUT", usage: "maximum duration for reading the request headers", ptr: &c.HTTP.ReadHeaderTimeout},
+		{key: "http.writeTimeout", env: "HTTP_WRITE_TIMEOUT", usage: "maximum duration before timing out writes of the response", ptr: &c.HTTP.WriteTimeout},
+		{key: "http.idleTimeout", env: "HTTP_IDLE_TIMEOUT", usage: "maximum time to wait for the next request on a keep-alive connection", ptr: &c.HTTP.IdleTimeout},
+		{key: "http.requestTimeout", env: "HTTP_REQUEST_TIMEOUT", usage: "time a handler has to respond before the request fails with 503, 0 disables it", ptr: &c.HTTP.RequestTimeout},
+		{key: "http.shutdownTimeout", env: "HTTP_SHUTDOWN_TIMEOUT", usage: "time given to in-flight requests to complete on shutdown", ptr: &c.HTTP.ShutdownTimeout},
+		{key: "mysql.user", env: "MYSQL_USER", usage: "mysql user", ptr: &c.MySQL.User},
+		{key: "mysql.password", env: "MYSQL_PASSWORD", usage: "mysql password", secret: true, ptr: &c.MySQL.Password},
+		{key: "mysql.host", env: "MYSQL_HOSTNAME", usage: "mysql host name", ptr: &c.MySQL.Host},
+		{key: "mysql.port", env: "MYSQL_PORT", usage: "mysql port", ptr: &c.MySQL.Port},
+		{key: "mysql.database", env: "MYSQL_DATABASE", usage: "mysql database name", ptr: &c.MySQL.Database},
+		{key: "mysql.params", env: "MYSQL_PARAMETER", usage: "extra DSN parameters, e.g. charset=utf8mb4&loc=UTC", ptr: &c.MySQL.Params},
+		{key: "mysql.maxOpenConns", env: "MYSQL_MAX_OPEN_CONNS", usage: "maximum number of open connections, 0 is unlimited", ptr: &c.MySQL.MaxOpenConns},
+		{key: "mysql.maxIdleConns", env: "MYSQL_MAX_IDLE_CONNS", usage: "maximum number of idle connections", ptr: &c.MySQL.MaxIdleConns},
+		{key: "mysql.connMaxLifetime", env: "MYSQL_CONN_MAX_LIFETIME", usage: "maximum time a connection is reused, 0 is forever", ptr: &c.MySQL.ConnMaxLifetime},
+		{key: "mysql.connMaxIdleTime", env: "MYSQL_CONN_MAX_IDLE_TIME", usage: "maximum time a connection stays idle, 0 is forever", ptr: &c.MySQL.ConnMaxIdleTime},
+		{key: "admin.token", env: "ADMIN_TOKEN", usage: "bearer token of the admin endpoints, they are disabled when empty", secret: true, ptr: &c.AdminToken},
+		{key: "analytics.searchEventBuffer", env: "ANALYTICS_SEARCH_EVENT_BUFFER", usage: "number of search events queued for writing before new ones are dropped", ptr: &c.SearchEventBuffer},
+		{key: "alerts.notifier", env: "ALERTS_NOTIFIER", usage: "how saved search alerts are delivered: log or file", ptr: &c.Alerts.Notifier},
+		{key: "alerts.file", env: "ALERTS_FILE", usage: "file the file notifier appends the alerts to as JSON lines", ptr: &c.Alerts.File},
+		{key: "alerts.dedupWindow", env: "ALERTS_DEDUP_WINDOW", usage: "time during which a buyer is alerted about a product at most once", ptr: &c.Alerts.DedupWindow},
+		{key: "alerts.buffer", env: "ALERTS_BUFFER", usage: "number of products queued for matching against the saved searches before new ones are dropped", ptr: &c.Alerts.Buffer},
+	}
+}
+
+func (s setting) set(value string) error {
+	switch p := s.ptr.(type) {
+	case *string:
+		*p = value
+	case *bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a boolean", s.key, value)
+		}
+		*p = v
+	case *int:
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not an integer", s.key, value)
+		}
+		*p = v
+	case *time.Duration:
+		v, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%s: %q is not a duration", s.key, value)
+		}
+		*p = v
+	}
+	return nil
+}
+
+func (s setting) String() string {
+	if s.ptr == nil {
+		return ""
+	}
+	switch p := s.ptr.(type) {
+	case *string:
+		return *p
+	case *bool:
+		return strconv.FormatBool(*p)
+	case *int:
+		return strconv.Itoa(*p)
+	case *time.Duration:
+		return p.String()
+	}
+	return ""
+}
+
+// flagValue records the flags given on the command line, they are applied last
+type flagValue struct {
+	setting
+	given map[string]string
+}
+
+func (f *flagValue) Set(value string) error {
+	f.given[f.key] = value
+	return nil
+}
+
+func (f *flagValue) IsBoolFlag() bool {
+	_, ok := f.ptr.(*bool)
+	return ok
+}
+
+// Load builds the configuration from the defaults, the config file, the environment and
+// the command line arguments, in increasing order of precedence. The flags are registered
+// on fs, the caller reads the remaining arguments from it
+func Load(fs *flag.FlagSet, args []string, getenv func(string) string) (*Config, error) {
+	cfg := Default()
+	settings := cfg.settings()
+
+	given := make(map[string]string)
+	fs.StringVar(&cfg.File, "config", getenv("CONFIG_FILE"), "path to an optional YAML or JSON config file")
+	for _, s := range settings {
+		fs.Var(&flagValue{setting: s, given: given}, s.key, fmt.Sprintf("%s (env %s)", s.usage, s.env))
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	if cfg.File != "" {
+		values, err := readFile(cfg.File)
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.apply(values); err != nil {
+			return nil, fmt.Errorf("config file %s: %w", cfg.File, err)
+		}
+	}
+	env := make(map[string]string)
+	for _, s := range settings {
+		if v := getenv(s.env); v != "" {
+			env[s.key] = v
+		}
+	}
+	if err := cfg.apply(env); err != nil {
+		return nil, fmt.Errorf("environment: %w", err)
+	}
+	if err := cfg.apply(given); err != nil {
+		return nil, fmt.Errorf("flags: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// apply sets the settings present in values, keyed by setting key
+func (c *Config) apply(values map[string]string) error {
+	var errs []error
+	known := make(map[string]bool)
+	for _, s := range c.settings() {
+		known[s.key] = true
+		if v, ok := values[s.key]; ok {
+			if err := s.set(v); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	for key := range values {
+		if !known[key] {
+			errs = append(errs, fmt.Errorf("unknown setting %q", key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// readFile reads a YAML or JSON config file into a map keyed by dotted setting key
+func readFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tree map[string]interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &tree)
+	case ".json":
+		err = json.Unmarshal(data, &tree)
+	default:
+		return nil, fmt.Errorf("config file %s: unsupported extension, use .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %w", path, err)
+	}
+	values := make(map[string]string)
+	flatten("", tree, values)
+	return values, nil
+}
+
+func flatten(prefix string, tree map[string]interface{}, values map[string]string) {
+	for key, v := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := v.(type) {
+		case map[string]interface{}:
+			flatten(key, v, values)
+		case float64:
+			// json numbers are floats, fmt would write 1000000 as 1e+06 and fail the int parse
+			values[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			values[key] = fmt.Sprint(v)
+		}
+	}
+}
+
+// Validate checks every setting and returns all the problems found at once
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.ServiceName != "", "service.name must not be empty")
+	check(oneOf(c.LogLevel, "debug", "info", "warn", "error"), "log.level must be one of debug, info, warn or error, got %q", c.LogLevel)
+	check(oneOf(c.LogFormat, "logfmt", "json"), "log.format must be logfmt or json, got %q", c.LogFormat)
+	check(c.DevMode || c.SyslogAddr != "", "syslog.address must be set unless dev.mode is enabled")
+	check(oneOf(c.StoreDriver, "mysql", "memory"), "store.driver must be mysql or memory, got %q", c.StoreDriver)
+	check(oneOf(c.SchemaCheck, "fail", "warn", "migrate"), "schema.check must be one of fail, warn or migrate, got %q", c.SchemaCheck)
+
+	_, port, err := net.SplitHostPort(c.HTTP.Addr)
+	check(err == nil && (port == "0" || validPort(port)), "http.addr must be a host:port address, got %q", c.HTTP.Addr)
+	check(c.HTTP.ReadTimeout >= 0, "http.readTimeout must not be negative")
+	check(c.HTTP.ReadHeaderTimeout >= 0, "http.readHeaderTimeout must not be negative")
+	check(c.HTTP.WriteTimeout >= 0, "http.writeTimeout must not be negative")
+	check(c.HTTP.IdleTimeout >= 0, "http.idleTimeout must not be negative")
+	check(c.HTTP.RequestTimeout >= 0, "http.requestTimeout must not be negative")
+	check(c.HTTP.ShutdownTimeout > 0, "http.shutdownTimeout must be positive")
+
+	if c.StoreDriver == "mysql" {
+		check(c.MySQL.User != "", "mysql.user must not be empty")
+		check(c.MySQL.Host != "", "mysql.host must not be empty")
+		check(c.MySQL.Database != "", "mysql.database must not be empty")
+		check(validPort(c.MySQL.Port), "mysql.port must be a port number, got %q", c.MySQL.Port)
+		_, err := url.ParseQuery(c.MySQL.Params)
+		check(err == nil, "mysql.params must be in key=value&key=value form: %v", err)
+		if err == nil && validPort(c.MySQL.Port) {
+			_, err = mysql.ParseDSN(c.MySQL.DSN())
+			check(err == nil, "mysql.params: %v", err)
+		}
+	}
+	check(c.MySQL.MaxOpenConns >= 0, "mysql.maxOpenConns must not be negative")
+	check(c.MySQL.MaxIdleConns >= 0, "mysql.maxIdleConns must not be negative")
+	check(c.MySQL.MaxOpenConns == 0 || c.MySQL.MaxIdleConns <= c.MySQL.MaxOpenConns,
+		"mysql.maxIdleConns (%d) must not exceed mysql.maxOpenConns (%d)", c.MySQL.MaxIdleConns, c.MySQL.MaxOpenConns)
+	check(c.MySQL.ConnMaxLifetime >= 0, "mysql.connMaxLifetime must not be negative")
+	check(c.MySQL.ConnMaxIdleTime >= 0, "mysql.connMaxIdleTime must not be negative")
+	check(c.SearchEventBuffer > 0, "analytics.searchEventBuffer must be positive")
+	check(oneOf(c.Alerts.Notifier, "log", "file"), "alerts.notifier must be log or file, got %q", c.Alerts.Notifier)
+	check(c.Alerts.Notifier != "file" || c.Alerts.File != "", "alerts.file must be set when alerts.notifier is file")
+	check(c.Alerts.DedupWindow >= 0, "alerts.dedupWindow must not be negative")
+	check(c.Alerts.Buffer > 0, "alerts.buffer must be positive")
+
+	return errors.Join(errs...)
+}
+
+// DSN is the data source name used to open the mysql connection, times are always parsed into time.Time
+func (m MySQL) DSN() string {
+	params, _ := url.ParseQuery(m.Params)
+	params.Set("parseTime", "true")
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s?%s", m.User, m.Password, net.JoinHostPort(m.Host, m.Port), m.Database, params.Encode())
+}
+
+// KeyValues returns the effective configuration as key/value pairs sorted by key,
+// with the secrets redacted
+func (c *Config) KeyValues() []interface{} {
+	settings := c.settings()
+	sort.Slice(settings, func(i, j int) bool { return settings[i].key < settings[j].key })
+	var keyvals []interface{}
+	if c.File != "" {
+		keyvals = append(keyvals, "config", c.File)
+	}
+	for _, s := range settings {
+		value := s.String()
+		if s.secret && value != "" {
+			value = redacted
+		}
+		keyvals = append(keyvals, s.key, value)
+	}
+	return keyvals
+}
+
+// Print writes the effective configuration as key = value lines with the secrets redacted
+func (c *Config) Print(w io.Writer) error {
+	keyvals := c.KeyValues()
+	for i := 0; i < len(keyvals); i += 2 {
+		if _, err := fmt.Fprintf(w, "%s = %s\n", keyvals[i], keyvals[i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func oneOf(value string, allowed ...string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+func validPort(port string) bool {
+	p, err := strconv.Atoi(port)
+	return err == nil && p > 0 && p <= 65535
+}
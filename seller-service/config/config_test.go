@@ -0,0 +1,155 @@
+package config
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func env(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func load(t *testing.T, args []string, vars map[string]string) (*Config, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(&strings.Builder{})
+	return Load(fs, args, env(vars))
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+var mysqlEnv = map[string]string{
+	"MYSQL_USER":     "user",
+	"MYSQL_PASSWORD": "password",
+	"MYSQL_HOSTNAME": "db",
+	"MYSQL_DATABASE": "db",
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	cfg, err := load(t, nil, mysqlEnv)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.HTTP.Addr != ":8080" || cfg.HTTP.ShutdownTimeout != 5*time.Second || cfg.MySQL.Port != "3306" {
+		t.Errorf("Unexpected defaults: %+v", cfg)
+	}
+}
+
+func TestLoad_Precedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+http:
+  addr: ":7070"
+  shutdownTimeout: 10s
+mysql:
+  user: file-user
+  host: file-host
+  database: db
+  maxOpenConns: 10
+  maxIdleConns: 5
+`)
+	vars := map[string]string{"HTTP_ADDR": ":6060", "MYSQL_USER": "env-user"}
+	cfg, err := load(t, []string{"-config", file, "-mysql.user=flag-user", "migrate", "up"}, vars)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.MySQL.User != "flag-user" {
+		t.Errorf("Expected the flag to win over env and file, got %q", cfg.MySQL.User)
+	}
+	if cfg.HTTP.Addr != ":6060" {
+		t.Errorf("Expected the env to win over the file, got %q", cfg.HTTP.Addr)
+	}
+	if cfg.HTTP.ShutdownTimeout != 10*time.Second || cfg.MySQL.Host != "file-host" || cfg.MySQL.MaxOpenConns != 10 {
+		t.Errorf("Expected the file to win over the defaults, got %+v", cfg)
+	}
+}
+
+func TestLoad_JSONFile(t *testing.T) {
+	file := writeFile(t, "config.json", `{"store": {"driver": "memory"}, "dev": {"mode": true}, "analytics": {"searchEventBuffer": 1000000}}`)
+	cfg, err := load(t, nil, map[string]string{"CONFIG_FILE": file})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.StoreDriver != "memory" || !cfg.DevMode || cfg.SearchEventBuffer != 1000000 {
+		t.Errorf("Unexpected config: %+v", cfg)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]struct {
+		args []string
+		file string
+	}{
+		"unknown file setting":  {file: "http:\n  port: 80\n"},
+		"bad duration":          {args: []string{"-http.readTimeout=soon"}},
+		"bad address":           {args: []string{"-http.addr=8080"}},
+		"bad driver":            {args: []string{"-store.driver=postgres"}},
+		"bad log level":         {args: []string{"-log.level=trace"}},
+		"idle above open":       {args: []string{"-mysql.maxOpenConns=5", "-mysql.maxIdleConns=10"}},
+		"bad dsn params":        {args: []string{"-mysql.params=timeout=soon"}},
+		"zero shutdown":         {args: []string{"-http.shutdownTimeout=0s"}},
+		"bad notifier":          {args: []string{"-alerts.notifier=email"}},
+		"file notifier no file": {args: []string{"-alerts.notifier=file", "-alerts.file="}},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "config.yaml", tt.file)}, args...)
+			}
+			if _, err := load(t, args, mysqlEnv); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsAllErrors(t *testing.T) {
+	_, err := load(t, []string{"-store.driver=mysql"}, nil)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	for _, setting := range []string{"mysql.user", "mysql.host", "mysql.database"} {
+		if !strings.Contains(err.Error(), setting) {
+			t.Errorf("Expected %s to be reported, got %v", setting, err)
+		}
+	}
+}
+
+func TestMySQL_DSN(t *testing.T) {
+	cfg, err := load(t, []string{"-mysql.params=charset=utf8mb4&loc=UTC"}, mysqlEnv)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	want := "user:password@tcp(db:3306)/db?charset=utf8mb4&loc=UTC&parseTime=true"
+	if dsn := cfg.MySQL.DSN(); dsn != want {
+		t.Errorf("Expected %s, got %s", want, dsn)
+	}
+}
+
+func TestPrint_RedactsSecrets(t *testing.T) {
+	cfg, err := load(t, nil, mysqlEnv)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	var out strings.Builder
+	if err := cfg.Print(&out); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out.String(), "password = password") || !strings.Contains(out.String(), "mysql.password = "+redacted) {
+		t.Errorf("Expected the password to be redacted:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "mysql.user = user") {
+		t.Errorf("Expected the user to be printed:\n%s", out.String())
+	}
+}
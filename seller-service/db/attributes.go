@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// queryer is implemented by *sql.DB and *sql.Tx
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// saveAttributes replaces the attributes of the product, numbers are also saved in value_number
+func saveAttributes(ctx context.Context, q queryer, productID int, attributes map[string]string) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM product_attributes WHERE product_id = ?`, productID); err != nil {
+		return err
+	}
+	if len(attributes) == 0 {
+		return nil
+	}
+	var values []string
+	var args []interface{}
+	for name, value := range attributes {
+		var number interface{}
+		if n, ok := models.AttributeNumber(value); ok {
+			number = n
+		}
+		values = append(values, "(?, ?, ?, ?)")
+		args = append(args, productID, name, value, number)
+	}
+	_, err := q.ExecContext(ctx, `INSERT INTO product_attributes (product_id, name, value, value_number) VALUES `+strings.Join(values, ", "), args...)
+	return err
+}
+
+// saveTags replaces the tags of the product
+func saveTags(ctx context.Context, q queryer, productID int, tags []string) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM product_tags WHERE product_id = ?`, productID); err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return nil
+	}
+	var values []string
+	var args []interface{}
+	for _, tag := range tags {
+		values = append(values, "(?, ?)")
+		args = append(args, productID, tag)
+	}
+	_, err := q.ExecContext(ctx, `INSERT INTO product_tags (product_id, tag) VALUES `+strings.Join(values, ", "), args...)
+	return err
+}
+
+// loadAttributes reads the attributes and tags of the products in two queries, whatever the
+// number of products
+func loadAttributes(ctx context.Context, q queryer, products []models.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	index := make(map[int]int, len(products))
+	args := make([]interface{}, len(products))
+	for i, p := range products {
+		index[p.ID] = i
+		args[i] = p.ID
+	}
+	in := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(products)), ", ") + ")"
+
+	rows, err := q.QueryContext(ctx, `SELECT product_id, name, value FROM product_attributes WHERE product_id IN `+in, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var name, value string
+		if err := rows.Scan(&id, &name, &value); err != nil {
+			return err
+		}
+		p := &products[index[id]]
+		if p.Attributes == nil {
+			p.Attributes = make(map[string]string)
+		}
+		p.Attributes[name] = value
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = q.QueryContext(ctx, `SELECT product_id, tag FROM product_tags WHERE product_id IN `+in+` ORDER BY tag`, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		var tag string
+		if err := rows.Scan(&id, &tag); err != nil {
+			return err
+		}
+		products[index[id]].Tags = append(products[index[id]].Tags, tag)
+	}
+	return rows.Err()
+}
+
+// attributeFilters frames the conditions of the attribute and tag filters of the request
+func attributeFilters(p *models.ProductRequest) (string, []interface{}) {
+	var query string
+	var args []interface{}
+	for _, f := range p.Attributes {
+		exists := "EXISTS (SELECT 1 FROM product_attributes AS a WHERE a.product_id = p.id AND a.name = ? AND "
+		switch f.Op {
+		case "=":
+			query += " AND " + exists + "a.value = ?)"
+			args = append(args, f.Name, f.Value)
+		case "!=":
+			query += " AND NOT " + exists + "a.value = ?)"
+			args = append(args, f.Name, f.Value)
+		default:
+			// the operator is one of models.AttributeOps, checked by the validation
+			value, _ := strconv.ParseFloat(f.Value, 64)
+			query += " AND " + exists + "a.value_number " + f.Op + " ?)"
+			args = append(args, f.Name, value)
+		}
+	}
+	for _, tag := range p.Tags {
+		query += " AND EXISTS (SELECT 1 FROM product_tags AS t WHERE t.product_id = p.id AND t.tag = ?)"
+		args = append(args, tag)
+	}
+	return query, args
+}
+
+// ListAttributes counts the products having each value of each attribute
+func (s *Store) ListAttributes(ctx context.Context) ([]models.AttributeSummary, error) {
+	rows, err := s.conn.QueryContext(ctx, `SELECT name, value, COUNT(*) FROM product_attributes GROUP BY name, BINARY value`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	counts := make(map[string]map[string]int)
+	for rows.Next() {
+		var name, value string
+		var count int
+		if err := rows.Scan(&name, &value, &count); err != nil {
+			return nil, err
+		}
+		if counts[name] == nil {
+			counts[name] = make(map[string]int)
+		}
+		counts[name][value] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return models.SummarizeAttributes(counts), nil
+}
@@ -0,0 +1,268 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// buyerColumns are the columns scanned by scanBuyer
+const buyerColumns = `id, name, email, phone, created_at`
+
+func scanBuyer(row rowScanner) (models.Buyer, error) {
+	var b models.Buyer
+	err := row.Scan(&b.ID, &b.Name, &b.Email, &b.Phone, &b.CreatedAt)
+	return b, err
+}
+
+// buyerAddressColumns are the columns scanned by scanBuyerAddress
+const buyerAddressColumns = `id, buyer_id, label, recipient, line1, line2, city, region, postal_code, country, phone, is_default`
+
+func scanBuyerAddress(row rowScanner) (models.BuyerAddress, error) {
+	var a models.BuyerAddress
+	err := row.Scan(&a.ID, &a.BuyerID, &a.Label, &a.Recipient, &a.Line1, &a.Line2, &a.City, &a.Region, &a.PostalCode, &a.Country, &a.Phone, &a.Default)
+	return a, err
+}
+
+// CreateBuyer saves the buyer and sets its ID and CreatedAt, the unique index on the email
+// refuses a taken one
+func (s *Store) CreateBuyer(ctx context.Context, b *models.Buyer) error {
+	b.Normalize()
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+	result, err := s.conn.ExecContext(ctx, `INSERT INTO buyers (name, email, phone, created_at) VALUES (?, ?, ?, ?)`,
+		b.Name, b.Email, b.Phone, createdAt)
+	if isDuplicateEntry(err) {
+		return models.ErrBuyerEmailTaken
+	}
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	b.ID, b.CreatedAt = int(id), createdAt
+	return nil
+}
+
+// GetBuyerByID retrieves a buyer by ID, returns models.ErrBuyerNotFound if it does not exist
+func (s *Store) GetBuyerByID(ctx context.Context, id int) (models.Buyer, error) {
+	b, err := scanBuyer(s.conn.QueryRowContext(ctx, `SELECT `+buyerColumns+` FROM buyers WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return b, models.ErrBuyerNotFound
+	}
+	return b, err
+}
+
+// UpdateBuyer locks the buyer row, applies the update and saves it in a transaction
+func (s *Store) UpdateBuyer(ctx context.Context, id int, update models.BuyerUpdate) (models.Buyer, error) {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Buyer{}, err
+	}
+	defer tx.Rollback()
+
+	b, err := scanBuyer(tx.QueryRowContext(ctx, `SELECT `+buyerColumns+` FROM buyers WHERE id = ? FOR UPDATE`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return b, models.ErrBuyerNotFound
+	}
+	if err != nil {
+		return b, err
+	}
+
+	update.Apply(&b)
+	_, err = tx.ExecContext(ctx, `UPDATE buyers SET name = ?, email = ?, phone = ? WHERE id = ?`, b.Name, b.Email, b.Phone, id)
+	if isDuplicateEntry(err) {
+		return models.Buyer{}, models.ErrBuyerEmailTaken
+	}
+	if err != nil {
+		return b, err
+	}
+	return b, tx.Commit()
+}
+
+// DeleteBuyer deletes the buyer and its saved searches in a transaction, its addresses and
+// cart items are deleted by the foreign keys
+func (s *Store) DeleteBuyer(ctx context.Context, id int) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM buyers WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrBuyerNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM saved_searches WHERE buyer_id = ?`, id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListBuyerAddresses returns the address book of the buyer ordered by ID
+func (s *Store) ListBuyerAddresses(ctx context.Context, buyerID int) ([]models.BuyerAddress, error) {
+	if _, err := s.GetBuyerByID(ctx, buyerID); err != nil {
+		return nil, err
+	}
+	return listBuyerAddresses(ctx, s.conn, buyerID)
+}
+
+// listBuyerAddresses reads the addresses of the buyer ordered by ID
+func listBuyerAddresses(ctx context.Context, q queryer, buyerID int) ([]models.BuyerAddress, error) {
+	rows, err := q.QueryContext(ctx, `SELECT `+buyerAddressColumns+` FROM buyer_addresses WHERE buyer_id = ? ORDER BY id`, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := []models.BuyerAddress{}
+	for rows.Next() {
+		a, err := scanBuyerAddress(rows)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, a)
+	}
+	return addresses, rows.Err()
+}
+
+// lockBuyer locks the buyer row so the changes to its address book are serialized, returns
+// models.ErrBuyerNotFound if it does not exist
+func lockBuyer(ctx context.Context, tx *sql.Tx, id int) error {
+	var found int
+	err := tx.QueryRowContext(ctx, `SELECT id FROM buyers WHERE id = ? FOR UPDATE`, id).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrBuyerNotFound
+	}
+	return err
+}
+
+// CreateBuyerAddress locks the buyer, checks its address book has room and adds the address
+// in a transaction
+func (s *Store) CreateBuyerAddress(ctx context.Context, a *models.BuyerAddress) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockBuyer(ctx, tx, a.BuyerID); err != nil {
+		return err
+	}
+	var count int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM buyer_addresses WHERE buyer_id = ?`, a.BuyerID).Scan(&count); err != nil {
+		return err
+	}
+	if count >= models.MaxBuyerAddresses {
+		return models.ErrTooManyBuyerAddresses
+	}
+	a.Normalize()
+	a.Default = a.Default || count == 0
+	if err := clearDefaultAddress(ctx, tx, *a); err != nil {
+		return err
+	}
+	result, err := tx.ExecContext(ctx, `INSERT INTO buyer_addresses
+		(buyer_id, label, recipient, line1, line2, city, region, postal_code, country, phone, is_default)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		a.BuyerID, a.Label, a.Recipient, a.Line1, a.Line2, a.City, a.Region, a.PostalCode, a.Country, a.Phone, a.Default)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	a.ID = int(id)
+	return nil
+}
+
+// UpdateBuyerAddress locks the buyer and replaces the address in a transaction
+func (s *Store) UpdateBuyerAddress(ctx context.Context, a *models.BuyerAddress) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockBuyer(ctx, tx, a.BuyerID); errors.Is(err, models.ErrBuyerNotFound) {
+		return models.ErrBuyerAddressNotFound
+	} else if err != nil {
+		return err
+	}
+	var wasDefault bool
+	err = tx.QueryRowContext(ctx, `SELECT is_default FROM buyer_addresses WHERE id = ? AND buyer_id = ?`, a.ID, a.BuyerID).Scan(&wasDefault)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrBuyerAddressNotFound
+	}
+	if err != nil {
+		return err
+	}
+	a.Normalize()
+	a.Default = a.Default || wasDefault
+	if err := clearDefaultAddress(ctx, tx, *a); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE buyer_addresses SET label = ?, recipient = ?, line1 = ?, line2 = ?, city = ?,
+		region = ?, postal_code = ?, country = ?, phone = ?, is_default = ? WHERE id = ?`,
+		a.Label, a.Recipient, a.Line1, a.Line2, a.City, a.Region, a.PostalCode, a.Country, a.Phone, a.Default, a.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// clearDefaultAddress takes the default off the other addresses of the buyer when the address
+// is the default one
+func clearDefaultAddress(ctx context.Context, tx *sql.Tx, a models.BuyerAddress) error {
+	if !a.Default {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `UPDATE buyer_addresses SET is_default = 0 WHERE buyer_id = ? AND id <> ? AND is_default = 1`, a.BuyerID, a.ID)
+	return err
+}
+
+// DeleteBuyerAddress locks the buyer, deletes the address and moves the default to the oldest
+// remaining address when it was the default one, in a transaction
+func (s *Store) DeleteBuyerAddress(ctx context.Context, buyerID, id int) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockBuyer(ctx, tx, buyerID); errors.Is(err, models.ErrBuyerNotFound) {
+		return models.ErrBuyerAddressNotFound
+	} else if err != nil {
+		return err
+	}
+	var wasDefault bool
+	err = tx.QueryRowContext(ctx, `SELECT is_default FROM buyer_addresses WHERE id = ? AND buyer_id = ?`, id, buyerID).Scan(&wasDefault)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrBuyerAddressNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM buyer_addresses WHERE id = ?`, id); err != nil {
+		return err
+	}
+	if wasDefault {
+		_, err = tx.ExecContext(ctx, `UPDATE buyer_addresses SET is_default = 1 WHERE buyer_id = ? ORDER BY id LIMIT 1`, buyerID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,157 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// CartItems returns the items of the cart of the buyer in the order they were added, joined to
+// their products and sellers
+func (s *Store) CartItems(ctx context.Context, buyerID int) ([]models.CartItem, error) {
+	if _, err := s.GetBuyerByID(ctx, buyerID); err != nil {
+		return nil, err
+	}
+	rows, err := s.conn.QueryContext(ctx, `SELECT c.quantity, c.added_price, c.added_stock, c.added_at, `+productColumns+`, `+sellerColumns+`
+		FROM cart_items AS c
+		INNER JOIN products AS p ON p.id = c.product_id
+		INNER JOIN sellers AS s ON s.id = p.seller_id
+		WHERE c.buyer_id = ? ORDER BY c.added_at, c.product_id`, buyerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.CartItem{}
+	for rows.Next() {
+		item := models.CartItem{BuyerID: buyerID}
+		var seller sellerScan
+		p := &item.Product
+		dest := []interface{}{&item.Quantity, &item.AddedPrice, &item.AddedStock, &item.AddedAt,
+			&p.ID, &p.SellerID, &p.ProductName, &p.Price, &p.Quantity, &p.Description, &p.CategoryID}
+		if err := rows.Scan(append(dest, seller.dest()...)...); err != nil {
+			return nil, err
+		}
+		result := seller.result()
+		p.Seller = &result
+		item.ProductID = p.ID
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
+
+// AddCartItem locks the buyer so the changes to its cart are serialized, checks the cart has
+// room and the product enough stock and saves the item in a transaction
+func (s *Store) AddCartItem(ctx context.Context, item *models.CartItem) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := lockBuyer(ctx, tx, item.BuyerID); err != nil {
+		return err
+	}
+	p, err := scanProduct(tx.QueryRowContext(ctx, `SELECT `+productColumns+` FROM products AS p WHERE p.id = ?`, item.ProductID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrProductNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	var inCart int
+	err = tx.QueryRowContext(ctx, `SELECT quantity, added_at FROM cart_items WHERE buyer_id = ? AND product_id = ?`,
+		item.BuyerID, item.ProductID).Scan(&inCart, &item.AddedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		var items int
+		if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM cart_items WHERE buyer_id = ?`, item.BuyerID).Scan(&items); err != nil {
+			return err
+		}
+		if items >= models.MaxCartItems {
+			return models.ErrTooManyCartItems
+		}
+		// mysql keeps milliseconds
+		item.AddedAt = time.Now().UTC().Truncate(time.Millisecond)
+	case err != nil:
+		return err
+	}
+	if item.Quantity+inCart > p.Quantity {
+		return models.ErrInsufficientStock
+	}
+	item.Quantity += inCart
+	item.Snapshot(p)
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO cart_items (buyer_id, product_id, quantity, added_price, added_stock, added_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE quantity = VALUES(quantity), added_price = VALUES(added_price), added_stock = VALUES(added_stock)`,
+		item.BuyerID, item.ProductID, item.Quantity, item.AddedPrice, item.AddedStock, item.AddedAt)
+	if err != nil {
+		if isNoReferencedRow(err) {
+			return models.ErrProductNotFound
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateCartItem locks the item, checks the product has enough stock and sets its quantity in
+// a transaction
+func (s *Store) UpdateCartItem(ctx context.Context, item *models.CartItem) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `SELECT added_at FROM cart_items WHERE buyer_id = ? AND product_id = ? FOR UPDATE`,
+		item.BuyerID, item.ProductID).Scan(&item.AddedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrCartItemNotFound
+	}
+	if err != nil {
+		return err
+	}
+	p, err := scanProduct(tx.QueryRowContext(ctx, `SELECT `+productColumns+` FROM products AS p WHERE p.id = ?`, item.ProductID))
+	if err != nil {
+		return err
+	}
+	if item.Quantity > p.Quantity {
+		return models.ErrInsufficientStock
+	}
+	item.Snapshot(p)
+
+	_, err = tx.ExecContext(ctx, `UPDATE cart_items SET quantity = ?, added_price = ?, added_stock = ? WHERE buyer_id = ? AND product_id = ?`,
+		item.Quantity, item.AddedPrice, item.AddedStock, item.BuyerID, item.ProductID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteCartItem takes the product out of the cart of the buyer
+func (s *Store) DeleteCartItem(ctx context.Context, buyerID, productID int) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM cart_items WHERE buyer_id = ? AND product_id = ?`, buyerID, productID)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrCartItemNotFound
+	}
+	return nil
+}
+
+// ClearCart empties the cart of the buyer
+func (s *Store) ClearCart(ctx context.Context, buyerID int) error {
+	if _, err := s.GetBuyerByID(ctx, buyerID); err != nil {
+		return err
+	}
+	_, err := s.conn.ExecContext(ctx, `DELETE FROM cart_items WHERE buyer_id = ?`, buyerID)
+	return err
+}
@@ -0,0 +1,224 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// categoryDepth is the level of the category of a path, 1 for the roots: /1/ has depth 1, /1/4/ depth 2
+func categoryDepth(path string) int {
+	return strings.Count(path, "/") - 1
+}
+
+// lockCategoryPath locks the category row and returns its path, sql.ErrNoRows if it does not exist
+func lockCategoryPath(ctx context.Context, tx *sql.Tx, id int) (string, error) {
+	var path string
+	err := tx.QueryRowContext(ctx, `SELECT path FROM categories WHERE id = ? FOR UPDATE`, id).Scan(&path)
+	return path, err
+}
+
+// CreateCategory saves the category and its path in a transaction and sets its ID
+func (s *Store) CreateCategory(ctx context.Context, c *models.Category) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	parentPath := "/"
+	if c.ParentID > 0 {
+		parentPath, err = lockCategoryPath(ctx, tx, c.ParentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrCategoryParentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if categoryDepth(parentPath) >= models.MaxCategoryDepth {
+			return models.ErrCategoryTooDeep
+		}
+	}
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO categories (parent_id, name) VALUES (?, ?)`, nullableID(c.ParentID), c.Name)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	path := parentPath + strconv.FormatInt(id, 10) + "/"
+	if _, err := tx.ExecContext(ctx, `UPDATE categories SET path = ? WHERE id = ?`, path, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	c.ID = int(id)
+	return nil
+}
+
+// GetCategoryByID retrieves a category by ID from the database
+func (s *Store) GetCategoryByID(ctx context.Context, id int) (models.Category, error) {
+	var category models.Category
+	err := s.conn.QueryRowContext(ctx, `SELECT id, COALESCE(parent_id, 0), name FROM categories WHERE id = ?`, id).
+		Scan(&category.ID, &category.ParentID, &category.Name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return category, models.ErrCategoryNotFound
+	}
+	return category, err
+}
+
+// UpdateCategory locks the category row, applies the update and saves it in a transaction.
+// When the parent changes the paths of the category and of its descendants are rewritten
+func (s *Store) UpdateCategory(ctx context.Context, id int, update models.CategoryUpdate) (models.Category, error) {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Category{}, err
+	}
+	defer tx.Rollback()
+
+	var category models.Category
+	var path string
+	err = tx.QueryRowContext(ctx, `SELECT id, COALESCE(parent_id, 0), name, path FROM categories WHERE id = ? FOR UPDATE`, id).
+		Scan(&category.ID, &category.ParentID, &category.Name, &path)
+	if errors.Is(err, sql.ErrNoRows) {
+		return category, models.ErrCategoryNotFound
+	}
+	if err != nil {
+		return category, err
+	}
+
+	previousParent := category.ParentID
+	update.Apply(&category)
+	if category.ParentID != previousParent {
+		if err := moveCategory(ctx, tx, category, path); err != nil {
+			return category, err
+		}
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE categories SET name = ?, parent_id = ? WHERE id = ?`, category.Name, nullableID(category.ParentID), id)
+	if err != nil {
+		return category, err
+	}
+	return category, tx.Commit()
+}
+
+// moveCategory rewrites the paths of the category at path and of its descendants to put them
+// under category.ParentID
+func moveCategory(ctx context.Context, tx *sql.Tx, category models.Category, path string) error {
+	parentPath := "/"
+	if category.ParentID > 0 {
+		var err error
+		parentPath, err = lockCategoryPath(ctx, tx, category.ParentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.ErrCategoryParentNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if strings.HasPrefix(parentPath, path) {
+			return models.ErrCategoryCycle
+		}
+	}
+
+	// the deepest descendant tells how many levels the subtree spans
+	var deepest string
+	err := tx.QueryRowContext(ctx, `SELECT path FROM categories WHERE path LIKE ? ORDER BY LENGTH(path) - LENGTH(REPLACE(path, '/', '')) DESC LIMIT 1`, path+"%").
+		Scan(&deepest)
+	if err != nil {
+		return err
+	}
+	height := categoryDepth(deepest) - categoryDepth(path) + 1
+	if categoryDepth(parentPath)+height > models.MaxCategoryDepth {
+		return models.ErrCategoryTooDeep
+	}
+
+	newPath := parentPath + strconv.Itoa(category.ID) + "/"
+	_, err = tx.ExecContext(ctx, `UPDATE categories SET path = CONCAT(?, SUBSTRING(path, ?)) WHERE path LIKE ?`, newPath, len(path)+1, path+"%")
+	return err
+}
+
+// DeleteCategory deletes the category in a transaction, unless it has subcategories or products
+func (s *Store) DeleteCategory(ctx context.Context, id int) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := lockCategoryPath(ctx, tx, id); errors.Is(err, sql.ErrNoRows) {
+		return models.ErrCategoryNotFound
+	} else if err != nil {
+		return err
+	}
+
+	var children, products int
+	err = tx.QueryRowContext(ctx, `SELECT (SELECT COUNT(*) FROM categories WHERE parent_id = ?), (SELECT COUNT(*) FROM products WHERE category_id = ?)`, id, id).
+		Scan(&children, &products)
+	if err != nil {
+		return err
+	}
+	if children > 0 || products > 0 {
+		return models.ErrCategoryNotEmpty
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM categories WHERE id = ?`, id)
+	if err != nil {
+		if isRowIsReferenced(err) {
+			return models.ErrCategoryNotEmpty
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// CategoryTree loads every category and the number of products of each to build the tree
+func (s *Store) CategoryTree(ctx context.Context) ([]*models.CategoryNode, error) {
+	rows, err := s.conn.QueryContext(ctx, `SELECT id, COALESCE(parent_id, 0), name FROM categories`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var categories []models.Category
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.ID, &c.ParentID, &c.Name); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int]int)
+	rows, err = s.conn.QueryContext(ctx, `SELECT category_id, COUNT(*) FROM products WHERE category_id IS NOT NULL GROUP BY category_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, count int
+		if err := rows.Scan(&id, &count); err != nil {
+			return nil, err
+		}
+		counts[id] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return models.CategoryTree(categories, counts), nil
+}
+
+// nullableID stores an id of 0, meaning none, as NULL
+func nullableID(id int) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
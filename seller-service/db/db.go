@@ -3,42 +3,34 @@ package db
 
 import (
 	"database/sql"
-	"fmt"
-	"log"
-	"os"
 
 	_ "github.com/go-sql-driver/mysql"
-)
 
-// Database configuration
-var (
-	dbDriver   = "mysql"
-	dbUser     = os.Getenv("MYSQL_USER")
-	dbPassword = os.Getenv("MYSQL_PASSWORD")
-	dbName     = os.Getenv("MYSQL_DATABASE")
-	dbHostName = os.Getenv("MYSQL_HOSTNAME")
-	dbPort     = os.Getenv("MYSQL_PORT")
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/config"
 )
 
-// DB - is a connection object which is used to talk to db
-var DB *sql.DB
+const dbDriver = "mysql"
 
 /*
-Init initializes the connection to the db and assigns it to an exported DB object, fatal if connection is failed.
+Open opens the connection pool to the db described by cfg and pings it to verify the connection.
 
-	It reads all necessary values that are required to connect to db
+	The caller is responsible for closing the returned DB
 */
-func Init() {
+func Open(cfg config.MySQL) (*sql.DB, error) {
 	// Open a database connection
-	var err error
-	DB, err = sql.Open(dbDriver, fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPassword, dbHostName, dbPort, dbName))
+	conn, err := sql.Open(dbDriver, cfg.DSN())
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
+	conn.SetMaxOpenConns(cfg.MaxOpenConns)
+	conn.SetMaxIdleConns(cfg.MaxIdleConns)
+	conn.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	conn.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
 
 	// Ping the database to verify the connection
-	err = DB.Ping()
-	if err != nil {
-		log.Fatal(err)
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, err
 	}
+	return conn, nil
 }
@@ -0,0 +1,110 @@
+package db
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// FacetProducts counts the products matching the request by each of req.Facets with one
+// GROUP BY query per facet, the counts of a facet ignore its own filter
+func (s *Store) FacetProducts(ctx context.Context, req *models.ProductRequest) (models.Facets, error) {
+	facets := make(models.Facets, len(req.Facets))
+	for _, facet := range req.Facets {
+		where, args := productFilters(req.WithoutFacetFilter(facet))
+		from := " FROM products AS p INNER JOIN sellers AS s ON p.seller_id = s.id WHERE 1=1" + where
+
+		var buckets []models.FacetBucket
+		var err error
+		switch facet {
+		case models.FacetLocation:
+			buckets, err = s.facetBuckets(ctx, "SELECT s.location, '', COUNT(*)"+from+
+				" GROUP BY s.location ORDER BY COUNT(*) DESC, s.location LIMIT "+strconv.Itoa(models.MaxFacetBuckets), args)
+		case models.FacetSellerID:
+			buckets, err = s.facetBuckets(ctx, "SELECT p.seller_id, s.name, COUNT(*)"+from+
+				" GROUP BY p.seller_id, s.name ORDER BY COUNT(*) DESC, p.seller_id LIMIT "+strconv.Itoa(models.MaxFacetBuckets), args)
+		case models.FacetPriceRange:
+			buckets, err = s.priceRangeBuckets(ctx, from, args)
+		}
+		if err != nil {
+			return nil, err
+		}
+		facets[facet] = buckets
+	}
+	return facets, nil
+}
+
+// facetBuckets runs a query selecting the value, the label and the count of each bucket
+func (s *Store) facetBuckets(ctx context.Context, query string, args []interface{}) ([]models.FacetBucket, error) {
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	buckets := []models.FacetBucket{}
+	for rows.Next() {
+		var b models.FacetBucket
+		if err := rows.Scan(&b.Value, &b.Label, &b.Count); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// priceRangeBuckets counts the products in each band of models.PriceRanges, empty bands included
+func (s *Store) priceRangeBuckets(ctx context.Context, from string, args []interface{}) ([]models.FacetBucket, error) {
+	band := "CASE"
+	for i, r := range models.PriceRanges {
+		if r.Max != 0 {
+			band += " WHEN p.price < " + strconv.FormatFloat(r.Max, 'f', -1, 64) + " THEN " + strconv.Itoa(i)
+		}
+	}
+	band += " ELSE " + strconv.Itoa(len(models.PriceRanges)-1) + " END"
+
+	rows, err := s.conn.QueryContext(ctx, "SELECT "+band+" AS band, COUNT(*)"+from+" GROUP BY band", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make([]int, len(models.PriceRanges))
+	for rows.Next() {
+		var i, count int
+		if err := rows.Scan(&i, &count); err != nil {
+			return nil, err
+		}
+		counts[i] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	buckets := make([]models.FacetBucket, len(models.PriceRanges))
+	for i, r := range models.PriceRanges {
+		buckets[i] = r.Bucket(counts[i])
+	}
+	return buckets, nil
+}
+
+// CountProductNames counts the products of each name, names differing only in case are one
+// group under the case-insensitive collation
+func (s *Store) CountProductNames(ctx context.Context) (map[string]int, error) {
+	rows, err := s.conn.QueryContext(ctx, `SELECT MIN(product_name), COUNT(*) FROM products GROUP BY product_name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]int)
+	for rows.Next() {
+		var name string
+		var count int
+		if err := rows.Scan(&name, &count); err != nil {
+			return nil, err
+		}
+		counts[name] = count
+	}
+	return counts, rows.Err()
+}
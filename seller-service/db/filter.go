@@ -0,0 +1,73 @@
+package db
+
+import (
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// filterColumns are the columns of the fields a filter compares, products are aliased p and
+// their sellers s. Only these names end up in the query, the values are all bound to placeholders
+var filterColumns = map[string]string{
+	"productId":   "p.id",
+	"sellerId":    "p.seller_id",
+	"productName": "p.product_name",
+	"description": "p.description",
+	"price":       "p.price",
+	"quantity":    "p.quantity",
+	"sellerName":  "s.name",
+	"location":    "s.location",
+	// sellers without an address have no country, like an empty one in the memory store
+	"country": "COALESCE(s.country, '')",
+}
+
+// likeEscaper escapes the wildcards of a LIKE pattern, backslash being the default escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// compileFilter frames the condition of a valid filter tree and the values of its placeholders
+func compileFilter(f *models.Filter) (string, []interface{}) {
+	switch {
+	case f.And != nil:
+		return compileFilters(f.And, " AND ")
+	case f.Or != nil:
+		return compileFilters(f.Or, " OR ")
+	case f.Not != nil:
+		condition, args := compileFilter(f.Not)
+		return "NOT (" + condition + ")", args
+	case f.Eq != nil:
+		return filterColumns[f.Eq.Field] + " = ?", []interface{}{f.Eq.Value}
+	case f.In != nil:
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(f.In.Values)), ", ")
+		return filterColumns[f.In.Field] + " IN (" + placeholders + ")", f.In.Values
+	case f.Range != nil:
+		column := filterColumns[f.Range.Field]
+		var conditions []string
+		var args []interface{}
+		for _, bound := range []struct {
+			op    string
+			value *float64
+		}{{">", f.Range.Gt}, {">=", f.Range.Gte}, {"<", f.Range.Lt}, {"<=", f.Range.Lte}} {
+			if bound.value != nil {
+				conditions = append(conditions, column+" "+bound.op+" ?")
+				args = append(args, *bound.value)
+			}
+		}
+		return "(" + strings.Join(conditions, " AND ") + ")", args
+	case f.Contains != nil:
+		value, _ := f.Contains.Value.(string)
+		return filterColumns[f.Contains.Field] + " LIKE ?", []interface{}{"%" + likeEscaper.Replace(value) + "%"}
+	}
+	return "FALSE", nil
+}
+
+// compileFilters joins the conditions of the filters with op, in parentheses
+func compileFilters(filters []*models.Filter, op string) (string, []interface{}) {
+	conditions := make([]string, len(filters))
+	var args []interface{}
+	for i, f := range filters {
+		var filterArgs []interface{}
+		conditions[i], filterArgs = compileFilter(f)
+		args = append(args, filterArgs...)
+	}
+	return "(" + strings.Join(conditions, op) + ")", args
+}
@@ -0,0 +1,45 @@
+package db
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+func TestCompileFilter(t *testing.T) {
+	tests := []struct {
+		filter    string
+		condition string
+		args      []interface{}
+	}{
+		{`{"eq": {"field": "location", "value": "IND"}}`, "s.location = ?", []interface{}{"IND"}},
+		{`{"in": {"field": "sellerId", "values": [1, 2]}}`, "p.seller_id IN (?, ?)", []interface{}{1.0, 2.0}},
+		{`{"range": {"field": "price", "gte": 10, "lt": 50}}`, "(p.price >= ? AND p.price < ?)", []interface{}{10.0, 50.0}},
+		{`{"contains": {"field": "productName", "value": "100%_"}}`, "p.product_name LIKE ?", []interface{}{`%100\%\_%`}},
+		{
+			`{"or": [{"not": {"eq": {"field": "quantity", "value": 0}}}, {"and": [{"eq": {"field": "sellerName", "value": "x' OR 1=1"}}]}]}`,
+			"(NOT (p.quantity = ?) OR (s.name = ?))", []interface{}{0.0, "x' OR 1=1"},
+		},
+	}
+	for _, tt := range tests {
+		var f models.Filter
+		if err := json.Unmarshal([]byte(tt.filter), &f); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", tt.filter, err)
+		}
+		condition, args := compileFilter(&f)
+		if condition != tt.condition || !reflect.DeepEqual(args, tt.args) {
+			t.Errorf("compileFilter(%s) = %q %v, want %q %v", tt.filter, condition, args, tt.condition, tt.args)
+		}
+	}
+}
+
+func TestProductFilters_NameIsLiteral(t *testing.T) {
+	req := &models.ProductRequest{ProductName: `100%_\`, Synonyms: models.Synonyms{"100%_\\": {"a_b"}}}
+	_, args := productFilters(req)
+	want := []interface{}{`%100\%\_\\%`, `%a\_b%`}
+	if !reflect.DeepEqual(args, want) {
+		t.Errorf("productFilters args = %v, want %v", args, want)
+	}
+}
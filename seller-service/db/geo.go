@@ -0,0 +1,89 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// sellerColumns are the columns scanned by sellerScan, in order, sellers are aliased s
+const sellerColumns = "s.id, s.name, s.location, s.country, s.city, s.postal_code, s.latitude, s.longitude"
+
+// sellerScan receives the sellerColumns of a row, the address columns being nullable
+type sellerScan struct {
+	seller     models.Seller
+	country    sql.NullString
+	city       string
+	postalCode string
+	latitude   sql.NullFloat64
+	longitude  sql.NullFloat64
+}
+
+// dest returns the destinations of the sellerColumns
+func (s *sellerScan) dest() []interface{} {
+	return []interface{}{&s.seller.ID, &s.seller.Name, &s.seller.Location, &s.country, &s.city, &s.postalCode, &s.latitude, &s.longitude}
+}
+
+// result returns the scanned seller, without address when it has no country
+func (s *sellerScan) result() models.Seller {
+	seller := s.seller
+	if s.country.Valid {
+		seller.Address = &models.Address{Country: s.country.String, City: s.city, PostalCode: s.postalCode}
+		if s.latitude.Valid && s.longitude.Valid {
+			seller.Address.Latitude, seller.Address.Longitude = &s.latitude.Float64, &s.longitude.Float64
+		}
+	}
+	return seller
+}
+
+// scanSeller scans the sellerColumns of the row into a seller
+func scanSeller(row rowScanner) (models.Seller, error) {
+	var s sellerScan
+	err := row.Scan(s.dest()...)
+	return s.result(), err
+}
+
+// addressValues are the values of the country, city, postal_code, latitude and longitude
+// columns of a seller with the address
+func addressValues(a *models.Address) []interface{} {
+	if a == nil {
+		return []interface{}{nil, "", "", nil, nil}
+	}
+	return []interface{}{a.Country, a.City, a.PostalCode, a.Latitude, a.Longitude}
+}
+
+// distance is the haversine distance in km between the seller and the point bound to its
+// placeholders by distanceArgs, as models.DistanceKm computes it
+const distance = "(2 * ? * ASIN(SQRT(LEAST(1, POW(SIN(RADIANS(s.latitude - ?) / 2), 2) + " +
+	"COS(RADIANS(?)) * COS(RADIANS(s.latitude)) * POW(SIN(RADIANS(s.longitude - ?) / 2), 2)))))"
+
+// distanceArgs are the values bound to the placeholders of distance
+func distanceArgs(p *models.ProductRequest) []interface{} {
+	return []interface{}{models.EarthRadiusKm, p.Near.Lat, p.Near.Lat, p.Near.Lng}
+}
+
+// geoFilters frames the conditions of the country and near filters of the request
+func geoFilters(p *models.ProductRequest) (string, []interface{}) {
+	var query string
+	var args []interface{}
+	if len(p.Countries) > 0 {
+		query += " AND s.country IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(p.Countries)), ", ") + ")"
+		for _, code := range p.Countries {
+			args = append(args, code)
+		}
+	}
+	if p.Near == nil {
+		return query, args
+	}
+	query += " AND s.latitude IS NOT NULL AND s.longitude IS NOT NULL"
+	if p.RadiusKm > 0 {
+		// the sellers within the radius are within as many degrees of latitude, a range the
+		// index on the latitude can read before the distance of each is computed
+		delta := p.RadiusKm / models.KmPerDegree
+		query += " AND s.latitude BETWEEN ? AND ? AND " + distance + " <= ?"
+		args = append(args, p.Near.Lat-delta, p.Near.Lat+delta)
+		args = append(append(args, distanceArgs(p)...), p.RadiusKm)
+	}
+	return query, args
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"0002_add_index.up.sql":      {Data: []byte("CREATE INDEX a ON b (c);")},
+		"0002_add_index.down.sql":    {Data: []byte("DROP INDEX a ON b;")},
+		"0001_create_table.up.sql":   {Data: []byte("CREATE TABLE b (c INT);")},
+		"0001_create_table.down.sql": {Data: []byte("DROP TABLE b;")},
+	}
+	migrations, err := LoadMigrations(fsys)
+	if err != nil {
+		t.Fatalf("LoadMigrations: %v", err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "create_table", Up: "CREATE TABLE b (c INT);", Down: "DROP TABLE b;"},
+		{Version: 2, Name: "add_index", Up: "CREATE INDEX a ON b (c);", Down: "DROP INDEX a ON b;"},
+	}
+	if !reflect.DeepEqual(migrations, want) {
+		t.Errorf("Expected %+v, got %+v", want, migrations)
+	}
+}
+
+func TestLoadMigrations_Invalid(t *testing.T) {
+	tests := map[string]fstest.MapFS{
+		"missing down": {"0001_a.up.sql": {Data: []byte("SELECT 1")}},
+		"bad name":     {"create.sql": {Data: []byte("SELECT 1")}},
+		"zero version": {"0000_a.up.sql": {Data: []byte("SELECT 1")}, "0000_a.down.sql": {Data: []byte("SELECT 1")}},
+		"two names":    {"0001_a.up.sql": {Data: []byte("SELECT 1")}, "0001_b.down.sql": {Data: []byte("SELECT 1")}},
+	}
+	for name, fsys := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := LoadMigrations(fsys); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrator, err := NewMigrator(nil)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	for i, m := range migrator.migrations {
+		if m.Version != i+1 {
+			t.Errorf("Migration versions must be contiguous, expected %d got %d_%s", i+1, m.Version, m.Name)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	script := `
+-- create the table
+CREATE TABLE a (id INT);
+
+INSERT INTO a VALUES (1);
+`
+	want := []string{"CREATE TABLE a (id INT)", "INSERT INTO a VALUES (1)"}
+	if got := splitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestMigrator(t *testing.T) {
+	conn := openTestDatabase(t)
+	migrator, err := NewMigrator(conn)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	ctx := context.Background()
+	t.Cleanup(func() { migrator.Up(ctx) })
+
+	if err := migrator.To(ctx, 0); err != nil {
+		t.Fatalf("To(0): %v", err)
+	}
+	if version, err := migrator.Version(ctx); err != nil || version != 0 {
+		t.Fatalf("Version = %d, %v, want 0", version, err)
+	}
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, s := range status {
+		if !s.Applied {
+			t.Errorf("Migration %d_%s is not applied", s.Version, s.Name)
+		}
+	}
+	if err := migrator.Down(ctx); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	if version, _ := migrator.Version(ctx); version != migrator.Latest()-1 {
+		t.Errorf("Expected version %d after Down, got %d", migrator.Latest()-1, version)
+	}
+}
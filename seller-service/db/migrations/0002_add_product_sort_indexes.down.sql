@@ -0,0 +1,2 @@
+DROP INDEX idx_product_name ON products;
+DROP INDEX idx_product_price ON products;
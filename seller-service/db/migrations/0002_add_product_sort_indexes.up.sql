@@ -0,0 +1,3 @@
+-- keyset pagination seeks on the sort column then the id, which every secondary index ends with
+CREATE INDEX idx_product_price ON products (price);
+CREATE INDEX idx_product_name ON products (product_name);
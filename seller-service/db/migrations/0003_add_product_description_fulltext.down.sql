@@ -0,0 +1,2 @@
+DROP INDEX ft_product_name_description ON products;
+ALTER TABLE products DROP COLUMN description;
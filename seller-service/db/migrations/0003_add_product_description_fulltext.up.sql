@@ -0,0 +1,3 @@
+ALTER TABLE products ADD COLUMN description VARCHAR(2000) NOT NULL DEFAULT '';
+-- full-text search ranks products on their name and description, see models.ParseTextQuery
+CREATE FULLTEXT INDEX ft_product_name_description ON products (product_name, description);
@@ -0,0 +1,5 @@
+ALTER TABLE products
+    DROP FOREIGN KEY fk_product_category_id,
+    DROP INDEX idx_product_category_id,
+    DROP COLUMN category_id;
+DROP TABLE categories;
@@ -0,0 +1,15 @@
+-- path lists the ids from the root down to the category, e.g. /1/4/9/, so the descendants
+-- of a category are the categories whose path starts with its own
+CREATE TABLE IF NOT EXISTS categories (
+    id        INT PRIMARY KEY AUTO_INCREMENT,
+    parent_id INT NULL,
+    name      VARCHAR(255) NOT NULL,
+    path      VARCHAR(255) CHARACTER SET ascii NOT NULL DEFAULT '',
+    INDEX idx_category_path (path),
+    CONSTRAINT fk_category_parent_id FOREIGN KEY (parent_id) REFERENCES categories (id)
+);
+
+ALTER TABLE products
+    ADD COLUMN category_id INT NULL,
+    ADD INDEX idx_product_category_id (category_id),
+    ADD CONSTRAINT fk_product_category_id FOREIGN KEY (category_id) REFERENCES categories (id);
@@ -0,0 +1,2 @@
+DROP TABLE product_tags;
+DROP TABLE product_attributes;
@@ -0,0 +1,19 @@
+-- value_number holds the value of attributes that are numbers, for the >, >=, < and <= filters
+CREATE TABLE IF NOT EXISTS product_attributes (
+    product_id   INT NOT NULL,
+    name         VARCHAR(64) NOT NULL,
+    value        VARCHAR(255) NOT NULL,
+    value_number DOUBLE NULL,
+    PRIMARY KEY (product_id, name),
+    INDEX idx_product_attribute_value (name, value),
+    INDEX idx_product_attribute_number (name, value_number),
+    CONSTRAINT fk_product_attribute_product_id FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS product_tags (
+    product_id INT NOT NULL,
+    tag        VARCHAR(64) NOT NULL,
+    PRIMARY KEY (product_id, tag),
+    INDEX idx_product_tag (tag),
+    CONSTRAINT fk_product_tag_product_id FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
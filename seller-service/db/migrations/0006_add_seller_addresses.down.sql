@@ -0,0 +1,8 @@
+ALTER TABLE sellers
+    DROP INDEX idx_seller_latitude,
+    DROP INDEX idx_seller_country,
+    DROP COLUMN longitude,
+    DROP COLUMN latitude,
+    DROP COLUMN postal_code,
+    DROP COLUMN city,
+    DROP COLUMN country;
@@ -0,0 +1,788 @@
+-- structured address of the sellers, location stays as a free-text description. Sellers
+-- without a country have no address, the coordinates are either both set or both NULL
+ALTER TABLE sellers
+    ADD COLUMN country     CHAR(2) CHARACTER SET ascii NULL,
+    ADD COLUMN city        VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN postal_code VARCHAR(32) NOT NULL DEFAULT '',
+    ADD COLUMN latitude    DOUBLE NULL,
+    ADD COLUMN longitude   DOUBLE NULL,
+    ADD INDEX idx_seller_country (country),
+    ADD INDEX idx_seller_latitude (latitude);
+
+-- backfill the country of the sellers whose location is the ISO 3166-1 alpha-2 or alpha-3
+-- code or the English name of a country, as models.CountryCode does. Other locations are
+-- left without an address until the seller is updated with one
+CREATE TEMPORARY TABLE country_names (
+    name VARCHAR(64) NOT NULL PRIMARY KEY,
+    code CHAR(2) CHARACTER SET ascii NOT NULL
+);
+
+INSERT IGNORE INTO country_names (name, code) VALUES
+    ('AD', 'AD'),
+    ('AND', 'AD'),
+    ('Andorra', 'AD'),
+    ('AE', 'AE'),
+    ('ARE', 'AE'),
+    ('United Arab Emirates', 'AE'),
+    ('AF', 'AF'),
+    ('AFG', 'AF'),
+    ('Afghanistan', 'AF'),
+    ('AG', 'AG'),
+    ('ATG', 'AG'),
+    ('Antigua and Barbuda', 'AG'),
+    ('AI', 'AI'),
+    ('AIA', 'AI'),
+    ('Anguilla', 'AI'),
+    ('AL', 'AL'),
+    ('ALB', 'AL'),
+    ('Albania', 'AL'),
+    ('AM', 'AM'),
+    ('ARM', 'AM'),
+    ('Armenia', 'AM'),
+    ('AO', 'AO'),
+    ('AGO', 'AO'),
+    ('Angola', 'AO'),
+    ('AQ', 'AQ'),
+    ('ATA', 'AQ'),
+    ('Antarctica', 'AQ'),
+    ('AR', 'AR'),
+    ('ARG', 'AR'),
+    ('Argentina', 'AR'),
+    ('AS', 'AS'),
+    ('ASM', 'AS'),
+    ('American Samoa', 'AS'),
+    ('AT', 'AT'),
+    ('AUT', 'AT'),
+    ('Austria', 'AT'),
+    ('AU', 'AU'),
+    ('AUS', 'AU'),
+    ('Australia', 'AU'),
+    ('AW', 'AW'),
+    ('ABW', 'AW'),
+    ('Aruba', 'AW'),
+    ('AX', 'AX'),
+    ('ALA', 'AX'),
+    ('Åland Islands', 'AX'),
+    ('AZ', 'AZ'),
+    ('AZE', 'AZ'),
+    ('Azerbaijan', 'AZ'),
+    ('BA', 'BA'),
+    ('BIH', 'BA'),
+    ('Bosnia and Herzegovina', 'BA'),
+    ('BB', 'BB'),
+    ('BRB', 'BB'),
+    ('Barbados', 'BB'),
+    ('BD', 'BD'),
+    ('BGD', 'BD'),
+    ('Bangladesh', 'BD'),
+    ('BE', 'BE'),
+    ('BEL', 'BE'),
+    ('Belgium', 'BE'),
+    ('BF', 'BF'),
+    ('BFA', 'BF'),
+    ('Burkina Faso', 'BF'),
+    ('BG', 'BG'),
+    ('BGR', 'BG'),
+    ('Bulgaria', 'BG'),
+    ('BH', 'BH'),
+    ('BHR', 'BH'),
+    ('Bahrain', 'BH'),
+    ('BI', 'BI'),
+    ('BDI', 'BI'),
+    ('Burundi', 'BI'),
+    ('BJ', 'BJ'),
+    ('BEN', 'BJ'),
+    ('Benin', 'BJ'),
+    ('BL', 'BL'),
+    ('BLM', 'BL'),
+    ('Saint Barthélemy', 'BL'),
+    ('BM', 'BM'),
+    ('BMU', 'BM'),
+    ('Bermuda', 'BM'),
+    ('BN', 'BN'),
+    ('BRN', 'BN'),
+    ('Brunei Darussalam', 'BN'),
+    ('BO', 'BO'),
+    ('BOL', 'BO'),
+    ('Bolivia, Plurinational State of', 'BO'),
+    ('Bolivia', 'BO'),
+    ('BQ', 'BQ'),
+    ('BES', 'BQ'),
+    ('Bonaire, Sint Eustatius and Saba', 'BQ'),
+    ('BR', 'BR'),
+    ('BRA', 'BR'),
+    ('Brazil', 'BR'),
+    ('BS', 'BS'),
+    ('BHS', 'BS'),
+    ('Bahamas', 'BS'),
+    ('BT', 'BT'),
+    ('BTN', 'BT'),
+    ('Bhutan', 'BT'),
+    ('BV', 'BV'),
+    ('BVT', 'BV'),
+    ('Bouvet Island', 'BV'),
+    ('BW', 'BW'),
+    ('BWA', 'BW'),
+    ('Botswana', 'BW'),
+    ('BY', 'BY'),
+    ('BLR', 'BY'),
+    ('Belarus', 'BY'),
+    ('BZ', 'BZ'),
+    ('BLZ', 'BZ'),
+    ('Belize', 'BZ'),
+    ('CA', 'CA'),
+    ('CAN', 'CA'),
+    ('Canada', 'CA'),
+    ('CC', 'CC'),
+    ('CCK', 'CC'),
+    ('Cocos (Keeling) Islands', 'CC'),
+    ('CD', 'CD'),
+    ('COD', 'CD'),
+    ('Congo, The Democratic Republic of the', 'CD'),
+    ('CF', 'CF'),
+    ('CAF', 'CF'),
+    ('Central African Republic', 'CF'),
+    ('CG', 'CG'),
+    ('COG', 'CG'),
+    ('Congo', 'CG'),
+    ('CH', 'CH'),
+    ('CHE', 'CH'),
+    ('Switzerland', 'CH'),
+    ('CI', 'CI'),
+    ('CIV', 'CI'),
+    ('Côte d''Ivoire', 'CI'),
+    ('CK', 'CK'),
+    ('COK', 'CK'),
+    ('Cook Islands', 'CK'),
+    ('CL', 'CL'),
+    ('CHL', 'CL'),
+    ('Chile', 'CL'),
+    ('CM', 'CM'),
+    ('CMR', 'CM'),
+    ('Cameroon', 'CM'),
+    ('CN', 'CN'),
+    ('CHN', 'CN'),
+    ('China', 'CN'),
+    ('CO', 'CO'),
+    ('COL', 'CO'),
+    ('Colombia', 'CO'),
+    ('CR', 'CR'),
+    ('CRI', 'CR'),
+    ('Costa Rica', 'CR'),
+    ('CU', 'CU'),
+    ('CUB', 'CU'),
+    ('Cuba', 'CU'),
+    ('CV', 'CV'),
+    ('CPV', 'CV'),
+    ('Cabo Verde', 'CV'),
+    ('CW', 'CW'),
+    ('CUW', 'CW'),
+    ('Curaçao', 'CW'),
+    ('CX', 'CX'),
+    ('CXR', 'CX'),
+    ('Christmas Island', 'CX'),
+    ('CY', 'CY'),
+    ('CYP', 'CY'),
+    ('Cyprus', 'CY'),
+    ('CZ', 'CZ'),
+    ('CZE', 'CZ'),
+    ('Czechia', 'CZ'),
+    ('DE', 'DE'),
+    ('DEU', 'DE'),
+    ('Germany', 'DE'),
+    ('DJ', 'DJ'),
+    ('DJI', 'DJ'),
+    ('Djibouti', 'DJ'),
+    ('DK', 'DK'),
+    ('DNK', 'DK'),
+    ('Denmark', 'DK'),
+    ('DM', 'DM'),
+    ('DMA', 'DM'),
+    ('Dominica', 'DM'),
+    ('DO', 'DO'),
+    ('DOM', 'DO'),
+    ('Dominican Republic', 'DO'),
+    ('DZ', 'DZ'),
+    ('DZA', 'DZ'),
+    ('Algeria', 'DZ'),
+    ('EC', 'EC'),
+    ('ECU', 'EC'),
+    ('Ecuador', 'EC'),
+    ('EE', 'EE'),
+    ('EST', 'EE'),
+    ('Estonia', 'EE'),
+    ('EG', 'EG'),
+    ('EGY', 'EG'),
+    ('Egypt', 'EG'),
+    ('EH', 'EH'),
+    ('ESH', 'EH'),
+    ('Western Sahara', 'EH'),
+    ('ER', 'ER'),
+    ('ERI', 'ER'),
+    ('Eritrea', 'ER'),
+    ('ES', 'ES'),
+    ('ESP', 'ES'),
+    ('Spain', 'ES'),
+    ('ET', 'ET'),
+    ('ETH', 'ET'),
+    ('Ethiopia', 'ET'),
+    ('FI', 'FI'),
+    ('FIN', 'FI'),
+    ('Finland', 'FI'),
+    ('FJ', 'FJ'),
+    ('FJI', 'FJ'),
+    ('Fiji', 'FJ'),
+    ('FK', 'FK'),
+    ('FLK', 'FK'),
+    ('Falkland Islands (Malvinas)', 'FK'),
+    ('FM', 'FM'),
+    ('FSM', 'FM'),
+    ('Micronesia, Federated States of', 'FM'),
+    ('FO', 'FO'),
+    ('FRO', 'FO'),
+    ('Faroe Islands', 'FO'),
+    ('FR', 'FR'),
+    ('FRA', 'FR'),
+    ('France', 'FR'),
+    ('GA', 'GA'),
+    ('GAB', 'GA'),
+    ('Gabon', 'GA'),
+    ('GB', 'GB'),
+    ('GBR', 'GB'),
+    ('United Kingdom', 'GB'),
+    ('GD', 'GD'),
+    ('GRD', 'GD'),
+    ('Grenada', 'GD'),
+    ('GE', 'GE'),
+    ('GEO', 'GE'),
+    ('Georgia', 'GE'),
+    ('GF', 'GF'),
+    ('GUF', 'GF'),
+    ('French Guiana', 'GF'),
+    ('GG', 'GG'),
+    ('GGY', 'GG'),
+    ('Guernsey', 'GG'),
+    ('GH', 'GH'),
+    ('GHA', 'GH'),
+    ('Ghana', 'GH'),
+    ('GI', 'GI'),
+    ('GIB', 'GI'),
+    ('Gibraltar', 'GI'),
+    ('GL', 'GL'),
+    ('GRL', 'GL'),
+    ('Greenland', 'GL'),
+    ('GM', 'GM'),
+    ('GMB', 'GM'),
+    ('Gambia', 'GM'),
+    ('GN', 'GN'),
+    ('GIN', 'GN'),
+    ('Guinea', 'GN'),
+    ('GP', 'GP'),
+    ('GLP', 'GP'),
+    ('Guadeloupe', 'GP'),
+    ('GQ', 'GQ'),
+    ('GNQ', 'GQ'),
+    ('Equatorial Guinea', 'GQ'),
+    ('GR', 'GR'),
+    ('GRC', 'GR'),
+    ('Greece', 'GR'),
+    ('GS', 'GS'),
+    ('SGS', 'GS'),
+    ('South Georgia and the South Sandwich Islands', 'GS'),
+    ('GT', 'GT'),
+    ('GTM', 'GT'),
+    ('Guatemala', 'GT'),
+    ('GU', 'GU'),
+    ('GUM', 'GU'),
+    ('Guam', 'GU'),
+    ('GW', 'GW'),
+    ('GNB', 'GW'),
+    ('Guinea-Bissau', 'GW'),
+    ('GY', 'GY'),
+    ('GUY', 'GY'),
+    ('Guyana', 'GY'),
+    ('HK', 'HK'),
+    ('HKG', 'HK'),
+    ('Hong Kong', 'HK'),
+    ('HM', 'HM'),
+    ('HMD', 'HM'),
+    ('Heard Island and McDonald Islands', 'HM'),
+    ('HN', 'HN'),
+    ('HND', 'HN'),
+    ('Honduras', 'HN'),
+    ('HR', 'HR'),
+    ('HRV', 'HR'),
+    ('Croatia', 'HR'),
+    ('HT', 'HT'),
+    ('HTI', 'HT'),
+    ('Haiti', 'HT'),
+    ('HU', 'HU'),
+    ('HUN', 'HU'),
+    ('Hungary', 'HU'),
+    ('ID', 'ID'),
+    ('IDN', 'ID'),
+    ('Indonesia', 'ID'),
+    ('IE', 'IE'),
+    ('IRL', 'IE'),
+    ('Ireland', 'IE'),
+    ('IL', 'IL'),
+    ('ISR', 'IL'),
+    ('Israel', 'IL'),
+    ('IM', 'IM'),
+    ('IMN', 'IM'),
+    ('Isle of Man', 'IM'),
+    ('IN', 'IN'),
+    ('IND', 'IN'),
+    ('India', 'IN'),
+    ('IO', 'IO'),
+    ('IOT', 'IO'),
+    ('British Indian Ocean Territory', 'IO'),
+    ('IQ', 'IQ'),
+    ('IRQ', 'IQ'),
+    ('Iraq', 'IQ'),
+    ('IR', 'IR'),
+    ('IRN', 'IR'),
+    ('Iran, Islamic Republic of', 'IR'),
+    ('Iran', 'IR'),
+    ('IS', 'IS'),
+    ('ISL', 'IS'),
+    ('Iceland', 'IS'),
+    ('IT', 'IT'),
+    ('ITA', 'IT'),
+    ('Italy', 'IT'),
+    ('JE', 'JE'),
+    ('JEY', 'JE'),
+    ('Jersey', 'JE'),
+    ('JM', 'JM'),
+    ('JAM', 'JM'),
+    ('Jamaica', 'JM'),
+    ('JO', 'JO'),
+    ('JOR', 'JO'),
+    ('Jordan', 'JO'),
+    ('JP', 'JP'),
+    ('JPN', 'JP'),
+    ('Japan', 'JP'),
+    ('KE', 'KE'),
+    ('KEN', 'KE'),
+    ('Kenya', 'KE'),
+    ('KG', 'KG'),
+    ('KGZ', 'KG'),
+    ('Kyrgyzstan', 'KG'),
+    ('KH', 'KH'),
+    ('KHM', 'KH'),
+    ('Cambodia', 'KH'),
+    ('KI', 'KI'),
+    ('KIR', 'KI'),
+    ('Kiribati', 'KI'),
+    ('KM', 'KM'),
+    ('COM', 'KM'),
+    ('Comoros', 'KM'),
+    ('KN', 'KN'),
+    ('KNA', 'KN'),
+    ('Saint Kitts and Nevis', 'KN'),
+    ('KP', 'KP'),
+    ('PRK', 'KP'),
+    ('Korea, Democratic People''s Republic of', 'KP'),
+    ('North Korea', 'KP'),
+    ('KR', 'KR'),
+    ('KOR', 'KR'),
+    ('Korea, Republic of', 'KR'),
+    ('South Korea', 'KR'),
+    ('KW', 'KW'),
+    ('KWT', 'KW'),
+    ('Kuwait', 'KW'),
+    ('KY', 'KY'),
+    ('CYM', 'KY'),
+    ('Cayman Islands', 'KY'),
+    ('KZ', 'KZ'),
+    ('KAZ', 'KZ'),
+    ('Kazakhstan', 'KZ'),
+    ('LA', 'LA'),
+    ('LAO', 'LA'),
+    ('Lao People''s Democratic Republic', 'LA'),
+    ('Laos', 'LA'),
+    ('LB', 'LB'),
+    ('LBN', 'LB'),
+    ('Lebanon', 'LB'),
+    ('LC', 'LC'),
+    ('LCA', 'LC'),
+    ('Saint Lucia', 'LC'),
+    ('LI', 'LI'),
+    ('LIE', 'LI'),
+    ('Liechtenstein', 'LI'),
+    ('LK', 'LK'),
+    ('LKA', 'LK'),
+    ('Sri Lanka', 'LK'),
+    ('LR', 'LR'),
+    ('LBR', 'LR'),
+    ('Liberia', 'LR'),
+    ('LS', 'LS'),
+    ('LSO', 'LS'),
+    ('Lesotho', 'LS'),
+    ('LT', 'LT'),
+    ('LTU', 'LT'),
+    ('Lithuania', 'LT'),
+    ('LU', 'LU'),
+    ('LUX', 'LU'),
+    ('Luxembourg', 'LU'),
+    ('LV', 'LV'),
+    ('LVA', 'LV'),
+    ('Latvia', 'LV'),
+    ('LY', 'LY'),
+    ('LBY', 'LY'),
+    ('Libya', 'LY'),
+    ('MA', 'MA'),
+    ('MAR', 'MA'),
+    ('Morocco', 'MA'),
+    ('MC', 'MC'),
+    ('MCO', 'MC'),
+    ('Monaco', 'MC'),
+    ('MD', 'MD'),
+    ('MDA', 'MD'),
+    ('Moldova, Republic of', 'MD'),
+    ('Moldova', 'MD'),
+    ('ME', 'ME'),
+    ('MNE', 'ME'),
+    ('Montenegro', 'ME'),
+    ('MF', 'MF'),
+    ('MAF', 'MF'),
+    ('Saint Martin (French part)', 'MF'),
+    ('MG', 'MG'),
+    ('MDG', 'MG'),
+    ('Madagascar', 'MG'),
+    ('MH', 'MH'),
+    ('MHL', 'MH'),
+    ('Marshall Islands', 'MH'),
+    ('MK', 'MK'),
+    ('MKD', 'MK'),
+    ('North Macedonia', 'MK'),
+    ('ML', 'ML'),
+    ('MLI', 'ML'),
+    ('Mali', 'ML'),
+    ('MM', 'MM'),
+    ('MMR', 'MM'),
+    ('Myanmar', 'MM'),
+    ('MN', 'MN'),
+    ('MNG', 'MN'),
+    ('Mongolia', 'MN'),
+    ('MO', 'MO'),
+    ('MAC', 'MO'),
+    ('Macao', 'MO'),
+    ('MP', 'MP'),
+    ('MNP', 'MP'),
+    ('Northern Mariana Islands', 'MP'),
+    ('MQ', 'MQ'),
+    ('MTQ', 'MQ'),
+    ('Martinique', 'MQ'),
+    ('MR', 'MR'),
+    ('MRT', 'MR'),
+    ('Mauritania', 'MR'),
+    ('MS', 'MS'),
+    ('MSR', 'MS'),
+    ('Montserrat', 'MS'),
+    ('MT', 'MT'),
+    ('MLT', 'MT'),
+    ('Malta', 'MT'),
+    ('MU', 'MU'),
+    ('MUS', 'MU'),
+    ('Mauritius', 'MU'),
+    ('MV', 'MV'),
+    ('MDV', 'MV'),
+    ('Maldives', 'MV'),
+    ('MW', 'MW'),
+    ('MWI', 'MW'),
+    ('Malawi', 'MW'),
+    ('MX', 'MX'),
+    ('MEX', 'MX'),
+    ('Mexico', 'MX'),
+    ('MY', 'MY'),
+    ('MYS', 'MY'),
+    ('Malaysia', 'MY'),
+    ('MZ', 'MZ'),
+    ('MOZ', 'MZ'),
+    ('Mozambique', 'MZ'),
+    ('NA', 'NA'),
+    ('NAM', 'NA'),
+    ('Namibia', 'NA'),
+    ('NC', 'NC'),
+    ('NCL', 'NC'),
+    ('New Caledonia', 'NC'),
+    ('NE', 'NE'),
+    ('NER', 'NE'),
+    ('Niger', 'NE'),
+    ('NF', 'NF'),
+    ('NFK', 'NF'),
+    ('Norfolk Island', 'NF'),
+    ('NG', 'NG'),
+    ('NGA', 'NG'),
+    ('Nigeria', 'NG'),
+    ('NI', 'NI'),
+    ('NIC', 'NI'),
+    ('Nicaragua', 'NI'),
+    ('NL', 'NL'),
+    ('NLD', 'NL'),
+    ('Netherlands', 'NL'),
+    ('NO', 'NO'),
+    ('NOR', 'NO'),
+    ('Norway', 'NO'),
+    ('NP', 'NP'),
+    ('NPL', 'NP'),
+    ('Nepal', 'NP'),
+    ('NR', 'NR'),
+    ('NRU', 'NR'),
+    ('Nauru', 'NR'),
+    ('NU', 'NU'),
+    ('NIU', 'NU'),
+    ('Niue', 'NU'),
+    ('NZ', 'NZ'),
+    ('NZL', 'NZ'),
+    ('New Zealand', 'NZ'),
+    ('OM', 'OM'),
+    ('OMN', 'OM'),
+    ('Oman', 'OM'),
+    ('PA', 'PA'),
+    ('PAN', 'PA'),
+    ('Panama', 'PA'),
+    ('PE', 'PE'),
+    ('PER', 'PE'),
+    ('Peru', 'PE'),
+    ('PF', 'PF'),
+    ('PYF', 'PF'),
+    ('French Polynesia', 'PF'),
+    ('PG', 'PG'),
+    ('PNG', 'PG'),
+    ('Papua New Guinea', 'PG'),
+    ('PH', 'PH'),
+    ('PHL', 'PH'),
+    ('Philippines', 'PH'),
+    ('PK', 'PK'),
+    ('PAK', 'PK'),
+    ('Pakistan', 'PK'),
+    ('PL', 'PL'),
+    ('POL', 'PL'),
+    ('Poland', 'PL'),
+    ('PM', 'PM'),
+    ('SPM', 'PM'),
+    ('Saint Pierre and Miquelon', 'PM'),
+    ('PN', 'PN'),
+    ('PCN', 'PN'),
+    ('Pitcairn', 'PN'),
+    ('PR', 'PR'),
+    ('PRI', 'PR'),
+    ('Puerto Rico', 'PR'),
+    ('PS', 'PS'),
+    ('PSE', 'PS'),
+    ('Palestine, State of', 'PS'),
+    ('PT', 'PT'),
+    ('PRT', 'PT'),
+    ('Portugal', 'PT'),
+    ('PW', 'PW'),
+    ('PLW', 'PW'),
+    ('Palau', 'PW'),
+    ('PY', 'PY'),
+    ('PRY', 'PY'),
+    ('Paraguay', 'PY'),
+    ('QA', 'QA'),
+    ('QAT', 'QA'),
+    ('Qatar', 'QA'),
+    ('RE', 'RE'),
+    ('REU', 'RE'),
+    ('Réunion', 'RE'),
+    ('RO', 'RO'),
+    ('ROU', 'RO'),
+    ('Romania', 'RO'),
+    ('RS', 'RS'),
+    ('SRB', 'RS'),
+    ('Serbia', 'RS'),
+    ('RU', 'RU'),
+    ('RUS', 'RU'),
+    ('Russian Federation', 'RU'),
+    ('RW', 'RW'),
+    ('RWA', 'RW'),
+    ('Rwanda', 'RW'),
+    ('SA', 'SA'),
+    ('SAU', 'SA'),
+    ('Saudi Arabia', 'SA'),
+    ('SB', 'SB'),
+    ('SLB', 'SB'),
+    ('Solomon Islands', 'SB'),
+    ('SC', 'SC'),
+    ('SYC', 'SC'),
+    ('Seychelles', 'SC'),
+    ('SD', 'SD'),
+    ('SDN', 'SD'),
+    ('Sudan', 'SD'),
+    ('SE', 'SE'),
+    ('SWE', 'SE'),
+    ('Sweden', 'SE'),
+    ('SG', 'SG'),
+    ('SGP', 'SG'),
+    ('Singapore', 'SG'),
+    ('SH', 'SH'),
+    ('SHN', 'SH'),
+    ('Saint Helena, Ascension and Tristan da Cunha', 'SH'),
+    ('SI', 'SI'),
+    ('SVN', 'SI'),
+    ('Slovenia', 'SI'),
+    ('SJ', 'SJ'),
+    ('SJM', 'SJ'),
+    ('Svalbard and Jan Mayen', 'SJ'),
+    ('SK', 'SK'),
+    ('SVK', 'SK'),
+    ('Slovakia', 'SK'),
+    ('SL', 'SL'),
+    ('SLE', 'SL'),
+    ('Sierra Leone', 'SL'),
+    ('SM', 'SM'),
+    ('SMR', 'SM'),
+    ('San Marino', 'SM'),
+    ('SN', 'SN'),
+    ('SEN', 'SN'),
+    ('Senegal', 'SN'),
+    ('SO', 'SO'),
+    ('SOM', 'SO'),
+    ('Somalia', 'SO'),
+    ('SR', 'SR'),
+    ('SUR', 'SR'),
+    ('Suriname', 'SR'),
+    ('SS', 'SS'),
+    ('SSD', 'SS'),
+    ('South Sudan', 'SS'),
+    ('ST', 'ST'),
+    ('STP', 'ST'),
+    ('Sao Tome and Principe', 'ST'),
+    ('SV', 'SV'),
+    ('SLV', 'SV'),
+    ('El Salvador', 'SV'),
+    ('SX', 'SX'),
+    ('SXM', 'SX'),
+    ('Sint Maarten (Dutch part)', 'SX'),
+    ('SY', 'SY'),
+    ('SYR', 'SY'),
+    ('Syrian Arab Republic', 'SY'),
+    ('Syria', 'SY'),
+    ('SZ', 'SZ'),
+    ('SWZ', 'SZ'),
+    ('Eswatini', 'SZ'),
+    ('TC', 'TC'),
+    ('TCA', 'TC'),
+    ('Turks and Caicos Islands', 'TC'),
+    ('TD', 'TD'),
+    ('TCD', 'TD'),
+    ('Chad', 'TD'),
+    ('TF', 'TF'),
+    ('ATF', 'TF'),
+    ('French Southern Territories', 'TF'),
+    ('TG', 'TG'),
+    ('TGO', 'TG'),
+    ('Togo', 'TG'),
+    ('TH', 'TH'),
+    ('THA', 'TH'),
+    ('Thailand', 'TH'),
+    ('TJ', 'TJ'),
+    ('TJK', 'TJ'),
+    ('Tajikistan', 'TJ'),
+    ('TK', 'TK'),
+    ('TKL', 'TK'),
+    ('Tokelau', 'TK'),
+    ('TL', 'TL'),
+    ('TLS', 'TL'),
+    ('Timor-Leste', 'TL'),
+    ('TM', 'TM'),
+    ('TKM', 'TM'),
+    ('Turkmenistan', 'TM'),
+    ('TN', 'TN'),
+    ('TUN', 'TN'),
+    ('Tunisia', 'TN'),
+    ('TO', 'TO'),
+    ('TON', 'TO'),
+    ('Tonga', 'TO'),
+    ('TR', 'TR'),
+    ('TUR', 'TR'),
+    ('Türkiye', 'TR'),
+    ('TT', 'TT'),
+    ('TTO', 'TT'),
+    ('Trinidad and Tobago', 'TT'),
+    ('TV', 'TV'),
+    ('TUV', 'TV'),
+    ('Tuvalu', 'TV'),
+    ('TW', 'TW'),
+    ('TWN', 'TW'),
+    ('Taiwan, Province of China', 'TW'),
+    ('Taiwan', 'TW'),
+    ('TZ', 'TZ'),
+    ('TZA', 'TZ'),
+    ('Tanzania, United Republic of', 'TZ'),
+    ('Tanzania', 'TZ'),
+    ('UA', 'UA'),
+    ('UKR', 'UA'),
+    ('Ukraine', 'UA'),
+    ('UG', 'UG'),
+    ('UGA', 'UG'),
+    ('Uganda', 'UG'),
+    ('UM', 'UM'),
+    ('UMI', 'UM'),
+    ('United States Minor Outlying Islands', 'UM'),
+    ('US', 'US'),
+    ('USA', 'US'),
+    ('United States', 'US'),
+    ('UY', 'UY'),
+    ('URY', 'UY'),
+    ('Uruguay', 'UY'),
+    ('UZ', 'UZ'),
+    ('UZB', 'UZ'),
+    ('Uzbekistan', 'UZ'),
+    ('VA', 'VA'),
+    ('VAT', 'VA'),
+    ('Holy See (Vatican City State)', 'VA'),
+    ('VC', 'VC'),
+    ('VCT', 'VC'),
+    ('Saint Vincent and the Grenadines', 'VC'),
+    ('VE', 'VE'),
+    ('VEN', 'VE'),
+    ('Venezuela, Bolivarian Republic of', 'VE'),
+    ('Venezuela', 'VE'),
+    ('VG', 'VG'),
+    ('VGB', 'VG'),
+    ('Virgin Islands, British', 'VG'),
+    ('VI', 'VI'),
+    ('VIR', 'VI'),
+    ('Virgin Islands, U.S.', 'VI'),
+    ('VN', 'VN'),
+    ('VNM', 'VN'),
+    ('Viet Nam', 'VN'),
+    ('Vietnam', 'VN'),
+    ('VU', 'VU'),
+    ('VUT', 'VU'),
+    ('Vanuatu', 'VU'),
+    ('WF', 'WF'),
+    ('WLF', 'WF'),
+    ('Wallis and Futuna', 'WF'),
+    ('WS', 'WS'),
+    ('WSM', 'WS'),
+    ('Samoa', 'WS'),
+    ('YE', 'YE'),
+    ('YEM', 'YE'),
+    ('Yemen', 'YE'),
+    ('YT', 'YT'),
+    ('MYT', 'YT'),
+    ('Mayotte', 'YT'),
+    ('ZA', 'ZA'),
+    ('ZAF', 'ZA'),
+    ('South Africa', 'ZA'),
+    ('ZM', 'ZM'),
+    ('ZMB', 'ZM'),
+    ('Zambia', 'ZM'),
+    ('ZW', 'ZW'),
+    ('ZWE', 'ZW'),
+    ('Zimbabwe', 'ZW'),
+    ('UK', 'GB'),
+    ('Great Britain', 'GB'),
+    ('United States of America', 'US');
+
+UPDATE sellers AS s
+    INNER JOIN country_names AS c ON c.name = TRIM(s.location)
+SET s.country = c.code
+WHERE s.country IS NULL;
+
+DROP TEMPORARY TABLE country_names;
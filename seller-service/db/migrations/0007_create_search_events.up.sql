@@ -0,0 +1,14 @@
+-- one row per product search served, written in batches by the analytics recorder
+-- query is the normalized text searched for, filters the sorted names of the filters
+-- joined by commas, params the parameters of the search as JSON
+CREATE TABLE IF NOT EXISTS search_events (
+    id           BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    searched_at  DATETIME(3) NOT NULL,
+    query        VARCHAR(255) NOT NULL DEFAULT '',
+    filters      VARCHAR(1024) NOT NULL DEFAULT '',
+    params       TEXT NOT NULL,
+    result_count INT NOT NULL,
+    first_page   TINYINT(1) NOT NULL,
+    latency_us   BIGINT NOT NULL,
+    INDEX idx_search_event_searched_at (searched_at)
+);
@@ -0,0 +1,12 @@
+-- the synonym dictionary applied to the product searches, every term of a group matches the
+-- products named with the others
+CREATE TABLE IF NOT EXISTS synonym_groups (
+    id INT AUTO_INCREMENT PRIMARY KEY
+);
+
+CREATE TABLE IF NOT EXISTS synonym_terms (
+    group_id INT NOT NULL,
+    term     VARCHAR(64) NOT NULL,
+    PRIMARY KEY (group_id, term),
+    CONSTRAINT fk_synonym_term_group_id FOREIGN KEY (group_id) REFERENCES synonym_groups (id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+-- the product searches the buyers are alerted about, a max_price of 0 means no maximum
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id           INT PRIMARY KEY AUTO_INCREMENT,
+    buyer_id     INT NOT NULL,
+    name         VARCHAR(100) NOT NULL DEFAULT '',
+    product_name VARCHAR(255) NOT NULL DEFAULT '',
+    location     VARCHAR(255) NOT NULL DEFAULT '',
+    min_price    DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    max_price    DECIMAL(10, 2) NOT NULL DEFAULT 0,
+    desired_qty  INT NOT NULL DEFAULT 0,
+    created_at   DATETIME(3) NOT NULL,
+    INDEX idx_saved_search_buyer_id (buyer_id),
+    INDEX idx_saved_search_location (location)
+);
@@ -0,0 +1,2 @@
+DROP TABLE buyer_addresses;
+DROP TABLE buyers;
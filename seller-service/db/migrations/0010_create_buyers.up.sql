@@ -0,0 +1,26 @@
+-- the buyers and their address books, is_default marks the one default address of a buyer
+CREATE TABLE IF NOT EXISTS buyers (
+    id         INT PRIMARY KEY AUTO_INCREMENT,
+    name       VARCHAR(255) NOT NULL,
+    email      VARCHAR(255) NOT NULL,
+    phone      VARCHAR(32) NOT NULL DEFAULT '',
+    created_at DATETIME(3) NOT NULL,
+    UNIQUE KEY uq_buyer_email (email)
+);
+
+CREATE TABLE IF NOT EXISTS buyer_addresses (
+    id          INT PRIMARY KEY AUTO_INCREMENT,
+    buyer_id    INT NOT NULL,
+    label       VARCHAR(50) NOT NULL DEFAULT '',
+    recipient   VARCHAR(255) NOT NULL,
+    line1       VARCHAR(255) NOT NULL,
+    line2       VARCHAR(255) NOT NULL DEFAULT '',
+    city        VARCHAR(255) NOT NULL,
+    region      VARCHAR(255) NOT NULL DEFAULT '',
+    postal_code VARCHAR(32) NOT NULL DEFAULT '',
+    country     CHAR(2) NOT NULL,
+    phone       VARCHAR(32) NOT NULL DEFAULT '',
+    is_default  TINYINT(1) NOT NULL DEFAULT 0,
+    INDEX idx_buyer_address_buyer_id (buyer_id),
+    CONSTRAINT fk_buyer_address_buyer_id FOREIGN KEY (buyer_id) REFERENCES buyers (id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+-- the carts of the buyers, added_price and added_stock are the price and quantity of the
+-- product when the item was last changed, a cart read flags the items whose product changed
+CREATE TABLE IF NOT EXISTS cart_items (
+    buyer_id    INT NOT NULL,
+    product_id  INT NOT NULL,
+    quantity    INT NOT NULL,
+    added_price DECIMAL(10, 2) NOT NULL,
+    added_stock INT NOT NULL,
+    added_at    DATETIME(3) NOT NULL,
+    PRIMARY KEY (buyer_id, product_id),
+    INDEX idx_cart_item_product_id (product_id),
+    CONSTRAINT fk_cart_item_buyer_id FOREIGN KEY (buyer_id) REFERENCES buyers (id) ON DELETE CASCADE,
+    CONSTRAINT fk_cart_item_product_id FOREIGN KEY (product_id) REFERENCES products (id) ON DELETE CASCADE
+);
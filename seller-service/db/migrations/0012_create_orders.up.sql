@@ -0,0 +1,23 @@
+-- the orders of the buyers, their lines keep the seller, name and price of the products at
+-- purchase and neither points to the buyers or products so the orders outlive them
+CREATE TABLE IF NOT EXISTS orders (
+    id         INT PRIMARY KEY AUTO_INCREMENT,
+    buyer_id   INT NOT NULL,
+    total      DECIMAL(17, 2) NOT NULL,
+    created_at DATETIME(3) NOT NULL,
+    INDEX idx_order_buyer_id (buyer_id)
+);
+
+CREATE TABLE IF NOT EXISTS order_lines (
+    id           INT PRIMARY KEY AUTO_INCREMENT,
+    order_id     INT NOT NULL,
+    product_id   INT NOT NULL,
+    seller_id    INT NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    quantity     INT NOT NULL,
+    unit_price   DECIMAL(10, 2) NOT NULL,
+    subtotal     DECIMAL(15, 2) NOT NULL,
+    INDEX idx_order_line_order_id (order_id),
+    INDEX idx_order_line_product_id (product_id),
+    CONSTRAINT fk_order_line_order_id FOREIGN KEY (order_id) REFERENCES orders (id) ON DELETE CASCADE
+);
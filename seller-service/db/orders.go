@@ -0,0 +1,136 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// orderLineColumns are the columns scanned by scanOrderLine, in order
+const orderLineColumns = "product_id, seller_id, product_name, quantity, unit_price, subtotal"
+
+// scanOrderLine scans the orderLineColumns of the row into an order line
+func scanOrderLine(row rowScanner) (models.OrderLine, error) {
+	var l models.OrderLine
+	err := row.Scan(&l.ProductID, &l.SellerID, &l.ProductName, &l.Quantity, &l.UnitPrice, &l.Subtotal)
+	return l, err
+}
+
+// PlaceOrder locks the products of the order with SELECT ... FOR UPDATE, checks their stock,
+// takes the quantities off it and saves the order and its lines in a transaction. The rows are
+// locked in the order of their ids so concurrent orders wait on each other instead of
+// deadlocking, and an order waiting on a product sees the stock the other one left
+func (s *Store) PlaceOrder(ctx context.Context, o *models.Order) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var buyer int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM buyers WHERE id = ?`, o.BuyerID).Scan(&buyer)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrBuyerNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	ids := o.ProductIDs()
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	in := strings.TrimSuffix(strings.Repeat("?, ", len(ids)), ", ")
+	rows, err := tx.QueryContext(ctx, `SELECT `+productColumns+` FROM products AS p WHERE p.id IN (`+in+`) ORDER BY p.id FOR UPDATE`, args...)
+	if err != nil {
+		return err
+	}
+	products := make(map[int]models.Product, len(ids))
+	for rows.Next() {
+		p, err := scanProduct(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		products[p.ID] = p
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if err := o.Take(products); err != nil {
+		return err
+	}
+
+	for _, line := range o.Lines {
+		_, err := tx.ExecContext(ctx, `UPDATE products SET quantity = quantity - ? WHERE id = ?`, line.Quantity, line.ProductID)
+		if err != nil {
+			return err
+		}
+	}
+	// mysql keeps milliseconds
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+	result, err := tx.ExecContext(ctx, `INSERT INTO orders (buyer_id, total, created_at) VALUES (?, ?, ?)`, o.BuyerID, o.Total, createdAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	if err := saveOrderLines(ctx, tx, int(id), o.Lines); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `DELETE FROM cart_items WHERE buyer_id = ? AND product_id IN (`+in+`)`, append([]interface{}{o.BuyerID}, args...)...)
+	if err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	o.ID, o.CreatedAt = int(id), createdAt
+	return nil
+}
+
+// saveOrderLines inserts the lines of the order
+func saveOrderLines(ctx context.Context, q queryer, orderID int, lines []models.OrderLine) error {
+	args := make([]interface{}, 0, 7*len(lines))
+	for _, l := range lines {
+		args = append(args, orderID, l.ProductID, l.SellerID, l.ProductName, l.Quantity, l.UnitPrice, l.Subtotal)
+	}
+	_, err := q.ExecContext(ctx, `INSERT INTO order_lines (order_id, `+orderLineColumns+`) VALUES `+
+		strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?), ", len(lines)), ", "), args...)
+	return err
+}
+
+// GetOrderByID retrieves an order and its lines, in the order they were placed, by ID
+func (s *Store) GetOrderByID(ctx context.Context, id int) (models.Order, error) {
+	o := models.Order{ID: id}
+	err := s.conn.QueryRowContext(ctx, `SELECT buyer_id, total, created_at FROM orders WHERE id = ?`, id).
+		Scan(&o.BuyerID, &o.Total, &o.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return o, models.ErrOrderNotFound
+	}
+	if err != nil {
+		return o, err
+	}
+
+	rows, err := s.conn.QueryContext(ctx, `SELECT `+orderLineColumns+` FROM order_lines WHERE order_id = ? ORDER BY id`, id)
+	if err != nil {
+		return o, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		line, err := scanOrderLine(rows)
+		if err != nil {
+			return o, err
+		}
+		o.Lines = append(o.Lines, line)
+	}
+	return o, rows.Err()
+}
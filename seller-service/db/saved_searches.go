@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// savedSearchColumns are the columns scanned by scanSavedSearch
+const savedSearchColumns = `id, buyer_id, name, product_name, location, min_price, max_price, desired_qty, created_at`
+
+func scanSavedSearch(row rowScanner) (models.SavedSearch, error) {
+	var s models.SavedSearch
+	err := row.Scan(&s.ID, &s.BuyerID, &s.Name, &s.ProductName, &s.Location, &s.MinPrice, &s.MaxPrice, &s.DesiredQty, &s.CreatedAt)
+	return s, err
+}
+
+// querySavedSearches runs the query and scans the saved searches it returns
+func (s *Store) querySavedSearches(ctx context.Context, query string, args ...interface{}) ([]models.SavedSearch, error) {
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var searches []models.SavedSearch
+	for rows.Next() {
+		search, err := scanSavedSearch(rows)
+		if err != nil {
+			return nil, err
+		}
+		searches = append(searches, search)
+	}
+	return searches, rows.Err()
+}
+
+// CreateSavedSearch saves the search and sets its ID and CreatedAt
+func (s *Store) CreateSavedSearch(ctx context.Context, search *models.SavedSearch) error {
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+	result, err := s.conn.ExecContext(ctx, `INSERT INTO saved_searches
+		(buyer_id, name, product_name, location, min_price, max_price, desired_qty, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		search.BuyerID, search.Name, search.ProductName, search.Location, search.MinPrice, search.MaxPrice, search.DesiredQty, createdAt)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	search.ID, search.CreatedAt = int(id), createdAt
+	return nil
+}
+
+// GetSavedSearchByID retrieves a saved search by ID, returns models.ErrSavedSearchNotFound if it does not exist
+func (s *Store) GetSavedSearchByID(ctx context.Context, id int) (models.SavedSearch, error) {
+	search, err := scanSavedSearch(s.conn.QueryRowContext(ctx, `SELECT `+savedSearchColumns+` FROM saved_searches WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return search, models.ErrSavedSearchNotFound
+	}
+	return search, err
+}
+
+// ListSavedSearches returns the page of saved searches of the buyer of the filter, ordered by ID
+func (s *Store) ListSavedSearches(ctx context.Context, filter models.SavedSearchFilter) ([]models.SavedSearch, error) {
+	return s.querySavedSearches(ctx, `SELECT `+savedSearchColumns+` FROM saved_searches
+		WHERE buyer_id = ? ORDER BY id LIMIT ? OFFSET ?`, filter.BuyerID, filter.PerPage, filter.Offset())
+}
+
+// UpdateSavedSearch locks the saved search, replaces its name and filters and reads back its
+// buyer and creation time in a transaction
+func (s *Store) UpdateSavedSearch(ctx context.Context, search *models.SavedSearch) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `SELECT buyer_id, created_at FROM saved_searches WHERE id = ? FOR UPDATE`, search.ID).
+		Scan(&search.BuyerID, &search.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrSavedSearchNotFound
+	}
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE saved_searches SET name = ?, product_name = ?, location = ?,
+		min_price = ?, max_price = ?, desired_qty = ? WHERE id = ?`,
+		search.Name, search.ProductName, search.Location, search.MinPrice, search.MaxPrice, search.DesiredQty, search.ID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteSavedSearch deletes the saved search, returns models.ErrSavedSearchNotFound if it does not exist
+func (s *Store) DeleteSavedSearch(ctx context.Context, id int) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM saved_searches WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+// CandidateSavedSearches returns the saved searches whose location, price range and desired
+// quantity accept the product of the seller, ordered by ID. The product name is left to
+// SavedSearch.Matches, which applies the synonyms
+func (s *Store) CandidateSavedSearches(ctx context.Context, p models.Product, seller models.Seller) ([]models.SavedSearch, error) {
+	return s.querySavedSearches(ctx, `SELECT `+savedSearchColumns+` FROM saved_searches
+		WHERE desired_qty <= ? AND min_price <= ? AND (max_price = 0 OR max_price >= ?)
+		AND (location = '' OR location = ?) ORDER BY id`, p.Quantity, p.Price, p.Price, seller.Location)
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// maxSearchEventsPerInsert bounds the rows inserted by one statement, 7 placeholders each
+const maxSearchEventsPerInsert = 500
+
+// maxFiltersLength is the length of the filters column, longer combinations are cut after
+// the last filter fitting in it
+const maxFiltersLength = 1024
+
+// zeroResults is the condition of the searches that found no product, see models.SearchEvent
+const zeroResults = "first_page = 1 AND result_count = 0"
+
+// RecordSearches saves the events, a few hundred rows per statement
+func (s *Store) RecordSearches(ctx context.Context, events []models.SearchEvent) error {
+	for len(events) > 0 {
+		batch := events
+		if len(batch) > maxSearchEventsPerInsert {
+			batch = batch[:maxSearchEventsPerInsert]
+		}
+		events = events[len(batch):]
+
+		query := "INSERT INTO search_events (searched_at, query, filters, params, result_count, first_page, latency_us) VALUES " +
+			strings.TrimSuffix(strings.Repeat("(?, ?, ?, ?, ?, ?, ?), ", len(batch)), ", ")
+		args := make([]interface{}, 0, 7*len(batch))
+		for _, e := range batch {
+			args = append(args, e.At.UTC(), e.Query, joinFilters(e.Filters), e.Params, e.Results, e.FirstPage, e.Latency.Microseconds())
+		}
+		if _, err := s.conn.ExecContext(ctx, query, args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// joinFilters joins the names of the filters by commas, within maxFiltersLength
+func joinFilters(filters []string) string {
+	joined := strings.Join(filters, ",")
+	if len(joined) <= maxFiltersLength {
+		return joined
+	}
+	return joined[:strings.LastIndex(joined[:maxFiltersLength+1], ",")]
+}
+
+// TopSearchQueries returns the queries searched most within the window of the request
+func (s *Store) TopSearchQueries(ctx context.Context, req *models.SearchReportRequest) ([]models.SearchQueryStat, error) {
+	query := `SELECT query, COUNT(*) AS searches, SUM(` + zeroResults + `) AS zero_results,
+		ROUND(AVG(latency_us) / 1000, 2), MAX(searched_at)
+		FROM search_events WHERE searched_at >= ? AND searched_at < ? AND query <> '' GROUP BY query`
+	if req.ZeroResults {
+		query += ` HAVING zero_results > 0 ORDER BY zero_results DESC, searches DESC, query LIMIT ?`
+	} else {
+		query += ` ORDER BY searches DESC, query LIMIT ?`
+	}
+	rows, err := s.conn.QueryContext(ctx, query, req.From.UTC(), req.To.UTC(), req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.SearchQueryStat{}
+	for rows.Next() {
+		var stat models.SearchQueryStat
+		if err := rows.Scan(&stat.Query, &stat.Searches, &stat.ZeroResults, &stat.AvgLatencyMs, &stat.LastSearchedAt); err != nil {
+			return nil, err
+		}
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
+
+// TopSearchFilters returns the combinations of filters used most within the window of the request
+func (s *Store) TopSearchFilters(ctx context.Context, req *models.SearchReportRequest) ([]models.SearchFilterStat, error) {
+	rows, err := s.conn.QueryContext(ctx, `SELECT filters, COUNT(*) AS searches, SUM(`+zeroResults+`)
+		FROM search_events WHERE searched_at >= ? AND searched_at < ? AND filters <> ''
+		GROUP BY filters ORDER BY searches DESC, filters LIMIT ?`, req.From.UTC(), req.To.UTC(), req.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.SearchFilterStat{}
+	for rows.Next() {
+		var stat models.SearchFilterStat
+		var filters string
+		if err := rows.Scan(&filters, &stat.Searches, &stat.ZeroResults); err != nil {
+			return nil, err
+		}
+		stat.Filters = strings.Split(filters, ",")
+		stats = append(stats, stat)
+	}
+	return stats, rows.Err()
+}
@@ -0,0 +1,617 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// mysql error numbers raised by foreign key and unique key violations
+const (
+	// mysqlErrDupEntry is raised when a row has the value of a unique key of another row
+	mysqlErrDupEntry = 1062
+	// mysqlErrRowIsReferenced is raised when deleting a row another row points to
+	mysqlErrRowIsReferenced = 1451
+	// mysqlErrNoReferencedRow is raised when a foreign key points to a missing row
+	mysqlErrNoReferencedRow = 1452
+)
+
+// fkProductCategory is the foreign key of products to their category
+const fkProductCategory = "fk_product_category_id"
+
+// Store is the mysql implementation of models.ProductStore, models.SellerStore, models.CategoryStore,
+// models.SearchLogStore, models.SynonymStore, models.SavedSearchStore, models.BuyerStore,
+// models.CartStore and models.OrderStore
+type Store struct {
+	conn *sql.DB
+}
+
+// NewStore creates a Store that talks to db over the given connection
+func NewStore(conn *sql.DB) *Store {
+	return &Store{conn: conn}
+}
+
+// CreateProduct saves the product in the database using a transaction and sets its ID
+func (s *Store) CreateProduct(ctx context.Context, p *models.Product) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `INSERT  INTO products (seller_id, product_name, price, quantity, description, category_id) VALUES (?,?,?,?,?,?)`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	p.Score, p.Distance, p.Seller = 0, nil, nil
+	p.Attributes, p.Tags = models.NormalizeAttributes(p.Attributes, p.Tags)
+	result, err := stmt.ExecContext(ctx, p.SellerID, p.ProductName, p.Price, p.Quantity, p.Description, nullableID(p.CategoryID))
+	if err != nil {
+		tx.Rollback()
+		if isNoReferencedRow(err, fkProductCategory) {
+			return models.ErrCategoryNotFound
+		}
+		if isNoReferencedRow(err) {
+			return models.ErrSellerNotFound
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := saveAttributes(ctx, tx, int(id), p.Attributes); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := saveTags(ctx, tx, int(id), p.Tags); err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	p.ID = int(id)
+	p.Score = 0
+	return nil
+}
+
+// productColumns are the columns scanned by scanProduct, in order
+const productColumns = "p.id, p.seller_id, p.product_name, p.price, p.quantity, p.description, COALESCE(p.category_id, 0)"
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanProduct scans the productColumns of the row into a product
+func scanProduct(row rowScanner) (models.Product, error) {
+	var product models.Product
+	err := row.Scan(&product.ID, &product.SellerID, &product.ProductName, &product.Price, &product.Quantity, &product.Description, &product.CategoryID)
+	return product, err
+}
+
+// scanSearchResult scans a row of a search into a product: the productColumns, the
+// sellerColumns, then the relevance score when withScore is set and the distance when
+// withDistance is set
+func scanSearchResult(row rowScanner, withScore, withDistance bool) (models.Product, error) {
+	var product models.Product
+	var seller sellerScan
+	dest := []interface{}{&product.ID, &product.SellerID, &product.ProductName, &product.Price, &product.Quantity, &product.Description, &product.CategoryID}
+	dest = append(dest, seller.dest()...)
+	if withScore {
+		dest = append(dest, &product.Score)
+	}
+	if withDistance {
+		product.Distance = new(float64)
+		dest = append(dest, product.Distance)
+	}
+	err := row.Scan(dest...)
+	result := seller.result()
+	product.Seller = &result
+	return product, err
+}
+
+// GetProductByID retrieves a product by ID from the database
+func (s *Store) GetProductByID(ctx context.Context, id int) (models.Product, error) {
+	row := s.conn.QueryRowContext(ctx, `SELECT `+productColumns+` FROM products AS p WHERE p.id = ?`, id)
+	product, err := scanProduct(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return product, models.ErrProductNotFound
+	}
+	if err != nil {
+		return product, err
+	}
+	products := []models.Product{product}
+	err = loadAttributes(ctx, s.conn, products)
+	return products[0], err
+}
+
+// UpdateProduct locks the product row, applies the update and saves it in a transaction
+func (s *Store) UpdateProduct(ctx context.Context, id int, update models.ProductUpdate) (models.Product, error) {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Product{}, err
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `SELECT `+productColumns+` FROM products AS p WHERE p.id = ? FOR UPDATE`, id)
+	product, err := scanProduct(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return product, models.ErrProductNotFound
+	}
+	if err != nil {
+		return product, err
+	}
+	products := []models.Product{product}
+	if err := loadAttributes(ctx, tx, products); err != nil {
+		return product, err
+	}
+	product = products[0]
+
+	update.Apply(&product)
+	_, err = tx.ExecContext(ctx, `UPDATE products SET product_name = ?, price = ?, quantity = ?, description = ?, category_id = ? WHERE id = ?`,
+		product.ProductName, product.Price, product.Quantity, product.Description, nullableID(product.CategoryID), id)
+	if isNoReferencedRow(err, fkProductCategory) {
+		return product, models.ErrCategoryNotFound
+	}
+	if err != nil {
+		return product, err
+	}
+	if update.Attributes != nil {
+		if err := saveAttributes(ctx, tx, id, product.Attributes); err != nil {
+			return product, err
+		}
+	}
+	if update.Tags != nil {
+		if err := saveTags(ctx, tx, id, product.Tags); err != nil {
+			return product, err
+		}
+	}
+	return product, tx.Commit()
+}
+
+// DeleteProduct deletes the product from the database
+func (s *Store) DeleteProduct(ctx context.Context, id int) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM products WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return models.ErrProductNotFound
+	}
+	return nil
+}
+
+// SearchProducts - This frames the sql queries based on the fields supplied to ProductRequest
+// It filters the matching records and returns them and error if any
+//
+// Pages are read with keyset pagination when the request has a cursor: the rows sorted after
+// the cursor, on the sort column then on the id, so the position is kept while products are
+// added or removed and deep pages cost no more than the first one
+func (s *Store) SearchProducts(ctx context.Context, p *models.ProductRequest) (models.ProductPage, error) {
+	where, whereArgs := productFilters(p)
+	var args []interface{}
+	query := "SELECT " + productColumns + ", " + sellerColumns
+	if p.Query != "" {
+		query += ", " + relevance + " AS score"
+		args = append(args, p.TextQuery().BooleanMode())
+	}
+	if p.Near != nil {
+		query += ", " + distance + " AS distance"
+		args = append(args, distanceArgs(p)...)
+	}
+	query += " FROM products AS p INNER JOIN sellers AS s ON p.seller_id = s.id WHERE 1=1" + where
+	args = append(args, whereArgs...)
+
+	// Sort by the keys of sortBy, the id breaks the ties so the order is stable. Reading
+	// backwards reverses the order, the page is put back in order once read
+	keys := sortKeys(p)
+	backward := p.Cursor != nil && p.Cursor.Backward
+	if p.Cursor != nil {
+		// Rows sorted after the cursor on the first key, or equal on it and sorted after it
+		// on the second, and so on down to the id
+		var conditions []string
+		var equal string
+		var equalArgs []interface{}
+		for _, key := range keys {
+			conditions = append(conditions, "("+equal+key.column+" "+after(key.descending != backward)+" ?)")
+			args = append(append(args, equalArgs...), key.args(p)...)
+			args = append(args, key.value(p.Cursor))
+			equal += key.column + " = ? AND "
+			equalArgs = append(append(equalArgs, key.args(p)...), key.value(p.Cursor))
+		}
+		query += " AND (" + strings.Join(conditions, " OR ") + ")"
+	}
+	var order []string
+	for _, key := range keys {
+		order = append(order, key.column+" "+direction(key.descending != backward))
+		args = append(args, key.args(p)...)
+	}
+	query += " ORDER BY " + strings.Join(order, ", ")
+
+	// Add pagination to the query, one more row than the page tells whether there are more
+	query += " LIMIT ? OFFSET ?"
+	args = append(args, p.PerPage+1, p.Offset())
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return models.ProductPage{}, err
+	}
+	defer rows.Close()
+
+	// Process the result set and create a list of products
+	var products []models.Product
+	for rows.Next() {
+		product, err := scanSearchResult(rows, p.Query != "", p.Near != nil)
+		if err != nil {
+			return models.ProductPage{}, err
+		}
+		products = append(products, product)
+	}
+	if err := rows.Err(); err != nil {
+		return models.ProductPage{}, err
+	}
+
+	page := models.ProductPage{Products: products}
+	if uint64(len(products)) > p.PerPage {
+		page.Products, page.HasMore = products[:p.PerPage], true
+	}
+	if err := loadAttributes(ctx, s.conn, page.Products); err != nil {
+		return models.ProductPage{}, err
+	}
+	if backward {
+		for i, j := 0, len(page.Products)-1; i < j; i, j = i+1, j-1 {
+			page.Products[i], page.Products[j] = page.Products[j], page.Products[i]
+		}
+	}
+	return page, nil
+}
+
+// CountProducts returns the number of products matching the filters of the request
+func (s *Store) CountProducts(ctx context.Context, p *models.ProductRequest) (int, error) {
+	where, args := productFilters(p)
+	var count int
+	err := s.conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM products AS p INNER JOIN sellers AS s ON p.seller_id = s.id WHERE 1=1"+where, args...).Scan(&count)
+	return count, err
+}
+
+// relevance is the score of a product for the full-text query bound to its placeholder
+const relevance = "MATCH(p.product_name, p.description) AGAINST (? IN BOOLEAN MODE)"
+
+// sortColumn is the expression products are sorted on for a field of sortBy
+type sortColumn struct {
+	column string
+	// descending is set when the field is sorted in descending order
+	descending bool
+	// bind returns the values of the placeholders of column, bound every time it appears, nil
+	// when it has none
+	bind func(p *models.ProductRequest) []interface{}
+	// value is the value of the column at a cursor
+	value func(c *models.Cursor) interface{}
+}
+
+// args are the values bound to the placeholders of the column
+func (c sortColumn) args(p *models.ProductRequest) []interface{} {
+	if c.bind == nil {
+		return nil
+	}
+	return c.bind(p)
+}
+
+// textQueryArgs binds the full-text query of the request to the placeholder of relevance
+func textQueryArgs(p *models.ProductRequest) []interface{} {
+	return []interface{}{p.TextQuery().BooleanMode()}
+}
+
+// sortColumns are the columns products are sorted on for each field of sortBy, in ascending order
+var sortColumns = map[string]sortColumn{
+	models.SortPrice:       {column: "p.price", value: func(c *models.Cursor) interface{} { return c.Price }},
+	models.SortProductName: {column: "p.product_name", value: func(c *models.Cursor) interface{} { return c.ProductName }},
+	models.SortSellerID:    {column: "p.seller_id", value: func(c *models.Cursor) interface{} { return c.SellerID }},
+	models.SortProductID:   {column: "p.id", value: func(c *models.Cursor) interface{} { return c.ID }},
+	models.SortSellerName:  {column: "s.name", value: func(c *models.Cursor) interface{} { return c.SellerName }},
+	models.SortLocation:    {column: "s.location", value: func(c *models.Cursor) interface{} { return c.Location }},
+	models.SortRelevance:   {column: relevance, descending: true, bind: textQueryArgs, value: func(c *models.Cursor) interface{} { return c.Score }},
+	models.SortDistance:    {column: distance, bind: distanceArgs, value: func(c *models.Cursor) interface{} { return c.Distance }},
+}
+
+// sortKeys returns the columns of the sort order of the request, ending with the id unless
+// the order already sorts on it
+func sortKeys(p *models.ProductRequest) []sortColumn {
+	var keys []sortColumn
+	order := p.SortOrder()
+	for _, key := range order {
+		column := sortColumns[key.Field]
+		column.descending = column.descending != key.Descending
+		keys = append(keys, column)
+	}
+	if !order.Has(models.SortProductID) {
+		keys = append(keys, sortColumns[models.SortProductID])
+	}
+	return keys
+}
+
+// after is the operator selecting the rows sorted after a value
+func after(descending bool) string {
+	if descending {
+		return "<"
+	}
+	return ">"
+}
+
+func direction(descending bool) string {
+	if descending {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// productFilters frames the conditions of the filters of the request, products are aliased p
+// and their sellers s
+func productFilters(p *models.ProductRequest) (string, []interface{}) {
+	var query string
+	var args []interface{}
+
+	if p.SellerID > 0 {
+		query += " AND p.seller_id = ? "
+		args = append(args, p.SellerID)
+	}
+	if p.ProductName != "" {
+		// the name or one of its expansions by the synonyms
+		names := p.ProductNames()
+		query += " AND (" + strings.TrimSuffix(strings.Repeat("p.product_name LIKE ? OR ", len(names)), " OR ") + ") "
+		for _, name := range names {
+			args = append(args, "%"+likeEscaper.Replace(name)+"%")
+		}
+	}
+	if p.DesiredQty > 0 {
+		query += " AND p.quantity >= ? "
+		args = append(args, p.DesiredQty)
+	}
+
+	if p.Location != "" {
+		query += " AND s.location = ? "
+		args = append(args, p.Location)
+	}
+	geo, geoArgs := geoFilters(p)
+	query += geo
+	args = append(args, geoArgs...)
+	if p.CategoryID > 0 {
+		// the category and its descendants, whose paths start with its own
+		query += " AND p.category_id IN (SELECT c.id FROM categories AS c INNER JOIN categories AS root ON c.path LIKE CONCAT(root.path, '%') WHERE root.id = ?)"
+		args = append(args, p.CategoryID)
+	}
+	if p.MinPrice > 0 {
+		query += " AND p.price >= ?"
+		args = append(args, p.MinPrice)
+	}
+	if p.MaxPrice > 0 {
+		query += " AND p.price <= ?"
+		args = append(args, p.MaxPrice)
+	}
+	if p.Query != "" {
+		query += " AND " + relevance
+		args = append(args, p.TextQuery().BooleanMode())
+	}
+	attributes, attributeArgs := attributeFilters(p)
+	query += attributes
+	args = append(args, attributeArgs...)
+	if p.Filter != nil {
+		condition, filterArgs := compileFilter(p.Filter)
+		query += " AND " + condition
+		args = append(args, filterArgs...)
+	}
+	return query, args
+}
+
+// CreateSeller saves the seller in the database using a transaction and sets its ID
+func (s *Store) CreateSeller(ctx context.Context, seller *models.Seller) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	stmt, err := tx.PrepareContext(ctx, `
+		INSERT INTO sellers (name, location, country, city, postal_code, latitude, longitude)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+
+	seller.Normalize()
+	result, err := stmt.ExecContext(ctx, append([]interface{}{seller.Name, seller.Location}, addressValues(seller.Address)...)...)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Retrieve the last inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	seller.ID = int(id)
+	return nil
+}
+
+// GetSellerByID retrieves a seller by ID from the database
+// Args:
+//
+//	id int: seller id
+//
+// Returns:
+//
+//	Seller: Seller Object
+//	error: models.ErrSellerNotFound or root cause of error
+func (s *Store) GetSellerByID(ctx context.Context, id int) (models.Seller, error) {
+	row := s.conn.QueryRowContext(ctx, `
+		SELECT `+sellerColumns+`
+		FROM sellers AS s
+		WHERE s.id = ?
+	`, id)
+
+	seller, err := scanSeller(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return seller, models.ErrSellerNotFound
+		}
+		return seller, err
+	}
+
+	return seller, nil
+}
+
+// ListSellers returns the page of sellers matching the filter
+func (s *Store) ListSellers(ctx context.Context, filter models.SellerFilter) ([]models.Seller, error) {
+	query := `SELECT ` + sellerColumns + ` FROM sellers AS s WHERE 1=1`
+	var args []interface{}
+	if filter.Location != "" {
+		query += " AND s.location = ?"
+		args = append(args, filter.Location)
+	}
+	if len(filter.Countries) > 0 {
+		query += " AND s.country IN (" + strings.TrimSuffix(strings.Repeat("?, ", len(filter.Countries)), ", ") + ")"
+		for _, code := range filter.Countries {
+			args = append(args, code)
+		}
+	}
+	query += " ORDER BY s.id LIMIT ? OFFSET ?"
+	args = append(args, filter.PerPage, filter.Offset())
+
+	rows, err := s.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sellers []models.Seller
+	for rows.Next() {
+		seller, err := scanSeller(rows)
+		if err != nil {
+			return nil, err
+		}
+		sellers = append(sellers, seller)
+	}
+	return sellers, rows.Err()
+}
+
+// UpdateSeller locks the seller row, applies the update and saves it in a transaction
+func (s *Store) UpdateSeller(ctx context.Context, id int, update models.SellerUpdate) (models.Seller, error) {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Seller{}, err
+	}
+	defer tx.Rollback()
+
+	seller, err := scanSeller(tx.QueryRowContext(ctx, `SELECT `+sellerColumns+` FROM sellers AS s WHERE s.id = ? FOR UPDATE`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return seller, models.ErrSellerNotFound
+	}
+	if err != nil {
+		return seller, err
+	}
+
+	update.Apply(&seller)
+	args := append([]interface{}{seller.Name, seller.Location}, addressValues(seller.Address)...)
+	_, err = tx.ExecContext(ctx, `UPDATE sellers SET name = ?, location = ?, country = ?, city = ?, postal_code = ?, latitude = ?, longitude = ? WHERE id = ?`, append(args, id)...)
+	if err != nil {
+		return seller, err
+	}
+	return seller, tx.Commit()
+}
+
+// DeleteSeller deletes the seller, and its products when cascade is set, in a transaction
+func (s *Store) DeleteSeller(ctx context.Context, id int, cascade bool) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var found int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM sellers WHERE id = ? FOR UPDATE`, id).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrSellerNotFound
+	}
+	if err != nil {
+		return err
+	}
+
+	if cascade {
+		_, err = tx.ExecContext(ctx, `DELETE FROM products WHERE seller_id = ?`, id)
+		if err != nil {
+			return err
+		}
+	} else {
+		var products int
+		err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM products WHERE seller_id = ?`, id).Scan(&products)
+		if err != nil {
+			return err
+		}
+		if products > 0 {
+			return models.ErrSellerHasProducts
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM sellers WHERE id = ?`, id)
+	if err != nil {
+		if isRowIsReferenced(err) {
+			return models.ErrSellerHasProducts
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+// isNoReferencedRow reports whether err is a foreign key violation on insert/update, of one
+// of the given constraints when any is given
+func isNoReferencedRow(err error, constraints ...string) bool {
+	var mysqlErr *mysql.MySQLError
+	if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlErrNoReferencedRow {
+		return false
+	}
+	for _, constraint := range constraints {
+		if strings.Contains(mysqlErr.Message, "CONSTRAINT `"+constraint+"`") {
+			return true
+		}
+	}
+	return len(constraints) == 0
+}
+
+// isRowIsReferenced reports whether err is a foreign key violation on delete
+func isRowIsReferenced(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrRowIsReferenced
+}
+
+// isDuplicateEntry reports whether err is a unique key violation
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry
+}
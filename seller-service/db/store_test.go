@@ -0,0 +1,544 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"math"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/config"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/memstore"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// openTestDatabase connects to the mysql instance described by the MYSQL_* variables,
+// the test is skipped when no instance is configured
+func openTestDatabase(t *testing.T) *sql.DB {
+	t.Helper()
+	cfg, err := config.Load(flag.NewFlagSet("test", flag.ContinueOnError), nil, os.Getenv)
+	if err != nil || cfg.MySQL.Host == "" {
+		t.Skip("MYSQL_* variables are not set, skipping mysql store tests")
+	}
+	conn, err := Open(cfg.MySQL)
+	if err != nil {
+		t.Fatalf("failed to connect to the database: %v", err)
+	}
+	migrator, err := NewMigrator(conn)
+	if err != nil {
+		t.Fatalf("NewMigrator: %v", err)
+	}
+	if err := migrator.Up(context.Background()); err != nil {
+		t.Fatalf("failed to migrate the database: %v", err)
+	}
+	t.Cleanup(func() {
+		truncateTables(t, conn, "product_tags", "product_attributes", "products", "categories", "sellers", "search_events", "synonym_terms", "synonym_groups", "saved_searches", "cart_items", "buyer_addresses", "buyers", "order_lines", "orders")
+		conn.Close()
+	})
+	truncateTables(t, conn, "product_tags", "product_attributes", "products", "categories", "sellers", "search_events", "synonym_terms", "synonym_groups", "saved_searches", "cart_items", "buyer_addresses", "buyers", "order_lines", "orders")
+	return conn
+}
+
+func truncateTables(t *testing.T, conn *sql.DB, tables ...string) {
+	t.Helper()
+	// TRUNCATE is refused on tables referenced by a foreign key
+	if _, err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0"); err != nil {
+		t.Fatalf("Failed to disable foreign key checks: %v", err)
+	}
+	defer conn.Exec("SET FOREIGN_KEY_CHECKS = 1")
+	for _, table := range tables {
+		if _, err := conn.Exec("TRUNCATE TABLE " + table); err != nil {
+			t.Fatalf("Failed to truncate table %s: %v", table, err)
+		}
+	}
+}
+
+func TestStore(t *testing.T) {
+	store := NewStore(openTestDatabase(t))
+	ctx := context.Background()
+
+	seller := models.Seller{Name: "Seller A", Location: "IND"}
+	if err := store.CreateSeller(ctx, &seller); err != nil {
+		t.Fatalf("CreateSeller: %v", err)
+	}
+	got, err := store.GetSellerByID(ctx, seller.ID)
+	if err != nil || got != seller {
+		t.Fatalf("GetSellerByID = %+v, %v, want %+v", got, err, seller)
+	}
+	if _, err := store.GetSellerByID(ctx, seller.ID+1); !errors.Is(err, models.ErrSellerNotFound) {
+		t.Errorf("Expected ErrSellerNotFound, got %v", err)
+	}
+
+	err = store.CreateProduct(ctx, &models.Product{SellerID: seller.ID + 1, ProductName: "Drone", Price: 10, Quantity: 1})
+	if !errors.Is(err, models.ErrSellerNotFound) {
+		t.Errorf("Expected ErrSellerNotFound, got %v", err)
+	}
+	for _, product := range []models.Product{
+		{SellerID: seller.ID, ProductName: "Smartphone", Price: 30, Quantity: 1},
+		{SellerID: seller.ID, ProductName: "Smartwatch", Price: 20, Quantity: 3},
+	} {
+		product := product
+		if err := store.CreateProduct(ctx, &product); err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+	}
+
+	page, err := store.SearchProducts(ctx, &models.ProductRequest{ProductName: "SMART", SortBy: "price", Page: 1, PerPage: 10})
+	if err != nil {
+		t.Fatalf("SearchProducts: %v", err)
+	}
+	products := page.Products
+	if len(products) != 2 || products[0].ProductName != "Smartwatch" || products[1].ProductName != "Smartphone" {
+		t.Errorf("Unexpected search result: %+v", products)
+	}
+	cursor := models.CursorAt(products[0], "price", false)
+	page, err = store.SearchProducts(ctx, &models.ProductRequest{ProductName: "SMART", SortBy: "price", PerPage: 1, Cursor: &cursor})
+	if err != nil || len(page.Products) != 1 || page.Products[0].ID != products[1].ID || page.HasMore {
+		t.Errorf("Expected the page after the cursor to hold %+v, got %+v, %v", products[1], page, err)
+	}
+	cursor = models.CursorAt(products[1], "price", true)
+	page, err = store.SearchProducts(ctx, &models.ProductRequest{ProductName: "SMART", SortBy: "price", PerPage: 1, Cursor: &cursor})
+	if err != nil || len(page.Products) != 1 || page.Products[0].ID != products[0].ID || page.HasMore {
+		t.Errorf("Expected the page before the cursor to hold %+v, got %+v, %v", products[0], page, err)
+	}
+	if count, err := store.CountProducts(ctx, &models.ProductRequest{ProductName: "SMART"}); err != nil || count != 2 {
+		t.Errorf("CountProducts = %d, %v, want 2", count, err)
+	}
+
+	mouse := models.Product{SellerID: seller.ID, ProductName: "Mouse, Wireless", Price: 15, Quantity: 1, Description: "Ergonomic"}
+	if err := store.CreateProduct(ctx, &mouse); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	facets, err := store.FacetProducts(ctx, &models.ProductRequest{
+		MinPrice: 25,
+		Facets:   []string{models.FacetLocation, models.FacetPriceRange, models.FacetSellerID},
+	})
+	if err != nil {
+		t.Fatalf("FacetProducts: %v", err)
+	}
+	if got := facets[models.FacetLocation]; len(got) != 1 || got[0].Value != "IND" || got[0].Count != 1 {
+		t.Errorf("Unexpected location facet %+v", got)
+	}
+	if got := facets[models.FacetSellerID]; len(got) != 1 || got[0].Label != seller.Name {
+		t.Errorf("Unexpected seller facet %+v", got)
+	}
+	if got := facets[models.FacetPriceRange]; len(got) != len(models.PriceRanges) || got[1].Count != 2 {
+		t.Errorf("Expected the price facet to ignore the price filter, got %+v", got)
+	}
+
+	for _, q := range []string{"wireless mouse", "wire*", `"mouse wireless"`} {
+		page, err = store.SearchProducts(ctx, &models.ProductRequest{Query: q, SortBy: "relevance", Page: 1, PerPage: 10})
+		if err != nil || len(page.Products) != 1 || page.Products[0].ID != mouse.ID || page.Products[0].Score <= 0 {
+			t.Errorf("Expected %q to find the mouse with a score, got %+v, %v", q, page, err)
+		}
+	}
+
+	filter := &models.Filter{Or: []*models.Filter{
+		{Contains: &models.FieldValue{Field: "description", Value: "ergo"}},
+		{Range: &models.FieldRange{Field: "price", Gt: &mouse.Price}},
+	}}
+	page, err = store.SearchProducts(ctx, &models.ProductRequest{Filter: filter, SortBy: "-price", Page: 1, PerPage: 10})
+	if err != nil || len(page.Products) != 3 || page.Products[2].ID != mouse.ID {
+		t.Errorf("Expected the filter to find the mouse and the products above its price, got %+v, %v", page, err)
+	}
+
+	electronics := models.Category{Name: "Electronics"}
+	if err := store.CreateCategory(ctx, &electronics); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	audio := models.Category{Name: "Audio", ParentID: electronics.ID}
+	if err := store.CreateCategory(ctx, &audio); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	if err := store.CreateCategory(ctx, &models.Category{Name: "Orphan", ParentID: audio.ID + 1}); !errors.Is(err, models.ErrCategoryParentNotFound) {
+		t.Errorf("Expected ErrCategoryParentNotFound, got %v", err)
+	}
+	if _, err := store.UpdateCategory(ctx, electronics.ID, models.CategoryUpdate{ParentID: &audio.ID}); !errors.Is(err, models.ErrCategoryCycle) {
+		t.Errorf("Expected ErrCategoryCycle, got %v", err)
+	}
+	if _, err := store.UpdateProduct(ctx, mouse.ID, models.ProductUpdate{CategoryID: &audio.ID}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	missing := audio.ID + 1
+	if _, err := store.UpdateProduct(ctx, mouse.ID, models.ProductUpdate{CategoryID: &missing}); !errors.Is(err, models.ErrCategoryNotFound) {
+		t.Errorf("Expected ErrCategoryNotFound, got %v", err)
+	}
+	page, err = store.SearchProducts(ctx, &models.ProductRequest{CategoryID: electronics.ID, Page: 1, PerPage: 10})
+	if err != nil || len(page.Products) != 1 || page.Products[0].ID != mouse.ID || page.Products[0].CategoryID != audio.ID {
+		t.Errorf("Expected the category filter to include descendants, got %+v, %v", page, err)
+	}
+	tree, err := store.CategoryTree(ctx)
+	if err != nil || len(tree) != 1 || tree[0].ProductCount != 1 || len(tree[0].Children) != 1 || tree[0].Children[0].ID != audio.ID {
+		t.Errorf("Unexpected category tree %+v, %v", tree, err)
+	}
+	if err := store.DeleteCategory(ctx, audio.ID); !errors.Is(err, models.ErrCategoryNotEmpty) {
+		t.Errorf("Expected ErrCategoryNotEmpty, got %v", err)
+	}
+
+	attributes := map[string]string{"Color": "red", "dpi": "1600"}
+	if _, err := store.UpdateProduct(ctx, mouse.ID, models.ProductUpdate{Attributes: attributes, Tags: []string{"Wireless"}}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	withAttributes, err := store.GetProductByID(ctx, mouse.ID)
+	if err != nil || !reflect.DeepEqual(withAttributes.Attributes, map[string]string{"color": "red", "dpi": "1600"}) || !reflect.DeepEqual(withAttributes.Tags, []string{"wireless"}) {
+		t.Errorf("Expected the attributes and tags of the mouse, got %+v, %v", withAttributes, err)
+	}
+	page, err = store.SearchProducts(ctx, &models.ProductRequest{
+		Attributes: []models.AttributeFilter{{Name: "color", Op: "=", Value: "RED"}, {Name: "dpi", Op: ">", Value: "1000"}},
+		Tags:       []string{"wireless"},
+		Page:       1, PerPage: 10,
+	})
+	if err != nil || len(page.Products) != 1 || page.Products[0].Attributes["dpi"] != "1600" {
+		t.Errorf("Expected the attribute filters to find the mouse, got %+v, %v", page, err)
+	}
+	if summaries, err := store.ListAttributes(ctx); err != nil || len(summaries) != 2 || summaries[0].Name != "color" || summaries[0].ProductCount != 1 {
+		t.Errorf("Unexpected attributes %+v, %v", summaries, err)
+	}
+
+	quantity := 50
+	updated, err := store.UpdateProduct(ctx, products[0].ID, models.ProductUpdate{Quantity: &quantity})
+	if err != nil || updated.Quantity != quantity || updated.ProductName != "Smartwatch" {
+		t.Fatalf("UpdateProduct = %+v, %v", updated, err)
+	}
+	if got, err := store.GetProductByID(ctx, updated.ID); err != nil || !reflect.DeepEqual(got, updated) {
+		t.Errorf("GetProductByID = %+v, %v, want %+v", got, err, updated)
+	}
+	if err := store.DeleteProduct(ctx, updated.ID); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+	if err := store.DeleteProduct(ctx, updated.ID); !errors.Is(err, models.ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound deleting twice, got %v", err)
+	}
+	if _, err := store.UpdateProduct(ctx, updated.ID, models.ProductUpdate{Quantity: &quantity}); !errors.Is(err, models.ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound updating a deleted product, got %v", err)
+	}
+
+	location := "US"
+	if updated, err := store.UpdateSeller(ctx, seller.ID, models.SellerUpdate{Location: &location}); err != nil || updated.Location != location {
+		t.Errorf("UpdateSeller = %+v, %v", updated, err)
+	}
+	if sellers, err := store.ListSellers(ctx, models.SellerFilter{Location: "us", Page: 1, PerPage: 10}); err != nil || len(sellers) != 1 {
+		t.Errorf("ListSellers = %+v, %v", sellers, err)
+	}
+	if sellers, err := store.ListSellers(ctx, models.SellerFilter{Countries: []string{"us"}, Page: 1, PerPage: 10}); err != nil || len(sellers) != 1 || sellers[0].Address.Country != "US" {
+		t.Errorf("ListSellers by country = %+v, %v", sellers, err)
+	}
+
+	lat, lng := 12.2958, 76.6394
+	nearby := models.Seller{Name: "Seller Mysuru", Location: "Mysuru", Address: &models.Address{Country: "in", City: "Mysuru", Latitude: &lat, Longitude: &lng}}
+	if err := store.CreateSeller(ctx, &nearby); err != nil {
+		t.Fatalf("CreateSeller: %v", err)
+	}
+	if got, err := store.GetSellerByID(ctx, nearby.ID); err != nil || !reflect.DeepEqual(got, nearby) || got.Address.Country != "IN" {
+		t.Errorf("GetSellerByID = %+v, %v, want %+v", got, err, nearby)
+	}
+	lamp := models.Product{SellerID: nearby.ID, ProductName: "Lamp", Price: 20, Quantity: 1}
+	if err := store.CreateProduct(ctx, &lamp); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	near := &models.ProductRequest{Near: &models.GeoPoint{Lat: 12.9716, Lng: 77.5946}, RadiusKm: 200, SortBy: "distance", Page: 1, PerPage: 10}
+	page, err = store.SearchProducts(ctx, near)
+	if err != nil || len(page.Products) != 1 || page.Products[0].ID != lamp.ID || page.Products[0].Distance == nil {
+		t.Fatalf("SearchProducts near = %+v, %v", page, err)
+	}
+	if want := models.DistanceKm(*near.Near, models.GeoPoint{Lat: lat, Lng: lng}); math.Abs(*page.Products[0].Distance-want) > 1e-6 {
+		t.Errorf("Expected a distance of %v, got %v", want, *page.Products[0].Distance)
+	}
+	near.RadiusKm = 100
+	if count, err := store.CountProducts(ctx, near); err != nil || count != 0 {
+		t.Errorf("CountProducts within 100km = %d, %v", count, err)
+	}
+	if err := store.DeleteSeller(ctx, seller.ID, false); !errors.Is(err, models.ErrSellerHasProducts) {
+		t.Errorf("Expected ErrSellerHasProducts, got %v", err)
+	}
+	if err := store.DeleteSeller(ctx, seller.ID, true); err != nil {
+		t.Errorf("DeleteSeller with cascade: %v", err)
+	}
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	events := []models.SearchEvent{
+		{At: now, Query: "drone", Filters: []string{"maxPrice", "q"}, Params: "{}", Results: 3, FirstPage: true, Latency: time.Millisecond},
+		{At: now, Query: "drone", Filters: []string{"maxPrice", "q"}, Params: "{}", Results: 0, FirstPage: false, Latency: 3 * time.Millisecond},
+		{At: now, Query: "flux capacitor", Filters: []string{"productName"}, Params: "{}", Results: 0, FirstPage: true, Latency: time.Millisecond},
+	}
+	if err := store.RecordSearches(ctx, events); err != nil {
+		t.Fatalf("RecordSearches: %v", err)
+	}
+	report := &models.SearchReportRequest{From: now.Add(-time.Hour), To: now.Add(time.Hour), Limit: 10}
+	queries, err := store.TopSearchQueries(ctx, report)
+	want := []models.SearchQueryStat{
+		{Query: "drone", Searches: 2, AvgLatencyMs: 2, LastSearchedAt: now},
+		{Query: "flux capacitor", Searches: 1, ZeroResults: 1, AvgLatencyMs: 1, LastSearchedAt: now},
+	}
+	if err != nil || !reflect.DeepEqual(queries, want) {
+		t.Errorf("TopSearchQueries = %+v, %v, want %+v", queries, err, want)
+	}
+	report.ZeroResults = true
+	if queries, err := store.TopSearchQueries(ctx, report); err != nil || len(queries) != 1 || queries[0].Query != "flux capacitor" {
+		t.Errorf("TopSearchQueries without results = %+v, %v", queries, err)
+	}
+	filters, err := store.TopSearchFilters(ctx, report)
+	if err != nil || len(filters) != 2 || !reflect.DeepEqual(filters[0].Filters, []string{"maxPrice", "q"}) || filters[0].Searches != 2 {
+		t.Errorf("TopSearchFilters = %+v, %v", filters, err)
+	}
+
+	group := &models.SynonymGroup{Terms: []string{"Earphones", "earbuds"}}
+	if err := store.CreateSynonymGroup(ctx, group); err != nil || group.ID == 0 {
+		t.Fatalf("CreateSynonymGroup: %v, %+v", err, group)
+	}
+	group.Terms = []string{"earphones", "ear buds", "earbuds"}
+	if err := store.UpdateSynonymGroup(ctx, group); err != nil {
+		t.Errorf("UpdateSynonymGroup: %v", err)
+	}
+	groups, err := store.ListSynonymGroups(ctx)
+	if wantGroups := []models.SynonymGroup{{ID: group.ID, Terms: []string{"ear buds", "earbuds", "earphones"}}}; err != nil || !reflect.DeepEqual(groups, wantGroups) {
+		t.Errorf("ListSynonymGroups = %+v, %v, want %+v", groups, err, wantGroups)
+	}
+	if err := store.DeleteSynonymGroup(ctx, group.ID); err != nil {
+		t.Errorf("DeleteSynonymGroup: %v", err)
+	}
+	if err := store.UpdateSynonymGroup(ctx, group); !errors.Is(err, models.ErrSynonymGroupNotFound) {
+		t.Errorf("Expected ErrSynonymGroupNotFound, got %v", err)
+	}
+
+	saved := &models.SavedSearch{BuyerID: 7, ProductName: "drone", Location: "IND", MaxPrice: 150, DesiredQty: 2}
+	if err := store.CreateSavedSearch(ctx, saved); err != nil || saved.ID == 0 || saved.CreatedAt.IsZero() {
+		t.Fatalf("CreateSavedSearch: %v, %+v", err, saved)
+	}
+	if got, err := store.GetSavedSearchByID(ctx, saved.ID); err != nil || !reflect.DeepEqual(got, *saved) {
+		t.Errorf("GetSavedSearchByID = %+v, %v, want %+v", got, err, *saved)
+	}
+	drone := models.Product{ProductName: "Drone", Price: 120, Quantity: 3}
+	for _, tt := range []struct {
+		product models.Product
+		seller  models.Seller
+		want    int
+	}{
+		{drone, models.Seller{Location: "ind"}, 1},
+		{drone, models.Seller{Location: "US"}, 0},
+		{models.Product{Price: 200, Quantity: 3}, models.Seller{Location: "IND"}, 0},
+		{models.Product{Price: 120, Quantity: 1}, models.Seller{Location: "IND"}, 0},
+	} {
+		if candidates, err := store.CandidateSavedSearches(ctx, tt.product, tt.seller); err != nil || len(candidates) != tt.want {
+			t.Errorf("CandidateSavedSearches(%+v, %+v) = %+v, %v, want %d", tt.product, tt.seller, candidates, err, tt.want)
+		}
+	}
+	update := &models.SavedSearch{ID: saved.ID, ProductName: "camera"}
+	if err := store.UpdateSavedSearch(ctx, update); err != nil || update.BuyerID != 7 || !update.CreatedAt.Equal(saved.CreatedAt) {
+		t.Errorf("UpdateSavedSearch: %v, %+v", err, update)
+	}
+	if list, err := store.ListSavedSearches(ctx, models.SavedSearchFilter{BuyerID: 7, Page: 1, PerPage: 10}); err != nil || len(list) != 1 || list[0].ProductName != "camera" {
+		t.Errorf("ListSavedSearches = %+v, %v", list, err)
+	}
+	if err := store.DeleteSavedSearch(ctx, saved.ID); err != nil {
+		t.Errorf("DeleteSavedSearch: %v", err)
+	}
+	if _, err := store.GetSavedSearchByID(ctx, saved.ID); !errors.Is(err, models.ErrSavedSearchNotFound) {
+		t.Errorf("Expected ErrSavedSearchNotFound, got %v", err)
+	}
+
+	buyer := &models.Buyer{Name: "Jane", Email: "Jane@Example.com"}
+	if err := store.CreateBuyer(ctx, buyer); err != nil || buyer.ID == 0 || buyer.Email != "jane@example.com" {
+		t.Fatalf("CreateBuyer: %v, %+v", err, buyer)
+	}
+	if err := store.CreateBuyer(ctx, &models.Buyer{Name: "Other", Email: "JANE@example.com"}); !errors.Is(err, models.ErrBuyerEmailTaken) {
+		t.Errorf("Expected ErrBuyerEmailTaken, got %v", err)
+	}
+	phone := "+91 80 1234 5678"
+	if got, err := store.UpdateBuyer(ctx, buyer.ID, models.BuyerUpdate{Phone: &phone}); err != nil || got.Phone != phone || got.Name != "Jane" {
+		t.Errorf("UpdateBuyer = %+v, %v", got, err)
+	}
+	home := &models.BuyerAddress{BuyerID: buyer.ID, Recipient: "Jane", Line1: "1 Main St", City: "Pune", Country: "in"}
+	office := &models.BuyerAddress{BuyerID: buyer.ID, Recipient: "Jane", Line1: "2 Park St", City: "Pune", Country: "IN", Default: true}
+	for _, a := range []*models.BuyerAddress{home, office} {
+		if err := store.CreateBuyerAddress(ctx, a); err != nil || a.ID == 0 {
+			t.Fatalf("CreateBuyerAddress: %v, %+v", err, a)
+		}
+	}
+	if err := store.DeleteBuyerAddress(ctx, buyer.ID, office.ID); err != nil {
+		t.Errorf("DeleteBuyerAddress: %v", err)
+	}
+	if addresses, err := store.ListBuyerAddresses(ctx, buyer.ID); err != nil || len(addresses) != 1 || !addresses[0].Default || addresses[0].Country != "IN" {
+		t.Errorf("ListBuyerAddresses = %+v, %v", addresses, err)
+	}
+
+	kettle := models.Product{SellerID: seller.ID, ProductName: "Kettle", Price: 15, Quantity: 2}
+	if err := store.CreateProduct(ctx, &kettle); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	for i := 1; i <= 2; i++ {
+		item := &models.CartItem{BuyerID: buyer.ID, ProductID: kettle.ID, Quantity: 1}
+		if err := store.AddCartItem(ctx, item); err != nil || item.Quantity != i || item.AddedPrice != 15 || item.AddedStock != 2 {
+			t.Fatalf("AddCartItem: %v, %+v", err, item)
+		}
+	}
+	if err := store.AddCartItem(ctx, &models.CartItem{BuyerID: buyer.ID, ProductID: kettle.ID, Quantity: 1}); !errors.Is(err, models.ErrInsufficientStock) {
+		t.Errorf("Expected ErrInsufficientStock, got %v", err)
+	}
+	if err := store.AddCartItem(ctx, &models.CartItem{BuyerID: buyer.ID, ProductID: kettle.ID + 1000, Quantity: 1}); !errors.Is(err, models.ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound, got %v", err)
+	}
+	if err := store.UpdateCartItem(ctx, &models.CartItem{BuyerID: buyer.ID, ProductID: kettle.ID, Quantity: 1}); err != nil {
+		t.Errorf("UpdateCartItem: %v", err)
+	}
+	items, err := store.CartItems(ctx, buyer.ID)
+	if err != nil || len(items) != 1 || items[0].Quantity != 1 || items[0].Product.ProductName != "Kettle" || items[0].Product.Seller.Name != "Seller A" {
+		t.Errorf("CartItems = %+v, %v", items, err)
+	}
+	if err := store.DeleteCartItem(ctx, buyer.ID, kettle.ID); err != nil {
+		t.Errorf("DeleteCartItem: %v", err)
+	}
+	if err := store.UpdateCartItem(ctx, &models.CartItem{BuyerID: buyer.ID, ProductID: kettle.ID, Quantity: 1}); !errors.Is(err, models.ErrCartItemNotFound) {
+		t.Errorf("Expected ErrCartItemNotFound, got %v", err)
+	}
+	if err := store.AddCartItem(ctx, &models.CartItem{BuyerID: buyer.ID, ProductID: kettle.ID, Quantity: 1}); err != nil {
+		t.Errorf("AddCartItem: %v", err)
+	}
+
+	if err := store.DeleteBuyer(ctx, buyer.ID); err != nil {
+		t.Errorf("DeleteBuyer: %v", err)
+	}
+	if _, err := store.ListBuyerAddresses(ctx, buyer.ID); !errors.Is(err, models.ErrBuyerNotFound) {
+		t.Errorf("Expected ErrBuyerNotFound, got %v", err)
+	}
+	if _, err := store.CartItems(ctx, buyer.ID); !errors.Is(err, models.ErrBuyerNotFound) {
+		t.Errorf("Expected ErrBuyerNotFound, got %v", err)
+	}
+}
+
+func TestStore_PlaceOrder(t *testing.T) {
+	store := NewStore(openTestDatabase(t))
+	ctx := context.Background()
+
+	seller := models.Seller{Name: "Seller A", Location: "IND"}
+	if err := store.CreateSeller(ctx, &seller); err != nil {
+		t.Fatalf("CreateSeller: %v", err)
+	}
+	buyer := models.Buyer{Name: "Jane", Email: "jane@example.com"}
+	if err := store.CreateBuyer(ctx, &buyer); err != nil {
+		t.Fatalf("CreateBuyer: %v", err)
+	}
+	drone := models.Product{SellerID: seller.ID, ProductName: "Drone", Price: 19.99, Quantity: 3}
+	if err := store.CreateProduct(ctx, &drone); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	if err := store.AddCartItem(ctx, &models.CartItem{BuyerID: buyer.ID, ProductID: drone.ID, Quantity: 1}); err != nil {
+		t.Fatalf("AddCartItem: %v", err)
+	}
+
+	order := &models.Order{BuyerID: buyer.ID, Lines: []models.OrderLine{{ProductID: drone.ID, Quantity: 2}}}
+	if err := store.PlaceOrder(ctx, order); err != nil || order.ID == 0 || order.Total != 39.98 || order.Lines[0].UnitPrice != 19.99 {
+		t.Fatalf("PlaceOrder: %v, %+v", err, order)
+	}
+	if got, err := store.GetOrderByID(ctx, order.ID); err != nil || !reflect.DeepEqual(got, *order) {
+		t.Errorf("GetOrderByID = %+v, %v, want %+v", got, err, *order)
+	}
+	if p, err := store.GetProductByID(ctx, drone.ID); err != nil || p.Quantity != 1 {
+		t.Errorf("Expected 1 drone left, got %+v, %v", p, err)
+	}
+	if items, err := store.CartItems(ctx, buyer.ID); err != nil || len(items) != 0 {
+		t.Errorf("Expected the drone to leave the cart, got %+v, %v", items, err)
+	}
+	var lineErr *models.OrderLineError
+	err := store.PlaceOrder(ctx, &models.Order{BuyerID: buyer.ID, Lines: []models.OrderLine{{ProductID: drone.ID + 1000, Quantity: 1}}})
+	if !errors.As(err, &lineErr) || !errors.Is(err, models.ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound, got %v", err)
+	}
+	err = store.PlaceOrder(ctx, &models.Order{BuyerID: buyer.ID + 1000, Lines: []models.OrderLine{{ProductID: drone.ID, Quantity: 1}}})
+	if !errors.Is(err, models.ErrBuyerNotFound) {
+		t.Errorf("Expected ErrBuyerNotFound, got %v", err)
+	}
+
+	// the last drone is ordered concurrently, exactly one order gets it
+	const orders = 10
+	errs := make(chan error, orders)
+	var wg sync.WaitGroup
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- store.PlaceOrder(ctx, &models.Order{BuyerID: buyer.ID, Lines: []models.OrderLine{{ProductID: drone.ID, Quantity: 1}}})
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	placed := 0
+	for err := range errs {
+		switch {
+		case err == nil:
+			placed++
+		case !errors.Is(err, models.ErrInsufficientStock):
+			t.Errorf("PlaceOrder: %v", err)
+		}
+	}
+	if placed != 1 {
+		t.Errorf("Expected exactly one order to get the last drone, %d did", placed)
+	}
+	if p, err := store.GetProductByID(ctx, drone.ID); err != nil || p.Quantity != 0 {
+		t.Errorf("Expected the drones to be sold out, got %+v, %v", p, err)
+	}
+}
+
+// TestStore_ProductNameParity runs the same name searches on the mysql store and the memstore,
+// wildcards of LIKE in the name are matched literally by both
+func TestStore_ProductNameParity(t *testing.T) {
+	stores := map[string]interface {
+		models.ProductStore
+		models.SellerStore
+	}{"mysql": NewStore(openTestDatabase(t)), "memstore": memstore.New()}
+	ctx := context.Background()
+
+	found := make(map[string]map[string][]string)
+	for name, store := range stores {
+		seller := models.Seller{Name: "Seller A", Location: "IND"}
+		if err := store.CreateSeller(ctx, &seller); err != nil {
+			t.Fatalf("%s CreateSeller: %v", name, err)
+		}
+		for _, productName := range []string{"100% Cotton Shirt", "1000 Piece Puzzle", "a_b Cable", "aXb Cable", `Back\slash`} {
+			if err := store.CreateProduct(ctx, &models.Product{SellerID: seller.ID, ProductName: productName, Price: 10, Quantity: 1}); err != nil {
+				t.Fatalf("%s CreateProduct: %v", name, err)
+			}
+		}
+		found[name] = make(map[string][]string)
+		for _, query := range []string{"100%", "a_b", `k\s`, "%"} {
+			page, err := store.SearchProducts(ctx, &models.ProductRequest{ProductName: query, SortBy: "productName", Page: 1, PerPage: 10})
+			if err != nil {
+				t.Fatalf("%s SearchProducts(%q): %v", name, query, err)
+			}
+			names := []string{}
+			for _, p := range page.Products {
+				names = append(names, p.ProductName)
+			}
+			found[name][query] = names
+		}
+	}
+	if !reflect.DeepEqual(found["mysql"], found["memstore"]) {
+		t.Errorf("Expected the stores to find the same products, mysql found %v and memstore %v", found["mysql"], found["memstore"])
+	}
+	if want := []string{"100% Cotton Shirt"}; !reflect.DeepEqual(found["memstore"]["100%"], want) {
+		t.Errorf("Expected %v for 100%%, got %v", want, found["memstore"]["100%"])
+	}
+}
+
+func TestStore_CreateProductSearchFields(t *testing.T) {
+	store := NewStore(openTestDatabase(t))
+	ctx := context.Background()
+	seller := models.Seller{Name: "Seller A", Location: "IND"}
+	if err := store.CreateSeller(ctx, &seller); err != nil {
+		t.Fatalf("CreateSeller: %v", err)
+	}
+	distance := 3.0
+	product := models.Product{SellerID: seller.ID, ProductName: "Drone", Price: 10, Quantity: 1,
+		Score: 9, Distance: &distance, Seller: &models.Seller{Name: "FAKE"}}
+	if err := store.CreateProduct(ctx, &product); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	if product.Score != 0 || product.Distance != nil || product.Seller != nil {
+		t.Errorf("Expected the fields of the searches to be cleared, got %+v", product)
+	}
+}
@@ -0,0 +1,113 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// ListSynonymGroups loads every synonym group with its terms in one query
+func (s *Store) ListSynonymGroups(ctx context.Context) ([]models.SynonymGroup, error) {
+	rows, err := s.conn.QueryContext(ctx, `SELECT g.id, t.term FROM synonym_groups AS g
+		INNER JOIN synonym_terms AS t ON t.group_id = g.id ORDER BY g.id, t.term`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []models.SynonymGroup{}
+	for rows.Next() {
+		var id int
+		var term string
+		if err := rows.Scan(&id, &term); err != nil {
+			return nil, err
+		}
+		if len(groups) == 0 || groups[len(groups)-1].ID != id {
+			groups = append(groups, models.SynonymGroup{ID: id})
+		}
+		last := &groups[len(groups)-1]
+		last.Terms = append(last.Terms, term)
+	}
+	return groups, rows.Err()
+}
+
+// CreateSynonymGroup saves the group and its terms in a transaction and sets its ID
+func (s *Store) CreateSynonymGroup(ctx context.Context, g *models.SynonymGroup) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `INSERT INTO synonym_groups () VALUES ()`)
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	g.Normalize()
+	if err := saveSynonymTerms(ctx, tx, int(id), g.Terms); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	g.ID = int(id)
+	return nil
+}
+
+// UpdateSynonymGroup replaces the terms of the group in a transaction
+func (s *Store) UpdateSynonymGroup(ctx context.Context, g *models.SynonymGroup) error {
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var id int
+	err = tx.QueryRowContext(ctx, `SELECT id FROM synonym_groups WHERE id = ? FOR UPDATE`, g.ID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ErrSynonymGroupNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM synonym_terms WHERE group_id = ?`, g.ID); err != nil {
+		return err
+	}
+	g.Normalize()
+	if err := saveSynonymTerms(ctx, tx, g.ID, g.Terms); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteSynonymGroup deletes the group, its terms are deleted by the foreign key
+func (s *Store) DeleteSynonymGroup(ctx context.Context, id int) error {
+	result, err := s.conn.ExecContext(ctx, `DELETE FROM synonym_groups WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return models.ErrSynonymGroupNotFound
+	}
+	return nil
+}
+
+// saveSynonymTerms inserts the terms of the group
+func saveSynonymTerms(ctx context.Context, q queryer, groupID int, terms []string) error {
+	args := make([]interface{}, 0, 2*len(terms))
+	for _, term := range terms {
+		args = append(args, groupID, term)
+	}
+	_, err := q.ExecContext(ctx, `INSERT INTO synonym_terms (group_id, term) VALUES `+
+		strings.TrimSuffix(strings.Repeat("(?, ?), ", len(terms)), ", "), args...)
+	return err
+}
@@ -2,4 +2,7 @@ module github.com/ganesh-sai/buyer-seller-app/seller-service
 
 go 1.20
 
-require github.com/go-sql-driver/mysql v1.7.1
+require (
+	github.com/go-sql-driver/mysql v1.7.1
+	gopkg.in/yaml.v3 v3.0.1
+)
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// defaultSearchReportWindow is the window of a report without from, ending at to
+const defaultSearchReportWindow = 24 * time.Hour
+
+// searchReportMeta is the meta of a report, the window it was computed over
+type searchReportMeta struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// searchReportRequest reads the window and the limit of a report from the query parameters
+func searchReportRequest(r *http.Request) (*models.SearchReportRequest, error) {
+	query := newQueryParams(r)
+	to := query.Time("to", time.Now().UTC())
+	req := &models.SearchReportRequest{
+		From:  query.Time("from", to.Add(-defaultSearchReportWindow)),
+		To:    to,
+		Limit: query.Int("limit", 20),
+	}
+	return req, query.Validate(req.Validate)
+}
+
+// TopSearchQueries responds with the queries searched most over a time window
+// It is mounted on GET /api/v1/admin/search/queries, and with zeroResults on
+// GET /api/v1/admin/search/zero-results to report the queries searched most without results.
+// The query of a search is its `q`, or else its `productName`, in lowercase
+//
+// Query Parameters:
+//
+//	`from` (optional): Start of the window in RFC 3339 format, 24 hours before `to` by default, at most 90 days before it
+//	`to` (optional): End of the window in RFC 3339 format, now by default
+//	`limit` (optional): Number of queries to return, 20 by default and at most 100
+//
+//	{"data": [
+//	  {"query": "smartphone", "searches": 42, "zeroResults": 0, "avgLatencyMs": 3.12, "lastSearchedAt": "2024-01-31T17:58:02.113Z"},
+//	  {"query": "drone", "searches": 17, "zeroResults": 2, "avgLatencyMs": 2.48, "lastSearchedAt": "2024-01-31T16:21:40.5Z"}
+//	 ],
+//	 "meta": {"from": "2024-01-30T18:00:00Z", "to": "2024-01-31T18:00:00Z"}}
+func TopSearchQueries(searches models.SearchLogStore, zeroResults bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := searchReportRequest(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		req.ZeroResults = zeroResults
+		stats, err := searches.TopSearchQueries(r.Context(), req)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSONWithMeta(w, http.StatusOK, stats, searchReportMeta{From: req.From, To: req.To})
+	}
+}
+
+// TopSearchFilters responds with the combinations of filters used most over a time window
+// It is mounted on GET /api/v1/admin/search/filters and takes the parameters of
+// TopSearchQueries. A combination lists the names of the parameters narrowing the searches,
+// attribute filters being named attr.<name>
+//
+//	{"data": [
+//	  {"filters": ["category", "maxPrice"], "searches": 120, "zeroResults": 4},
+//	  {"filters": ["attr.color", "q"], "searches": 35, "zeroResults": 0}
+//	 ],
+//	 "meta": {"from": "2024-01-30T18:00:00Z", "to": "2024-01-31T18:00:00Z"}}
+func TopSearchFilters(searches models.SearchLogStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := searchReportRequest(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		stats, err := searches.TopSearchFilters(r.Context(), req)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSONWithMeta(w, http.StatusOK, stats, searchReportMeta{From: req.From, To: req.To})
+	}
+}
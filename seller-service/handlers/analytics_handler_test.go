@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// serveAdmin serves a request to the admin endpoints with the bearer token
+func serveAdmin(method, target string, body []byte) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	recorder := httptest.NewRecorder()
+	routes.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestSearchReports(t *testing.T) {
+	for i := 0; i < 3; i++ {
+		serve(http.MethodGet, "/api/v1/product/search?productName=Flux%20Capacitor&maxPrice=200", nil)
+	}
+	serve(http.MethodGet, "/api/v1/product/search?productName=flux%20capacitor&maxPrice=200&page=2", nil)
+	serve(http.MethodGet, "/api/v1/product/search?q=drone&maxPrice=200", nil)
+	serve(http.MethodPost, "/api/v1/product/search", []byte(`{"productName": "Drone", "maxPrice": 200}`))
+	// a search answered with an error is not recorded
+	serve(http.MethodGet, "/api/v1/product/search?productName=drone&maxPrice=-1", nil)
+	if err := searches.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	recorder := serveAdmin(http.MethodGet, "/api/v1/admin/search/queries?limit=100", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var queries []models.SearchQueryStat
+	decodeData(t, recorder, &queries)
+	counts := make(map[string][2]int)
+	for _, q := range queries {
+		counts[q.Query] = [2]int{q.Searches, q.ZeroResults}
+	}
+	// the page after the first one counts as a search but not as one without results
+	if got := counts["flux capacitor"]; got != [2]int{4, 3} {
+		t.Errorf("Expected 4 searches of flux capacitor, 3 without results, got %v", got)
+	}
+	if got := counts["drone"]; got != [2]int{2, 0} {
+		t.Errorf("Expected 2 searches of drone with results, got %v", got)
+	}
+
+	recorder = serveAdmin(http.MethodGet, "/api/v1/admin/search/zero-results?limit=100", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	decodeData(t, recorder, &queries)
+	for _, q := range queries {
+		if q.ZeroResults == 0 || q.Query == "drone" {
+			t.Errorf("Expected only queries searched without results, got %+v", q)
+		}
+	}
+
+	recorder = serveAdmin(http.MethodGet, "/api/v1/admin/search/filters?limit=100", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var filters []models.SearchFilterStat
+	decodeData(t, recorder, &filters)
+	found := false
+	for _, f := range filters {
+		if reflect.DeepEqual(f.Filters, []string{"maxPrice", "productName"}) {
+			found = f.Searches >= 5
+		}
+	}
+	if !found {
+		t.Errorf("Expected at least 5 searches filtered by maxPrice and productName, got %+v", filters)
+	}
+}
+
+func TestSearchReports_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		token  string
+		status int
+	}{
+		{"missing token", "/api/v1/admin/search/queries", "", http.StatusUnauthorized},
+		{"wrong token", "/api/v1/admin/search/queries", "Bearer nope", http.StatusUnauthorized},
+		{"invalid from", "/api/v1/admin/search/queries?from=yesterday", "Bearer " + adminToken, http.StatusBadRequest},
+		{"to before from", "/api/v1/admin/search/filters?from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z", "Bearer " + adminToken, http.StatusBadRequest},
+		{"window too long", "/api/v1/admin/search/zero-results?from=2023-01-01T00:00:00Z&to=2024-01-01T00:00:00Z", "Bearer " + adminToken, http.StatusBadRequest},
+		{"limit too large", "/api/v1/admin/search/queries?limit=101", "Bearer " + adminToken, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			routes.ServeHTTP(recorder, req)
+			if recorder.Code != tt.status {
+				t.Errorf("Expected status %d, got %d %s", tt.status, recorder.Code, recorder.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// ListAttributes responds with the attributes of the products, to discover the `attr.<name>`
+// filters of the search
+//
+// It is mounted on GET /api/v1/attributes. Attributes are sorted by name, each with the number
+// of products having it and its most common values
+//
+//	{"data": [
+//	  {"name": "color", "productCount": 12, "values": [{"value": "red", "count": 7}, {"value": "blue", "count": 5}]},
+//	  {"name": "ram", "productCount": 4, "values": [{"value": "16", "count": 3}, {"value": "32", "count": 1}]}
+//	]}
+func ListAttributes(products models.ProductStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		attributes, err := products.ListAttributes(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, attributes)
+	}
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+func TestProductAttributes(t *testing.T) {
+	var created []models.Product
+	for _, body := range []string{
+		`{"sellerId": 1, "productName": "Laptop 16", "price": 900, "quantity": 1, "attributes": {"Color": "Red", "ram": "16"}, "tags": ["Portable", "gaming"]}`,
+		`{"sellerId": 1, "productName": "Laptop 32", "price": 1500, "quantity": 1, "attributes": {"color": "black", "ram": "32"}, "tags": ["gaming"]}`,
+		`{"sellerId": 1, "productName": "Laptop 8", "price": 400, "quantity": 1, "attributes": {"color": "red", "ram": "8"}}`,
+	} {
+		recorder := serve(http.MethodPost, "/api/v1/product", []byte(body))
+		if recorder.Code != http.StatusCreated {
+			t.Fatalf("Expected 201, got %d: %s", recorder.Code, recorder.Body)
+		}
+		var product models.Product
+		decodeData(t, recorder, &product)
+		created = append(created, product)
+	}
+	if want := map[string]string{"color": "Red", "ram": "16"}; !reflect.DeepEqual(created[0].Attributes, want) || !reflect.DeepEqual(created[0].Tags, []string{"gaming", "portable"}) {
+		t.Errorf("Expected names and tags in lowercase, got %+v %v", created[0].Attributes, created[0].Tags)
+	}
+
+	tests := []struct {
+		query string
+		want  []int
+	}{
+		{"attr.color=red", []int{created[0].ID, created[2].ID}},
+		{"attr.ram>=16", []int{created[0].ID, created[1].ID}},
+		{"attr.ram>16", []int{created[1].ID}},
+		{"attr.color=RED&attr.ram<=8", []int{created[2].ID}},
+		{"attr.color!=red&minPrice=1000", []int{created[1].ID}},
+		{"tags=gaming,portable", []int{created[0].ID}},
+		{"attr.ram<32&tags=gaming&maxPrice=1000", []int{created[0].ID}},
+	}
+	for _, tt := range tests {
+		var products []models.Product
+		decodeData(t, serve(http.MethodGet, "/api/v1/product/search?sortBy=productId&"+tt.query, nil), &products)
+		var ids []int
+		for _, p := range products {
+			ids = append(ids, p.ID)
+		}
+		if !reflect.DeepEqual(ids, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.query, ids, tt.want)
+		}
+	}
+
+	recorder := serve(http.MethodPatch, fmt.Sprintf("/api/v1/product/%d", created[2].ID), []byte(`{"attributes": {}, "tags": ["budget"]}`))
+	var updated models.Product
+	decodeData(t, recorder, &updated)
+	if recorder.Code != http.StatusOK || updated.Attributes != nil || !reflect.DeepEqual(updated.Tags, []string{"budget"}) {
+		t.Errorf("Expected the attributes to be removed and the tags replaced, got %d %+v", recorder.Code, updated)
+	}
+
+	var attributes []models.AttributeSummary
+	decodeData(t, serve(http.MethodGet, "/api/v1/attributes", nil), &attributes)
+	want := []models.AttributeSummary{
+		{Name: "color", ProductCount: 2, Values: []models.FacetBucket{{Value: "Red", Count: 1}, {Value: "black", Count: 1}}},
+		{Name: "ram", ProductCount: 2, Values: []models.FacetBucket{{Value: "16", Count: 1}, {Value: "32", Count: 1}}},
+	}
+	if !reflect.DeepEqual(attributes, want) {
+		t.Errorf("Unexpected attributes %+v", attributes)
+	}
+}
+
+func TestProductAttributes_Invalid(t *testing.T) {
+	tests := []struct {
+		method string
+		target string
+		body   string
+		fields []string
+	}{
+		{http.MethodPost, "/api/v1/product", `{"sellerId": 1, "productName": "X", "price": 1, "quantity": 1, "attributes": {"bad name": "x", "ok": "` + strings.Repeat("v", 256) + `"}}`, []string{"attributes.bad name", "attributes.ok"}},
+		{http.MethodPost, "/api/v1/product", `{"sellerId": 1, "productName": "X", "price": 1, "quantity": 1, "tags": ["a", "A"]}`, []string{"tags"}},
+		{http.MethodGet, "/api/v1/product/search?attr.ram>=lots", "", []string{"attr.ram"}},
+		{http.MethodGet, "/api/v1/product/search?attr.ram>=nan", "", []string{"attr.ram"}},
+		{http.MethodGet, "/api/v1/product/search?attr.ram<Infinity", "", []string{"attr.ram"}},
+		{http.MethodGet, "/api/v1/product/search?attr.=red", "", []string{"attr."}},
+	}
+	for _, tt := range tests {
+		var body []byte
+		if tt.body != "" {
+			body = []byte(tt.body)
+		}
+		recorder := serve(tt.method, tt.target, body)
+		if fields := errorFields(t, recorder); recorder.Code != http.StatusBadRequest || !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%s %s: expected 400 on %v, got %d %v", tt.method, tt.target, tt.fields, recorder.Code, fields)
+		}
+	}
+}
+
+func TestProductAttributes_NonFiniteValue(t *testing.T) {
+	recorder := serve(http.MethodPost, "/api/v1/product", []byte(`{"sellerId": 1, "productName": "Infinity Phone", "price": 1, "quantity": 1, "attributes": {"model": "Infinity"}}`))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected 201, got %d: %s", recorder.Code, recorder.Body)
+	}
+	var product models.Product
+	decodeData(t, recorder, &product)
+	t.Cleanup(func() { serve(http.MethodDelete, fmt.Sprintf("/api/v1/product/%d", product.ID), nil) })
+
+	for query, found := range map[string]bool{"attr.model=infinity": true, "attr.model>=0": false, "attr.model<0": false} {
+		var products []models.Product
+		decodeData(t, serve(http.MethodGet, "/api/v1/product/search?"+query, nil), &products)
+		got := false
+		for _, p := range products {
+			got = got || p.ID == product.ID
+		}
+		if got != found {
+			t.Errorf("%s: expected the product to be found %v, got %v", query, found, got)
+		}
+	}
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// CreateBuyer handles the registration of a buyer
+//
+// It is mounted on POST /api/v1/buyer. The email is saved in lowercase and no two buyers
+// share one, a taken email gets a 409. The below is json input
+//
+// Input:
+//
+//	{
+//	  "name": "Jane Doe",
+//	  "email": "jane@example.com",
+//	  "phone": "+91 80 1234 5678"
+//	}
+//
+// Output:
+//
+//	{
+//	  "data": {
+//	    "id": 1,
+//	    "name": "Jane Doe",
+//	    "email": "jane@example.com",
+//	    "phone": "+91 80 1234 5678",
+//	    "createdAt": "2024-01-31T17:58:02.113Z"
+//	  }
+//	}
+//
+// Returns the saved buyer back as JSON response or error if any
+func CreateBuyer(buyers models.BuyerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var buyer models.Buyer
+		err := decodeJSON(r, &buyer)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = buyer.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = buyers.CreateBuyer(r.Context(), &buyer)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, buyer)
+	}
+}
+
+// GetBuyer responds with the profile of the buyer matching the id of the path
+//
+// It is mounted on GET /api/v1/buyer/{id}
+func GetBuyer(buyers models.BuyerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		buyer, err := buyers.GetBuyerByID(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, buyer)
+	}
+}
+
+// UpdateBuyer changes the name, email or phone of the buyer matching the id of the path
+//
+// It is mounted on PUT /api/v1/buyer/{id}, where the name and email are required, and on
+// PATCH /api/v1/buyer/{id}, where only the fields given are changed. The below is json input
+//
+// Input:
+//
+//	{
+//	  "name": "Jane Doe",
+//	  "email": "jane.doe@example.com"
+//	}
+//
+// Returns the updated buyer
+func UpdateBuyer(buyers models.BuyerStore, partial bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var update models.BuyerUpdate
+		err = decodeJSON(r, &update)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		err = update.Validate(!partial)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		buyer, err := buyers.UpdateBuyer(r.Context(), id, update)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, buyer)
+	}
+}
+
+// DeleteBuyer deletes the buyer matching the id of the path along with its address book, saved
+// searches and cart
+//
+// It is mounted on DELETE /api/v1/buyer/{id} and responds with 204 No Content
+func DeleteBuyer(buyers models.BuyerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = buyers.DeleteBuyer(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// ListBuyerAddresses responds with the address book of the buyer matching the id of the path,
+// ordered by id
+//
+// It is mounted on GET /api/v1/buyer/{id}/addresses
+func ListBuyerAddresses(buyers models.BuyerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		addresses, err := buyers.ListBuyerAddresses(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, addresses)
+	}
+}
+
+// CreateBuyerAddress adds an address to the address book of the buyer matching the id of the path
+//
+// It is mounted on POST /api/v1/buyer/{id}/addresses. The first address of a buyer becomes
+// its default address, as does an address marked default. A book holds at most 10 addresses,
+// a full one gets a 409. The below is json input
+//
+// Input:
+//
+//	{
+//	  "label": "Home",
+//	  "recipient": "Jane Doe",
+//	  "line1": "12 MG Road",
+//	  "city": "Bengaluru",
+//	  "postalCode": "560001",
+//	  "country": "IN",
+//	  "default": true
+//	}
+//
+// Returns the saved address with a 201
+func CreateBuyerAddress(buyers models.BuyerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var address models.BuyerAddress
+		err = decodeJSON(r, &address)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		address.BuyerID = id
+		err = address.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = buyers.CreateBuyerAddress(r.Context(), &address)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, address)
+	}
+}
+
+// UpdateBuyerAddress replaces the address matching the addressId of the path in the address
+// book of the buyer matching its id
+//
+// It is mounted on PUT /api/v1/buyer/{id}/addresses/{addressId} and takes the body of
+// CreateBuyerAddress. The default address stays so until another one is marked default
+func UpdateBuyerAddress(buyers models.BuyerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		addressID, err := pathParamID(r, "addressId")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var address models.BuyerAddress
+		err = decodeJSON(r, &address)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		address.ID, address.BuyerID = addressID, id
+		err = address.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = buyers.UpdateBuyerAddress(r.Context(), &address)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, address)
+	}
+}
+
+// DeleteBuyerAddress deletes the address matching the addressId of the path from the address
+// book of the buyer matching its id, the oldest remaining address becomes the default one when
+// it was
+//
+// It is mounted on DELETE /api/v1/buyer/{id}/addresses/{addressId} and responds with 204 No Content
+func DeleteBuyerAddress(buyers models.BuyerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		addressID, err := pathParamID(r, "addressId")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = buyers.DeleteBuyerAddress(r.Context(), id, addressID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// invalidBuyer is the error of a request referencing a buyer that does not exist
+func invalidBuyer() error {
+	return validation.Errors{{Field: "buyerId", Message: "buyer does not exist"}}
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// createTestBuyer adds a buyer with the email to the shared store
+func createTestBuyer(t *testing.T, email string) models.Buyer {
+	t.Helper()
+	buyer := models.Buyer{Name: "Temp Buyer", Email: email}
+	if err := store.CreateBuyer(context.Background(), &buyer); err != nil {
+		t.Fatalf("Failed to create buyer: %v", err)
+	}
+	return buyer
+}
+
+func TestBuyers(t *testing.T) {
+	recorder := serve(http.MethodPost, "/api/v1/buyer", []byte(`{"name": "Jane Doe", "email": " Jane@Example.com ", "phone": "+91 80 1234 5678"}`))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var buyer models.Buyer
+	decodeData(t, recorder, &buyer)
+	if buyer.ID == 0 || buyer.Email != "jane@example.com" || buyer.CreatedAt.IsZero() {
+		t.Errorf("Expected the buyer back with its email in lowercase, got %+v", buyer)
+	}
+	target := fmt.Sprintf("/api/v1/buyer/%d", buyer.ID)
+
+	recorder = serve(http.MethodPost, "/api/v1/buyer", []byte(`{"name": "Jane Again", "email": "JANE@example.com"}`))
+	if recorder.Code != http.StatusConflict || decodeError(t, recorder).Code != "buyer_email_taken" {
+		t.Errorf("Expected a taken email to be refused, got %d %s", recorder.Code, recorder.Body)
+	}
+
+	recorder = serve(http.MethodPatch, target, []byte(`{"phone": ""}`))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	buyer = models.Buyer{}
+	decodeData(t, recorder, &buyer)
+	if buyer.Name != "Jane Doe" || buyer.Phone != "" {
+		t.Errorf("Expected only the phone to be removed, got %+v", buyer)
+	}
+	other := createTestBuyer(t, "someone.else@example.com")
+	recorder = serve(http.MethodPut, fmt.Sprintf("/api/v1/buyer/%d", other.ID), []byte(`{"name": "Someone", "email": "jane@example.com"}`))
+	if recorder.Code != http.StatusConflict {
+		t.Errorf("Expected taking the email of another buyer to be refused, got %d", recorder.Code)
+	}
+
+	recorder = serve(http.MethodGet, target, nil)
+	var got models.Buyer
+	decodeData(t, recorder, &got)
+	if got != buyer {
+		t.Errorf("Expected %+v, got %+v", buyer, got)
+	}
+
+	if code := serve(http.MethodDelete, target, nil).Code; code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+	recorder = serve(http.MethodGet, target, nil)
+	if recorder.Code != http.StatusNotFound || decodeError(t, recorder).Code != "buyer_not_found" {
+		t.Errorf("Expected buyer_not_found, got %d %s", recorder.Code, recorder.Body)
+	}
+}
+
+func TestCreateBuyer_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+	}{
+		{"missing fields", `{}`, []string{"name", "email"}},
+		{"bad email", `{"name": "Jane", "email": "Jane <jane@example.com>"}`, []string{"email"}},
+		{"bad phone", `{"name": "Jane", "email": "jane@example.com", "phone": "call me"}`, []string{"phone"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(http.MethodPost, "/api/v1/buyer", []byte(tt.body))
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("Expected 400, got %d", recorder.Code)
+			}
+			if fields := errorFields(t, recorder); fmt.Sprint(fields) != fmt.Sprint(tt.fields) {
+				t.Errorf("Expected invalid fields %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+}
+
+func TestBuyerAddresses(t *testing.T) {
+	buyer := createTestBuyer(t, "addresses@example.com")
+	target := fmt.Sprintf("/api/v1/buyer/%d/addresses", buyer.ID)
+	add := func(body string) models.BuyerAddress {
+		t.Helper()
+		recorder := serve(http.MethodPost, target, []byte(body))
+		if recorder.Code != http.StatusCreated {
+			t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+		}
+		var address models.BuyerAddress
+		decodeData(t, recorder, &address)
+		return address
+	}
+	defaults := func() []int {
+		t.Helper()
+		var addresses []models.BuyerAddress
+		decodeData(t, serve(http.MethodGet, target, nil), &addresses)
+		var ids []int
+		for _, a := range addresses {
+			if a.Default {
+				ids = append(ids, a.ID)
+			}
+		}
+		return ids
+	}
+
+	home := add(`{"label": "Home", "recipient": "Jane Doe", "line1": "12 MG Road", "city": "Bengaluru", "postalCode": "560001", "country": "in"}`)
+	if !home.Default || home.Country != "IN" || home.BuyerID != buyer.ID {
+		t.Errorf("Expected the first address to be the default one, got %+v", home)
+	}
+	office := add(`{"label": "Office", "recipient": "Jane Doe", "line1": "1 Residency Road", "city": "Bengaluru", "country": "IN"}`)
+	if office.Default {
+		t.Errorf("Expected the second address not to be the default one, got %+v", office)
+	}
+
+	recorder := serve(http.MethodPut, fmt.Sprintf("%s/%d", target, office.ID), []byte(`{"label": "Office", "recipient": "Jane Doe", "line1": "2 Residency Road", "city": "Bengaluru", "country": "IN", "default": true}`))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	if ids := defaults(); len(ids) != 1 || ids[0] != office.ID {
+		t.Errorf("Expected the office to be the only default address, got %v", ids)
+	}
+
+	if code := serve(http.MethodDelete, fmt.Sprintf("%s/%d", target, office.ID), nil).Code; code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+	if ids := defaults(); len(ids) != 1 || ids[0] != home.ID {
+		t.Errorf("Expected home to become the default address, got %v", ids)
+	}
+
+	other := createTestBuyer(t, "other.addresses@example.com")
+	recorder = serve(http.MethodDelete, fmt.Sprintf("/api/v1/buyer/%d/addresses/%d", other.ID, home.ID), nil)
+	if recorder.Code != http.StatusNotFound || decodeError(t, recorder).Code != "buyer_address_not_found" {
+		t.Errorf("Expected the address of another buyer not to be found, got %d %s", recorder.Code, recorder.Body)
+	}
+	recorder = serve(http.MethodPost, target, []byte(`{"recipient": "Jane Doe", "line1": "12 MG Road", "city": "Bengaluru", "country": "XX"}`))
+	if fields := errorFields(t, recorder); recorder.Code != http.StatusBadRequest || fmt.Sprint(fields) != "[country]" {
+		t.Errorf("Expected an unknown country to be refused, got %d %v", recorder.Code, fields)
+	}
+
+	for i := 1; i < models.MaxBuyerAddresses; i++ {
+		add(`{"recipient": "Jane Doe", "line1": "12 MG Road", "city": "Bengaluru", "country": "IN"}`)
+	}
+	recorder = serve(http.MethodPost, target, []byte(`{"recipient": "Jane Doe", "line1": "12 MG Road", "city": "Bengaluru", "country": "IN"}`))
+	if recorder.Code != http.StatusConflict || decodeError(t, recorder).Code != "too_many_buyer_addresses" {
+		t.Errorf("Expected a full address book to be refused, got %d %s", recorder.Code, recorder.Body)
+	}
+	if code := serve(http.MethodGet, "/api/v1/buyer/9999/addresses", nil).Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 for the addresses of an unknown buyer, got %d", code)
+	}
+}
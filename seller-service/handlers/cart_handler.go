@@ -0,0 +1,198 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// GetCart responds with the cart of the buyer matching the id of the path, read against the
+// live price and stock of its products
+//
+// It is mounted on GET /api/v1/buyer/{id}/cart. The lines are grouped by seller, each line is
+// flagged when the price or the stock of its product changed since it was added, or when the
+// stock is below its quantity
+//
+// Output:
+//
+//	{
+//	  "data": {
+//	    "buyerId": 7,
+//	    "sellers": [
+//	      {"sellerId": 1, "sellerName": "Seller Name", "lines": [
+//	        {"productId": 42, "productName": "Mini Drone", "quantity": 2, "price": 120, "stock": 5, "subtotal": 240,
+//	         "addedPrice": 110, "addedStock": 5, "addedAt": "2024-01-31T17:58:02.113Z",
+//	         "priceChanged": true, "stockChanged": false, "insufficientStock": false}
+//	       ], "quantity": 2, "subtotal": 240}
+//	    ],
+//	    "items": 1,
+//	    "quantity": 2,
+//	    "total": 240,
+//	    "changed": true
+//	  }
+//	}
+func GetCart(carts models.CartStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeCart(w, r, carts, id, http.StatusOK)
+	}
+}
+
+// AddCartItem puts a quantity of a product in the cart of the buyer matching the id of the path
+//
+// It is mounted on POST /api/v1/buyer/{id}/cart/items. A product already in the cart gets the
+// quantity on top of the one it has, the cart cannot hold more than the stock of the product
+// nor more than 100 products, both get a 409. The below is json input
+//
+// Input:
+//
+//	{
+//	  "productId": 42,
+//	  "quantity": 2
+//	}
+//
+// Returns the cart, see GetCart, with a 201
+func AddCartItem(carts models.CartStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var item models.CartItem
+		err = decodeJSON(r, &item)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		item.BuyerID = id
+		err = item.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = carts.AddCartItem(r.Context(), &item)
+		if errors.Is(err, models.ErrProductNotFound) {
+			err = validation.Errors{{Field: "productId", Message: "product does not exist"}}
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeCart(w, r, carts, id, http.StatusCreated)
+	}
+}
+
+// UpdateCartItem sets the quantity of the product matching the productId of the path in the
+// cart of the buyer matching its id
+//
+// It is mounted on PUT /api/v1/buyer/{id}/cart/items/{productId}. The price and stock of the
+// product are taken as seen by the buyer again, clearing the flags of the line. The below is
+// json input
+//
+// Input:
+//
+//	{
+//	  "quantity": 3
+//	}
+//
+// Returns the cart, see GetCart
+func UpdateCartItem(carts models.CartStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		productID, err := pathParamID(r, "productId")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var item models.CartItem
+		err = decodeJSON(r, &item)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		item.BuyerID, item.ProductID = id, productID
+		err = item.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = carts.UpdateCartItem(r.Context(), &item)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		writeCart(w, r, carts, id, http.StatusOK)
+	}
+}
+
+// DeleteCartItem takes the product matching the productId of the path out of the cart of the
+// buyer matching its id
+//
+// It is mounted on DELETE /api/v1/buyer/{id}/cart/items/{productId} and responds with 204 No Content
+func DeleteCartItem(carts models.CartStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		productID, err := pathParamID(r, "productId")
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = carts.DeleteCartItem(r.Context(), id, productID)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// ClearCart empties the cart of the buyer matching the id of the path
+//
+// It is mounted on DELETE /api/v1/buyer/{id}/cart and responds with 204 No Content
+func ClearCart(carts models.CartStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = carts.ClearCart(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// writeCart responds with the cart of the buyer built from its items, see models.NewCart
+func writeCart(w http.ResponseWriter, r *http.Request, carts models.CartStore, buyerID, status int) {
+	items, err := carts.CartItems(r.Context(), buyerID)
+	if err != nil {
+		writeError(w, r, err)
+		return
+	}
+	response.JSON(w, status, models.NewCart(buyerID, items))
+}
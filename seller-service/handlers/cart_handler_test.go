@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// cartOf reads the cart of the buyer through the API
+func cartOf(t *testing.T, buyerID int) models.Cart {
+	t.Helper()
+	recorder := serve(http.MethodGet, fmt.Sprintf("/api/v1/buyer/%d/cart", buyerID), nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var cart models.Cart
+	decodeData(t, recorder, &cart)
+	return cart
+}
+
+func TestCart(t *testing.T) {
+	ctx := context.Background()
+	buyer := createTestBuyer(t, "cart@example.com")
+	target := fmt.Sprintf("/api/v1/buyer/%d/cart", buyer.ID)
+	lamp := createTestProductNamed(t, "Cart Lamp", 15)
+	seller := createTestSeller(t, 0)
+	mug := models.Product{SellerID: seller.ID, ProductName: "Cart Mug", Price: 9.99, Quantity: 5}
+	if err := store.CreateProduct(ctx, &mug); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+
+	if cart := cartOf(t, buyer.ID); cart.Items != 0 || len(cart.Sellers) != 0 {
+		t.Errorf("Expected an empty cart, got %+v", cart)
+	}
+	for i := 0; i < 2; i++ {
+		recorder := serve(http.MethodPost, target+"/items", []byte(fmt.Sprintf(`{"productId": %d, "quantity": 1}`, lamp.ID)))
+		if recorder.Code != http.StatusCreated {
+			t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+		}
+	}
+	recorder := serve(http.MethodPost, target+"/items", []byte(fmt.Sprintf(`{"productId": %d, "quantity": 1}`, lamp.ID)))
+	if recorder.Code != http.StatusConflict || decodeError(t, recorder).Code != "insufficient_stock" {
+		t.Errorf("Expected the cart to hold at most the stock, got %d %s", recorder.Code, recorder.Body)
+	}
+	recorder = serve(http.MethodPost, target+"/items", []byte(fmt.Sprintf(`{"productId": %d, "quantity": 3}`, mug.ID)))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var cart models.Cart
+	decodeData(t, recorder, &cart)
+	if len(cart.Sellers) != 2 || cart.Sellers[0].SellerID != lamp.SellerID || cart.Sellers[1].SellerID != seller.ID {
+		t.Fatalf("Expected the lines grouped by seller in the order they were added, got %+v", cart.Sellers)
+	}
+	if line := cart.Sellers[0].Lines[0]; line.ProductID != lamp.ID || line.Quantity != 2 || line.Subtotal != 30 {
+		t.Errorf("Expected the quantities of the lamp to add up, got %+v", line)
+	}
+	if cart.Items != 2 || cart.Quantity != 5 || cart.Total != 59.97 || cart.Sellers[1].Subtotal != 29.97 || cart.Changed {
+		t.Errorf("Unexpected totals %+v", cart)
+	}
+
+	price, stock := 20.0, 2
+	if _, err := store.UpdateProduct(ctx, lamp.ID, models.ProductUpdate{Price: &price}); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+	if _, err := store.UpdateProduct(ctx, mug.ID, models.ProductUpdate{Quantity: &stock}); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+	cart = cartOf(t, buyer.ID)
+	lampLine, mugLine := cart.Sellers[0].Lines[0], cart.Sellers[1].Lines[0]
+	if !lampLine.PriceChanged || lampLine.StockChanged || lampLine.AddedPrice != 15 || lampLine.Price != 20 {
+		t.Errorf("Expected the price change of the lamp to be flagged, got %+v", lampLine)
+	}
+	if mugLine.PriceChanged || !mugLine.StockChanged || !mugLine.InsufficientStock || mugLine.Stock != 2 {
+		t.Errorf("Expected the stock change of the mug to be flagged, got %+v", mugLine)
+	}
+	if !cart.Changed || cart.Total != 69.97 {
+		t.Errorf("Expected the cart to be changed and priced live, got %+v", cart)
+	}
+
+	recorder = serve(http.MethodPut, fmt.Sprintf("%s/items/%d", target, mug.ID), []byte(`{"quantity": 2}`))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	decodeData(t, recorder, &cart)
+	if mugLine = cart.Sellers[1].Lines[0]; mugLine.Quantity != 2 || mugLine.StockChanged || mugLine.InsufficientStock {
+		t.Errorf("Expected an update to take the stock as seen, got %+v", mugLine)
+	}
+
+	recorder = serve(http.MethodDelete, fmt.Sprintf("%s/items/%d", target, lamp.ID), nil)
+	if recorder.Code != http.StatusNoContent {
+		t.Errorf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	recorder = serve(http.MethodDelete, fmt.Sprintf("%s/items/%d", target, lamp.ID), nil)
+	if recorder.Code != http.StatusNotFound || decodeError(t, recorder).Code != "cart_item_not_found" {
+		t.Errorf("Expected a 404 for a product not in the cart, got %d %s", recorder.Code, recorder.Body)
+	}
+	if recorder = serve(http.MethodDelete, fmt.Sprintf("/api/v1/product/%d", mug.ID), nil); recorder.Code != http.StatusNoContent {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	if cart = cartOf(t, buyer.ID); cart.Items != 0 || cart.Total != 0 {
+		t.Errorf("Expected deleted products to leave the cart, got %+v", cart)
+	}
+
+	serve(http.MethodPost, target+"/items", []byte(fmt.Sprintf(`{"productId": %d, "quantity": 1}`, lamp.ID)))
+	if recorder = serve(http.MethodDelete, target, nil); recorder.Code != http.StatusNoContent {
+		t.Errorf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	if cart = cartOf(t, buyer.ID); cart.Items != 0 {
+		t.Errorf("Expected the cart to be cleared, got %+v", cart)
+	}
+}
+
+func TestCart_Errors(t *testing.T) {
+	buyer := createTestBuyer(t, "cart.errors@example.com")
+	target := fmt.Sprintf("/api/v1/buyer/%d/cart", buyer.ID)
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		code   string
+		fields []string
+	}{
+		{"unknown buyer", http.MethodGet, "/api/v1/buyer/99999/cart", "", http.StatusNotFound, "buyer_not_found", nil},
+		{"add for unknown buyer", http.MethodPost, "/api/v1/buyer/99999/cart/items", `{"productId": 1, "quantity": 1}`, http.StatusNotFound, "buyer_not_found", nil},
+		{"unknown product", http.MethodPost, target + "/items", `{"productId": 99999, "quantity": 1}`, http.StatusBadRequest, "validation_failed", []string{"productId"}},
+		{"no quantity", http.MethodPost, target + "/items", `{"productId": 1}`, http.StatusBadRequest, "validation_failed", []string{"quantity"}},
+		{"negative quantity", http.MethodPut, target + "/items/1", `{"quantity": -1}`, http.StatusBadRequest, "validation_failed", []string{"quantity"}},
+		{"not in cart", http.MethodPut, target + "/items/1", `{"quantity": 1}`, http.StatusNotFound, "cart_item_not_found", nil},
+		{"invalid product id", http.MethodDelete, target + "/items/abc", "", http.StatusBadRequest, "invalid_id", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			if tt.body != "" {
+				body = []byte(tt.body)
+			}
+			recorder := serve(tt.method, tt.target, body)
+			if recorder.Code != tt.status {
+				t.Fatalf("Expected %d, got %d %s", tt.status, recorder.Code, recorder.Body)
+			}
+			if code := decodeError(t, recorder).Code; code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, code)
+			}
+			if tt.fields != nil {
+				if fields := errorFields(t, recorder); fmt.Sprint(fields) != fmt.Sprint(tt.fields) {
+					t.Errorf("Expected invalid fields %v, got %v", tt.fields, fields)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// CreateCategory handles the creation of a category
+//
+// It is mounted on POST /api/v1/category. The below is json input, categories without a
+// parentId are roots of the tree
+//
+// Input:
+//
+//	{
+//	  "name": "Headphones",
+//	  "parentId": 2
+//	}
+//
+// Returns the saved category with a 201
+func CreateCategory(categories models.CategoryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var category models.Category
+		err := decodeJSON(r, &category)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = category.Validate(r.Context(), categories)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = categories.CreateCategory(r.Context(), &category)
+		if err != nil {
+			writeError(w, r, invalidParent(err))
+			return
+		}
+		response.JSON(w, http.StatusCreated, category)
+	}
+}
+
+// GetCategory responds with the category matching the id of the path
+//
+// It is mounted on GET /api/v1/category/{id}
+func GetCategory(categories models.CategoryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		category, err := categories.GetCategoryByID(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, category)
+	}
+}
+
+// GetCategoryTree responds with the whole category tree
+//
+// It is mounted on GET /api/v1/categories. Every node holds its subcategories and the number
+// of products of the category and of its descendants, siblings are sorted by name
+//
+//	{"data": [
+//	  {"id": 1, "parentId": 0, "name": "Electronics", "productCount": 12, "children": [
+//	    {"id": 2, "parentId": 1, "name": "Audio", "productCount": 5, "children": [...]}
+//	  ]}
+//	]}
+func GetCategoryTree(categories models.CategoryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		tree, err := categories.CategoryTree(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, tree)
+	}
+}
+
+// UpdateCategory renames the category matching the id of the path or moves it, along with its
+// descendants, under another parent
+//
+// It is mounted on PUT /api/v1/category/{id}, where the name is required and a missing parentId
+// makes the category a root, and on PATCH /api/v1/category/{id}, where only the fields given
+// are changed and a parentId of 0 makes the category a root
+func UpdateCategory(categories models.CategoryStore, partial bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var update models.CategoryUpdate
+		err = decodeJSON(r, &update)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		err = update.Validate(!partial)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if !partial && update.ParentID == nil {
+			root := 0
+			update.ParentID = &root
+		}
+
+		category, err := categories.UpdateCategory(r.Context(), id, update)
+		if err != nil {
+			writeError(w, r, invalidParent(err))
+			return
+		}
+		response.JSON(w, http.StatusOK, category)
+	}
+}
+
+// DeleteCategory deletes the category matching the id of the path
+//
+// It is mounted on DELETE /api/v1/category/{id} and responds with 204 No Content, or with a
+// 409 when the category still has subcategories or products
+func DeleteCategory(categories models.CategoryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = categories.DeleteCategory(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// invalidParent turns the errors of a category that cannot be placed under its parent into
+// an error on the parentId field, other errors are returned unchanged
+func invalidParent(err error) error {
+	switch {
+	case errors.Is(err, models.ErrCategoryParentNotFound):
+		return validation.Errors{{Field: "parentId", Message: "category does not exist"}}
+	case errors.Is(err, models.ErrCategoryCycle):
+		return validation.Errors{{Field: "parentId", Message: "cannot be the category or one of its descendants"}}
+	case errors.Is(err, models.ErrCategoryTooDeep):
+		return validation.Errors{{Field: "parentId", Message: "would nest categories deeper than the tree allows"}}
+	}
+	return err
+}
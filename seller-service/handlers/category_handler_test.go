@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// createTestCategory adds a category under the parent through the API
+func createTestCategory(t *testing.T, name string, parentID int) models.Category {
+	t.Helper()
+	recorder := serve(http.MethodPost, "/api/v1/category", []byte(fmt.Sprintf(`{"name": %q, "parentId": %d}`, name, parentID)))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 creating category %s, got %d: %s", name, recorder.Code, recorder.Body)
+	}
+	var category models.Category
+	decodeData(t, recorder, &category)
+	return category
+}
+
+// findCategoryNode returns the node of the category in the tree, nil when it is not in it
+func findCategoryNode(nodes []*models.CategoryNode, id int) *models.CategoryNode {
+	for _, node := range nodes {
+		if node.ID == id {
+			return node
+		}
+		if found := findCategoryNode(node.Children, id); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func TestCategories(t *testing.T) {
+	electronics := createTestCategory(t, "Electronics", 0)
+	audio := createTestCategory(t, "Audio", electronics.ID)
+	headphones := createTestCategory(t, "Headphones", audio.ID)
+	cameras := createTestCategory(t, "Cameras", electronics.ID)
+
+	recorder := serve(http.MethodPost, "/api/v1/product", []byte(fmt.Sprintf(`{"sellerId": 1, "productName": "Wireless Headphones", "price": 99, "quantity": 3, "categoryId": %d}`, headphones.ID)))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 creating a categorized product, got %d: %s", recorder.Code, recorder.Body)
+	}
+	var product models.Product
+	decodeData(t, recorder, &product)
+	camera := createTestProductNamed(t, "Action Camera", 250)
+	recorder = serve(http.MethodPatch, fmt.Sprintf("/api/v1/product/%d", camera.ID), []byte(fmt.Sprintf(`{"categoryId": %d}`, cameras.ID)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200 moving a product to a category, got %d: %s", recorder.Code, recorder.Body)
+	}
+
+	var products []models.Product
+	decodeData(t, serve(http.MethodGet, fmt.Sprintf("/api/v1/product/search?category=%d&sortBy=price", electronics.ID), nil), &products)
+	if len(products) != 2 || products[0].ID != product.ID || products[1].ID != camera.ID {
+		t.Errorf("Expected the products of the descendants of the category, got %+v", products)
+	}
+	decodeData(t, serve(http.MethodGet, fmt.Sprintf("/api/v1/product/search?category=%d", audio.ID), nil), &products)
+	if len(products) != 1 || products[0].ID != product.ID {
+		t.Errorf("Expected only the headphones in audio, got %+v", products)
+	}
+
+	var tree []*models.CategoryNode
+	decodeData(t, serve(http.MethodGet, "/api/v1/categories", nil), &tree)
+	root := findCategoryNode(tree, electronics.ID)
+	if root == nil || root.ProductCount != 2 || len(root.Children) != 2 {
+		t.Fatalf("Unexpected tree node %+v", root)
+	}
+	if names := []string{root.Children[0].Name, root.Children[1].Name}; !reflect.DeepEqual(names, []string{"Audio", "Cameras"}) {
+		t.Errorf("Expected children sorted by name, got %v", names)
+	}
+	if node := findCategoryNode(tree, headphones.ID); node == nil || node.ProductCount != 1 || node.ParentID != audio.ID {
+		t.Errorf("Unexpected tree node %+v", node)
+	}
+
+	// moving audio under cameras takes headphones along
+	recorder = serve(http.MethodPatch, fmt.Sprintf("/api/v1/category/%d", audio.ID), []byte(fmt.Sprintf(`{"parentId": %d}`, cameras.ID)))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200 moving a category, got %d: %s", recorder.Code, recorder.Body)
+	}
+	decodeData(t, serve(http.MethodGet, fmt.Sprintf("/api/v1/product/search?category=%d", cameras.ID), nil), &products)
+	if len(products) != 2 {
+		t.Errorf("Expected the moved subtree to be searched under its new parent, got %+v", products)
+	}
+
+	recorder = serve(http.MethodPut, fmt.Sprintf("/api/v1/category/%d", cameras.ID), []byte(`{"name": "Cameras & Photo"}`))
+	var renamed models.Category
+	decodeData(t, recorder, &renamed)
+	if recorder.Code != http.StatusOK || renamed.Name != "Cameras & Photo" || renamed.ParentID != 0 {
+		t.Errorf("Expected PUT without parentId to make a root, got %d %+v", recorder.Code, renamed)
+	}
+}
+
+func TestCategories_Errors(t *testing.T) {
+	parent := createTestCategory(t, "Parent", 0)
+	child := createTestCategory(t, "Child", parent.ID)
+	createTestProductNamed(t, "Uncategorized", 1)
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+		fields []string
+	}{
+		{"missing name", http.MethodPost, "/api/v1/category", `{"parentId": 0}`, http.StatusBadRequest, []string{"name"}},
+		{"unknown parent", http.MethodPost, "/api/v1/category", `{"name": "Orphan", "parentId": 999999}`, http.StatusBadRequest, []string{"parentId"}},
+		{"cycle", http.MethodPatch, fmt.Sprintf("/api/v1/category/%d", parent.ID), fmt.Sprintf(`{"parentId": %d}`, child.ID), http.StatusBadRequest, []string{"parentId"}},
+		{"own parent", http.MethodPatch, fmt.Sprintf("/api/v1/category/%d", parent.ID), fmt.Sprintf(`{"parentId": %d}`, parent.ID), http.StatusBadRequest, []string{"parentId"}},
+		{"not empty", http.MethodDelete, fmt.Sprintf("/api/v1/category/%d", parent.ID), "", http.StatusConflict, nil},
+		{"not found", http.MethodGet, "/api/v1/category/999999", "", http.StatusNotFound, nil},
+		{"unknown product category", http.MethodPost, "/api/v1/product", `{"sellerId": 1, "productName": "X", "price": 1, "quantity": 1, "categoryId": 999999}`, http.StatusBadRequest, []string{"categoryId"}},
+		{"invalid search category", http.MethodGet, "/api/v1/product/search?category=audio", "", http.StatusBadRequest, []string{"category"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body []byte
+			if tt.body != "" {
+				body = []byte(tt.body)
+			}
+			recorder := serve(tt.method, tt.target, body)
+			if recorder.Code != tt.status {
+				t.Fatalf("Expected %d, got %d: %s", tt.status, recorder.Code, recorder.Body)
+			}
+			if fields := errorFields(t, recorder); tt.fields != nil && !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Expected errors on %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+
+	if recorder := serve(http.MethodDelete, fmt.Sprintf("/api/v1/category/%d", child.ID), nil); recorder.Code != http.StatusNoContent {
+		t.Errorf("Expected 204 deleting an empty category, got %d", recorder.Code)
+	}
+}
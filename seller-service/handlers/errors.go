@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// domainErrors maps the errors of the stores to the status and code returned to the client
+var domainErrors = []struct {
+	err     error
+	status  int
+	code    string
+	message string
+}{
+	{models.ErrSellerNotFound, http.StatusNotFound, "seller_not_found", "Seller not found"},
+	{models.ErrProductNotFound, http.StatusNotFound, "product_not_found", "Product not found"},
+	{models.ErrSellerHasProducts, http.StatusConflict, "seller_has_products", "Seller still has products, delete them first or pass cascade=true"},
+	{models.ErrCategoryNotFound, http.StatusNotFound, "category_not_found", "Category not found"},
+	{models.ErrCategoryNotEmpty, http.StatusConflict, "category_not_empty", "Category still has subcategories or products, move or delete them first"},
+	{models.ErrSynonymGroupNotFound, http.StatusNotFound, "synonym_group_not_found", "Synonym group not found"},
+	{models.ErrSavedSearchNotFound, http.StatusNotFound, "saved_search_not_found", "Saved search not found"},
+	{models.ErrBuyerNotFound, http.StatusNotFound, "buyer_not_found", "Buyer not found"},
+	{models.ErrBuyerEmailTaken, http.StatusConflict, "buyer_email_taken", "Email is already registered"},
+	{models.ErrBuyerAddressNotFound, http.StatusNotFound, "buyer_address_not_found", "Buyer address not found"},
+	{models.ErrTooManyBuyerAddresses, http.StatusConflict, "too_many_buyer_addresses", "Address book is full, delete an address first"},
+	{models.ErrCartItemNotFound, http.StatusNotFound, "cart_item_not_found", "Product is not in the cart"},
+	{models.ErrTooManyCartItems, http.StatusConflict, "too_many_cart_items", "Cart is full, remove a product first"},
+	{models.ErrInsufficientStock, http.StatusConflict, "insufficient_stock", "Not enough of the product in stock"},
+	{models.ErrOrderNotFound, http.StatusNotFound, "order_not_found", "Order not found"},
+	{models.ErrNothingToUpdate, http.StatusBadRequest, response.CodeValidationFailed, "Nothing to update"},
+}
+
+// writeError responds with err in the error envelope, mapping the domain errors to their status
+// and validation errors to a 400 listing every invalid field
+func writeError(w http.ResponseWriter, r *http.Request, err error) {
+	var invalid validation.Errors
+	if errors.As(err, &invalid) {
+		details := make([]response.FieldError, len(invalid))
+		for i, fe := range invalid {
+			details[i] = response.FieldError{Field: fe.Field, Message: fe.Message}
+		}
+		response.Err(w, r, response.BadRequest(response.CodeValidationFailed, "Validation failed", details...))
+		return
+	}
+	response.Err(w, r, domainError(err))
+}
+
+// domainError returns the response of domainErrors matching err with the details, or err when
+// none does
+func domainError(err error, details ...response.FieldError) error {
+	for _, d := range domainErrors {
+		if errors.Is(err, d.err) {
+			return response.NewError(d.status, d.code, d.message, details...)
+		}
+	}
+	return err
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/router"
+)
+
+// pathID parses the {id} parameter of the path, ids are positive integers
+func pathID(r *http.Request) (int, error) {
+	return pathParamID(r, "id")
+}
+
+// pathParamID parses a parameter of the path holding an id, such as {addressId}
+func pathParamID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(router.Param(r, name))
+	if err != nil || id <= 0 {
+		return 0, response.BadRequest(response.CodeInvalidID, "invalid id")
+	}
+	return id, nil
+}
+
+// decodeJSON reads the JSON body of the request into v. The body must be a single JSON
+// document without fields unknown to v, so typos in field names are not silently ignored
+func decodeJSON(r *http.Request, v interface{}) error {
+	defer r.Body.Close()
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	err := dec.Decode(v)
+	if err == nil && dec.Decode(&struct{}{}) != io.EOF {
+		err = errors.New("unexpected data after the JSON document")
+	}
+	if err == nil {
+		return nil
+	}
+	logging.FromContext(r.Context()).Debugf("%v", err.Error())
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return response.BadRequest(response.CodeInvalidBody, "Invalid request body",
+			response.FieldError{Field: typeErr.Field, Message: "must be a " + typeErr.Type.String()})
+	}
+	if strings.HasPrefix(err.Error(), "json: unknown field ") {
+		field, _ := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+		return response.BadRequest(response.CodeInvalidBody, "Invalid request body",
+			response.FieldError{Field: field, Message: "is not a known field"})
+	}
+	return response.BadRequest(response.CodeInvalidBody, "Invalid request body")
+}
+
+// queryParams parses the query parameters of a request, a parameter that is given but
+// cannot be parsed is recorded in errs instead of being replaced by its default
+type queryParams struct {
+	values url.Values
+	errs   validation.Errors
+}
+
+func newQueryParams(r *http.Request) *queryParams {
+	return &queryParams{values: r.URL.Query()}
+}
+
+// String returns the parameter, or def when it is not given
+func (q *queryParams) String(name, def string) string {
+	if v := q.values.Get(name); v != "" {
+		return v
+	}
+	return def
+}
+
+// List returns the comma separated values of the parameter, nil when it is not given
+func (q *queryParams) List(name string) []string {
+	var list []string
+	for _, v := range strings.Split(q.values.Get(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// Int returns the parameter as an int, or def when it is not given
+func (q *queryParams) Int(name string, def int) int {
+	v := q.values.Get(name)
+	if v == "" {
+		return def
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		q.errs.Add(name, "must be an integer")
+		return def
+	}
+	return i
+}
+
+// Uint returns the parameter as an uint64, or def when it is not given
+func (q *queryParams) Uint(name string, def uint64) uint64 {
+	v := q.values.Get(name)
+	if v == "" {
+		return def
+	}
+	u, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		q.errs.Add(name, "must be a non negative integer")
+		return def
+	}
+	return u
+}
+
+// Float returns the parameter as a float64, or def when it is not given. NaN and infinities are
+// not numbers, they would pass the min and max validations
+func (q *queryParams) Float(name string, def float64) float64 {
+	v := q.values.Get(name)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		q.errs.Add(name, "must be a number")
+		return def
+	}
+	return f
+}
+
+// Bool returns the parameter as a bool, or def when it is not given
+func (q *queryParams) Bool(name string, def bool) bool {
+	v := q.values.Get(name)
+	if v == "" {
+		return def
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		q.errs.Add(name, "must be true or false")
+		return def
+	}
+	return b
+}
+
+// Time returns the parameter as a time in RFC 3339 format, or def when it is not given
+func (q *queryParams) Time(name string, def time.Time) time.Time {
+	v := q.values.Get(name)
+	if v == "" {
+		return def
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		q.errs.Add(name, "must be a time in RFC 3339 format, e.g. 2024-01-31T18:00:00Z")
+		return def
+	}
+	return t
+}
+
+// GeoPoint returns the parameter as a point, see models.ParseGeoPoint, or nil when it is not given
+func (q *queryParams) GeoPoint(name string) *models.GeoPoint {
+	v := q.values.Get(name)
+	if v == "" {
+		return nil
+	}
+	p, err := models.ParseGeoPoint(v)
+	if err != nil {
+		q.errs.Add(name, err.Error())
+		return nil
+	}
+	return &p
+}
+
+// Attributes returns the attribute filters of the parameters named attr.<name>, see
+// models.ParseAttributeFilter, sorted by name so the order of the parameters does not matter
+func (q *queryParams) Attributes() []models.AttributeFilter {
+	var filters []models.AttributeFilter
+	for key, values := range q.values {
+		for _, value := range values {
+			if f, ok := models.ParseAttributeFilter(key, value); ok {
+				filters = append(filters, f)
+			}
+		}
+	}
+	sort.Slice(filters, func(i, j int) bool {
+		a, b := filters[i], filters[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Op != b.Op {
+			return a.Op < b.Op
+		}
+		return a.Value < b.Value
+	})
+	return filters
+}
+
+// Pagination returns the page and perPage parameters, defaulting to the first page of 10 records
+func (q *queryParams) Pagination() (page, perPage uint64) {
+	return q.Uint("page", 1), q.Uint("perPage", 10)
+}
+
+// Validate adds the parse errors to the violations found by validate, the Validate method of
+// the model built from the parameters, so all of them are reported at once
+func (q *queryParams) Validate(validate func() error) error {
+	err := validate()
+	var invalid validation.Errors
+	if err != nil && !errors.As(err, &invalid) {
+		return err
+	}
+	for _, fe := range invalid {
+		if !q.errs.Has(fe.Field) {
+			q.errs = append(q.errs, fe)
+		}
+	}
+	return q.errs.Err()
+}
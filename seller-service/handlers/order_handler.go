@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// PlaceOrder handles the purchase of products by a buyer
+//
+// It is mounted on POST /api/v1/orders. The quantities are taken off the stock of the products
+// and the order saved at once, a line asking for more than the stock gets a 409 and nothing is
+// ordered. The prices are those of the products at purchase and the ordered products leave the
+// cart of the buyer. The below is json input
+//
+// Input:
+//
+//	{
+//	  "buyerId": 7,
+//	  "lines": [
+//	    {"productId": 42, "quantity": 2},
+//	    {"productId": 43, "quantity": 1}
+//	  ]
+//	}
+//
+// Output:
+//
+//	{
+//	  "data": {
+//	    "id": 1,
+//	    "buyerId": 7,
+//	    "lines": [
+//	      {"productId": 42, "quantity": 2, "sellerId": 1, "productName": "Mini Drone", "unitPrice": 120, "subtotal": 240},
+//	      {"productId": 43, "quantity": 1, "sellerId": 3, "productName": "Drone Battery", "unitPrice": 19.99, "subtotal": 19.99}
+//	    ],
+//	    "total": 259.99,
+//	    "createdAt": "2024-01-31T17:58:02.113Z"
+//	  }
+//	}
+//
+// Returns the placed order with a 201
+func PlaceOrder(orders models.OrderStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var order models.Order
+		err := decodeJSON(r, &order)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = order.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = orders.PlaceOrder(r.Context(), &order)
+		if err != nil {
+			writeError(w, r, orderError(&order, err))
+			return
+		}
+		response.JSON(w, http.StatusCreated, order)
+	}
+}
+
+// orderError points the error of a line of the order to the fields of the line: an unknown
+// product is invalid and a short stock is a conflict detailing the quantity available
+func orderError(order *models.Order, err error) error {
+	if errors.Is(err, models.ErrBuyerNotFound) {
+		return invalidBuyer()
+	}
+	var lineErr *models.OrderLineError
+	if !errors.As(err, &lineErr) {
+		return err
+	}
+	i := 0
+	for i < len(order.Lines)-1 && order.Lines[i].ProductID != lineErr.ProductID {
+		i++
+	}
+	if errors.Is(lineErr, models.ErrProductNotFound) {
+		return validation.Errors{{Field: order.LineField(i, "productId"), Message: "product does not exist"}}
+	}
+	return domainError(lineErr, response.FieldError{Field: order.LineField(i, "quantity"), Message: "only " + strconv.Itoa(lineErr.Available) + " in stock"})
+}
+
+// GetOrder responds with the order matching the id of the path along with its lines
+//
+// It is mounted on GET /api/v1/order/{id}
+func GetOrder(orders models.OrderStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		order, err := orders.GetOrderByID(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, order)
+	}
+}
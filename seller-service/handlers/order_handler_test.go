@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+func TestPlaceOrder(t *testing.T) {
+	ctx := context.Background()
+	buyer := createTestBuyer(t, "orders@example.com")
+	lamp := createTestProductNamed(t, "Order Lamp", 15)
+	seller := createTestSeller(t, 0)
+	// the facets of the searches count the sellers, this one leaves with its products
+	t.Cleanup(func() { store.DeleteSeller(ctx, seller.ID, true) })
+	mug := models.Product{SellerID: seller.ID, ProductName: "Order Mug", Price: 9.99, Quantity: 5}
+	if err := store.CreateProduct(ctx, &mug); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	if err := store.AddCartItem(ctx, &models.CartItem{BuyerID: buyer.ID, ProductID: mug.ID, Quantity: 1}); err != nil {
+		t.Fatalf("Failed to add cart item: %v", err)
+	}
+
+	body := fmt.Sprintf(`{"buyerId": %d, "lines": [{"productId": %d, "quantity": 2}, {"productId": %d, "quantity": 3}]}`, buyer.ID, mug.ID, lamp.ID)
+	recorder := serve(http.MethodPost, "/api/v1/orders", []byte(body))
+	if recorder.Code != http.StatusConflict || decodeError(t, recorder).Code != "insufficient_stock" {
+		t.Fatalf("Expected the lamp to be short, got %d %s", recorder.Code, recorder.Body)
+	}
+	if fields := errorFields(t, recorder); len(fields) != 1 || fields[0] != "lines[1].quantity" {
+		t.Errorf("Expected the quantity of the lamp to be pointed at, got %v", fields)
+	}
+	if p, _ := store.GetProductByID(ctx, mug.ID); p.Quantity != 5 {
+		t.Errorf("Expected a refused order to leave the stock alone, got %d", p.Quantity)
+	}
+
+	body = fmt.Sprintf(`{"buyerId": %d, "lines": [{"productId": %d, "quantity": 2}, {"productId": %d, "quantity": 2}]}`, buyer.ID, mug.ID, lamp.ID)
+	recorder = serve(http.MethodPost, "/api/v1/orders", []byte(body))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var order models.Order
+	decodeData(t, recorder, &order)
+	if order.ID == 0 || order.Total != 49.98 || len(order.Lines) != 2 || order.CreatedAt.IsZero() {
+		t.Fatalf("Unexpected order %+v", order)
+	}
+	if line := order.Lines[0]; line.ProductName != "Order Mug" || line.SellerID != seller.ID || line.UnitPrice != 9.99 || line.Subtotal != 19.98 {
+		t.Errorf("Expected the line to snapshot the product, got %+v", line)
+	}
+	if p, _ := store.GetProductByID(ctx, mug.ID); p.Quantity != 3 {
+		t.Errorf("Expected the stock of the mug to drop to 3, got %d", p.Quantity)
+	}
+	if p, _ := store.GetProductByID(ctx, lamp.ID); p.Quantity != 0 {
+		t.Errorf("Expected the lamp to be sold out, got %d", p.Quantity)
+	}
+	if cart := cartOf(t, buyer.ID); cart.Items != 0 {
+		t.Errorf("Expected the ordered products to leave the cart, got %+v", cart)
+	}
+
+	price := 12.5
+	if _, err := store.UpdateProduct(ctx, mug.ID, models.ProductUpdate{Price: &price}); err != nil {
+		t.Fatalf("Failed to update product: %v", err)
+	}
+	recorder = serve(http.MethodGet, fmt.Sprintf("/api/v1/order/%d", order.ID), nil)
+	var got models.Order
+	decodeData(t, recorder, &got)
+	if got.Total != order.Total || got.Lines[0].UnitPrice != 9.99 {
+		t.Errorf("Expected the order to keep the prices at purchase, got %+v", got)
+	}
+}
+
+func TestPlaceOrder_LastUnit(t *testing.T) {
+	buyer := createTestBuyer(t, "last.unit@example.com")
+	product := createTestProductNamed(t, "Last Unit", 50)
+	body := []byte(fmt.Sprintf(`{"buyerId": %d, "lines": [{"productId": %d, "quantity": 1}]}`, buyer.ID, product.ID))
+
+	// two units are in stock, the first order takes one and the racing orders fight for the other
+	if recorder := serve(http.MethodPost, "/api/v1/orders", body); recorder.Code != http.StatusCreated {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	const orders = 20
+	codes := make(chan int, orders)
+	var wg sync.WaitGroup
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			codes <- serve(http.MethodPost, "/api/v1/orders", body).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	placed := 0
+	for code := range codes {
+		switch code {
+		case http.StatusCreated:
+			placed++
+		case http.StatusConflict:
+		default:
+			t.Errorf("Unexpected response status code: %d", code)
+		}
+	}
+	if placed != 1 {
+		t.Errorf("Expected exactly one order to get the last unit, %d did", placed)
+	}
+	if p, _ := store.GetProductByID(context.Background(), product.ID); p.Quantity != 0 {
+		t.Errorf("Expected the product to be sold out, got %d", p.Quantity)
+	}
+}
+
+func TestPlaceOrder_Errors(t *testing.T) {
+	buyer := createTestBuyer(t, "order.errors@example.com")
+	tests := []struct {
+		name   string
+		body   string
+		status int
+		code   string
+		fields []string
+	}{
+		{"no lines", fmt.Sprintf(`{"buyerId": %d}`, buyer.ID), http.StatusBadRequest, "validation_failed", []string{"lines"}},
+		{"invalid lines", `{"buyerId": 0, "lines": [{"productId": 0, "quantity": 1}, {"productId": 1, "quantity": 0}]}`, http.StatusBadRequest, "validation_failed", []string{"buyerId", "lines[0].productId", "lines[1].quantity"}},
+		{"same product twice", fmt.Sprintf(`{"buyerId": %d, "lines": [{"productId": 1, "quantity": 1}, {"productId": 1, "quantity": 1}]}`, buyer.ID), http.StatusBadRequest, "validation_failed", []string{"lines[1].productId"}},
+		{"unknown buyer", `{"buyerId": 99999, "lines": [{"productId": 1, "quantity": 1}]}`, http.StatusBadRequest, "validation_failed", []string{"buyerId"}},
+		{"unknown product", fmt.Sprintf(`{"buyerId": %d, "lines": [{"productId": 1, "quantity": 1}, {"productId": 99999, "quantity": 1}]}`, buyer.ID), http.StatusBadRequest, "validation_failed", []string{"lines[1].productId"}},
+		{"unknown field", fmt.Sprintf(`{"buyerId": %d, "lines": [], "coupon": "FREE"}`, buyer.ID), http.StatusBadRequest, "invalid_body", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(http.MethodPost, "/api/v1/orders", []byte(tt.body))
+			if recorder.Code != tt.status {
+				t.Fatalf("Expected %d, got %d %s", tt.status, recorder.Code, recorder.Body)
+			}
+			if code := decodeError(t, recorder).Code; code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, code)
+			}
+			if tt.fields != nil {
+				if fields := errorFields(t, recorder); fmt.Sprint(fields) != fmt.Sprint(tt.fields) {
+					t.Errorf("Expected invalid fields %v, got %v", tt.fields, fields)
+				}
+			}
+		})
+	}
+
+	recorder := serve(http.MethodGet, "/api/v1/order/99999", nil)
+	if recorder.Code != http.StatusNotFound || decodeError(t, recorder).Code != "order_not_found" {
+		t.Errorf("Expected a 404 for an unknown order, got %d %s", recorder.Code, recorder.Body)
+	}
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/suggest"
+)
+
+// pageMeta is the pagination of a page of products, the cursors are passed back in the
+// `cursor` parameter to read the next or the previous page
+type pageMeta struct {
+	PerPage    uint64 `json:"perPage"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+	Total      *int   `json:"total,omitempty"`
+	// Facets are the counts of the facets the request asked for
+	Facets models.Facets `json:"facets,omitempty"`
+	// DidYouMean is the searched text with its misspelled words corrected, given when the first
+	// page has fewer than fewResults products
+	DidYouMean string `json:"didYouMean,omitempty"`
+}
+
+// fewResults is the number of products under which a search suggests a spelling correction
+const fewResults = 3
+
+// Cursor returns the cursor parameter, nil when it is not given
+func (q *queryParams) Cursor() *models.Cursor {
+	v := q.values.Get("cursor")
+	if v == "" {
+		return nil
+	}
+	cursor, err := models.DecodeCursor(v)
+	if err != nil {
+		q.errs.Add("cursor", "is not a cursor returned by the API")
+		return nil
+	}
+	return &cursor
+}
+
+// writeProductPage searches the products of the request and responds with the page. The
+// cursors of the next and previous pages are in the meta, and in the Link header of GET
+// requests, along with the total number of matching products when withTotal is set and the
+// facets of the request. When vocabulary is set and the first page has few products, the
+// searched text corrected against the words of the product names is in the meta too.
+// Returns the number of products of the page, false when it responded with an error
+func writeProductPage(w http.ResponseWriter, r *http.Request, products models.ProductStore, req *models.ProductRequest, withTotal bool, vocabulary *suggest.Index) (int, bool) {
+	page, err := products.SearchProducts(r.Context(), req)
+	if err != nil {
+		writeError(w, r, err)
+		return 0, false
+	}
+	meta := pageMeta{PerPage: req.PerPage}
+	if withTotal {
+		total, err := products.CountProducts(r.Context(), req)
+		if err != nil {
+			writeError(w, r, err)
+			return 0, false
+		}
+		meta.Total = &total
+	}
+	if len(req.Facets) > 0 {
+		meta.Facets, err = products.FacetProducts(r.Context(), req)
+		if err != nil {
+			writeError(w, r, err)
+			return 0, false
+		}
+	}
+
+	result := page.Products
+	if result == nil {
+		result = []models.Product{}
+	}
+	if len(result) > 0 {
+		// a page read backwards came from the page after it, one read forwards from the page
+		// before it unless it is the first one
+		backward := req.Cursor != nil && req.Cursor.Backward
+		hasNext, hasPrev := page.HasMore, req.Cursor != nil || req.Offset() > 0
+		if backward {
+			hasNext, hasPrev = true, page.HasMore
+		}
+		if hasNext {
+			meta.NextCursor = models.CursorAt(result[len(result)-1], req.SortBy, false).Encode()
+		}
+		if hasPrev {
+			meta.PrevCursor = models.CursorAt(result[0], req.SortBy, true).Encode()
+		}
+	}
+
+	if vocabulary != nil && len(result) < fewResults && req.Cursor == nil && req.Offset() == 0 {
+		meta.DidYouMean = vocabulary.Correct(req.SearchText(), req.Synonyms.Has)
+	}
+
+	// the links of a POST search would lose its body
+	if r.Method == http.MethodGet {
+		links := []string{link(r.URL, "", "first")}
+		if meta.NextCursor != "" {
+			links = append(links, link(r.URL, meta.NextCursor, "next"))
+		}
+		if meta.PrevCursor != "" {
+			links = append(links, link(r.URL, meta.PrevCursor, "prev"))
+		}
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+	response.JSONWithMeta(w, http.StatusOK, result, meta)
+	return len(result), true
+}
+
+// link formats a Link header value to the request URL with the cursor in place of the page
+func link(u *url.URL, cursor, rel string) string {
+	query := u.Query()
+	query.Del("page")
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	target := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return "<" + target.String() + `>; rel="` + rel + `"`
+}
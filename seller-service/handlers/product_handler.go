@@ -1,17 +1,16 @@
 package handlers
 
 import (
-	"encoding/json"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/utils"
-	"io"
 	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
 )
 
-// ProductHandler handles the creation of a product
+// CreateProduct handles the creation of a product
 //
-// POST is the only operation supported by this API. The below is json input
+// It is mounted on POST /api/v1/product. The below is json input
 //
 // Input:
 //
@@ -19,64 +18,166 @@ import (
 //	 		"sellerId": 1,
 //	 		"productName": "Product Name",
 //	 		"price": 10.0,
-//	 		"quantity": 5
+//	 		"quantity": 5,
+//	 		"description": "Optional description",
+//	 		"categoryId": 3
 //	 	}
 //
 // Output:
 //
 //	{
-//		"id": 1
+//		"data": {
+//			"id": 1,
+//			"sellerId": 1,
+//			"productName": "Product Name",
+//			"price": 10.0,
+//			"quantity": 5,
+//			"description": "Optional description",
+//			"categoryId": 3
+//		}
 //	}
 //
-// Returns a saved record id back as JSON response or error if any
-func ProductHandler(w http.ResponseWriter, r *http.Request) {
-	// Set up panic handler
-	defer utils.PanicHandler(w)
+// Returns the saved product back as JSON response or error if any
+func CreateProduct(products models.ProductStore, sellers models.SellerStore, categories models.CategoryStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Parse request body
+		var input models.ProductInput
+		err := decodeJSON(r, &input)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		product := input.Product()
 
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
-	}
+		// Validate the product
+		err = product.Validate(r.Context(), sellers, categories)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
 
-	// Read request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
-		return
-	}
-	defer r.Body.Close()
+		// Save the product in the database
+		err = products.CreateProduct(r.Context(), &product)
+		if err == models.ErrSellerNotFound {
+			err = invalidSeller()
+		}
+		if err == models.ErrCategoryNotFound {
+			err = invalidCategory()
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
 
-	// Parse request body
-	var product *models.Product
-	err = json.Unmarshal(body, &product)
-	if err != nil {
-		logging.GetLogger().Debugf("%v", err.Error())
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+		// Respond with the created product
+		response.JSON(w, http.StatusCreated, product)
 	}
+}
 
-	// Validate the product
-	err = product.Validate()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
-		return
+// GetProduct responds with the product matching the id of the path
+//
+// It is mounted on GET /api/v1/product/{id}
+//
+// Output:
+//
+//	{
+//		"data": {
+//			"id": 1,
+//			"sellerId": 1,
+//			"productName": "Product Name",
+//			"price": 10.0,
+//			"quantity": 5
+//		}
+//	}
+func GetProduct(products models.ProductStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		product, err := products.GetProductByID(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, product)
 	}
+}
 
-	// Save the product in the database
-	err = product.Save()
-	if err != nil {
-		http.Error(w, "Failed to save product", http.StatusInternalServerError)
-		return
+// UpdateProduct changes the name, price, quantity, description or category of the product matching the id of the path
+//
+// It is mounted on PUT /api/v1/product/{id}, where every field is required, and on
+// PATCH /api/v1/product/{id}, where only the fields given are changed. The below is json input
+//
+// Input:
+//
+//	{
+//		"productName": "Product Name",
+//		"price": 12.5,
+//		"quantity": 20
+//	}
+//
+// Returns the updated product
+func UpdateProduct(products models.ProductStore, partial bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var update models.ProductUpdate
+		err = decodeJSON(r, &update)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		err = update.Validate(!partial)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		product, err := products.UpdateProduct(r.Context(), id, update)
+		if err == models.ErrCategoryNotFound {
+			err = invalidCategory()
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, product)
 	}
+}
+
+// DeleteProduct deletes the product matching the id of the path
+//
+// It is mounted on DELETE /api/v1/product/{id} and responds with 204 No Content
+func DeleteProduct(products models.ProductStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
 
-	// Respond with the created product ID
-	response := struct {
-		ID int `json:"id"`
-	}{
-		ID: product.ID,
+		err = products.DeleteProduct(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
 	}
+}
+
+// invalidSeller is the error of a product referencing a seller that does not exist
+func invalidSeller() error {
+	return validation.Errors{{Field: "sellerId", Message: "seller does not exist"}}
+}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(response)
+// invalidCategory is the error of a product referencing a category that does not exist
+func invalidCategory() error {
+	return validation.Errors{{Field: "categoryId", Message: "category does not exist"}}
 }
@@ -2,32 +2,45 @@ package handlers
 
 import (
 	"bytes"
-	"database/sql"
+	"context"
 	"encoding/json"
 	"fmt"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/db"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/alerts"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/analytics"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/memstore"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/middleware"
 	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
 	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/suggest"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/synonyms"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
-var (
-	dbUser     = os.Getenv("MYSQL_USER")
-	dbPassword = os.Getenv("MYSQL_PASSWORD")
-	dbName     = os.Getenv("MYSQL_DATABASE")
-	dbHostName = os.Getenv("MYSQL_HOSTNAME")
-	dbPort     = os.Getenv("MYSQL_PORT")
-)
+// store is shared by the handler tests and seeded once in TestMain
+var store *memstore.Store
 
-func TestProductHandler(t *testing.T) {
-	err := setupTestDatabase()
-	if err != nil {
-		t.Fatalf("Unable to run tests")
-	}
+// routes serves the API backed by store
+var routes http.Handler
+
+// searches records the searches served by routes into store
+var searches *analytics.Recorder
+
+// matcher alerts notifier of the products created and restocked through routes
+var matcher *alerts.Matcher
+var notifier = &recordingNotifier{}
+
+// adminToken is the bearer token of the admin endpoints of routes
+const adminToken = "test-admin-token"
+
+func TestCreateProduct(t *testing.T) {
 	product := models.Product{
 		SellerID:    1,
 		ProductName: "Sample Product",
@@ -39,11 +52,11 @@ func TestProductHandler(t *testing.T) {
 		t.Fatalf("Failed to marshal request payload: %v", err)
 	}
 
-	req := httptest.NewRequest(http.MethodPost, "/product", bytes.NewReader(payload))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/product", bytes.NewReader(payload))
 
 	recorder := httptest.NewRecorder()
 
-	ProductHandler(recorder, req)
+	routes.ServeHTTP(recorder, req)
 	res := recorder.Result()
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
@@ -56,39 +69,42 @@ func TestProductHandler(t *testing.T) {
 	}
 
 	var response struct {
-		ID int `json:"id"`
+		Data models.Product `json:"data"`
 	}
 	err = json.Unmarshal(body, &response)
 	if err != nil {
 		t.Fatalf("Failed to unmarshal response body: %v", err)
 	}
 
-	if response.ID == 0 {
+	if response.Data.ID == 0 {
 		t.Error("Product ID should not be zero")
 	}
+	if response.Data.ProductName != product.ProductName {
+		t.Errorf("Expected the created product back, got %+v", response.Data)
+	}
 }
 
-func TestProductHandler_InvalidPayload(t *testing.T) {
+func TestCreateProduct_InvalidPayload(t *testing.T) {
 	invalidPayload := []byte(`{"invalid": "payload"`)
-	req := httptest.NewRequest(http.MethodPost, "/product", bytes.NewReader(invalidPayload))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/product", bytes.NewReader(invalidPayload))
 	recorder := httptest.NewRecorder()
-	ProductHandler(recorder, req)
+	routes.ServeHTTP(recorder, req)
 	res := recorder.Result()
 	if res.StatusCode != http.StatusBadRequest {
 		t.Errorf("Unexpected response status code: %d", res.StatusCode)
 	}
 }
 
-func TestProductHandler_InvalidMethod(t *testing.T) {
-	req := httptest.NewRequest(http.MethodGet, "/product", nil)
+func TestCreateProduct_InvalidMethod(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/product", nil)
 	recorder := httptest.NewRecorder()
-	ProductHandler(recorder, req)
+	routes.ServeHTTP(recorder, req)
 	res := recorder.Result()
 	if res.StatusCode != http.StatusMethodNotAllowed {
 		t.Errorf("Unexpected response status code: %d", res.StatusCode)
 	}
 }
-func TestProductHandler_SaveErrorInvalidSellerId(t *testing.T) {
+func TestCreateProduct_SaveErrorInvalidSellerId(t *testing.T) {
 	product := models.Product{
 		SellerID:    1123,
 		ProductName: "Sample Product",
@@ -100,45 +116,20 @@ func TestProductHandler_SaveErrorInvalidSellerId(t *testing.T) {
 		t.Fatalf("Failed to marshal request payload: %v", err)
 	}
 
-	req := httptest.NewRequest(http.MethodPost, "/product", bytes.NewReader(payload))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/product", bytes.NewReader(payload))
 	recorder := httptest.NewRecorder()
 
-	ProductHandler(recorder, req)
+	routes.ServeHTTP(recorder, req)
 
 	res := recorder.Result()
 
 	if res.StatusCode != http.StatusBadRequest {
 		t.Errorf("Unexpected response status code: %d", res.StatusCode)
 	}
-}
-
-func dropTestDatabase(tables ...string) error {
-	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPassword, dbHostName, dbPort, dbName))
-	if err != nil {
-		return fmt.Errorf("failed to connect to the database: %v", err)
+	apiErr := decodeError(t, recorder)
+	if apiErr.Code != response.CodeValidationFailed || len(apiErr.Details) != 1 || apiErr.Details[0].Field != "sellerId" {
+		t.Errorf("Expected a validation error on sellerId, got %+v", apiErr)
 	}
-	// DISABLE Foreign Key Checks
-	_, err = db.Exec("SET FOREIGN_KEY_CHECKS = 0")
-	if err != nil {
-		fmt.Println("Failed to disable foreign key checks:", err)
-		return err
-	}
-	for _, table := range tables {
-		stmt := fmt.Sprintln("TRUNCATE TABLE " + table + ";")
-
-		_, err := db.Exec(stmt)
-		if err != nil {
-			fmt.Printf("Failed to truncate table %s: %v\n", table, err)
-		} else {
-			fmt.Printf("Successfully truncated table %s\n", table)
-		}
-	}
-	// Enable Foreign key checks
-	_, err = db.Exec("SET FOREIGN_KEY_CHECKS = 1")
-	if err != nil {
-		fmt.Println("Failed to enable foreign key checks:", err)
-	}
-	return nil
 }
 
 func TestMain(m *testing.M) {
@@ -149,51 +140,57 @@ func TestMain(m *testing.M) {
 		Prefix:   "test",
 		LogLevel: logging.DEBUG,
 	})
-	db.Init()
+	store = memstore.New()
+	if err := seedStore(store); err != nil {
+		fmt.Printf("Failed to seed test store: %v\n", err)
+		os.Exit(1)
+	}
+	suggestions := suggest.New()
+	if err := suggestions.Rebuild(context.Background(), store); err != nil {
+		fmt.Printf("Failed to build the product name index: %v\n", err)
+		os.Exit(1)
+	}
+	tracked := suggest.Track(store, suggestions)
+	searches = analytics.NewRecorder(store, 1000, logging.GetLogger())
+	dictionary := synonyms.New(store)
+	matcher = alerts.NewMatcher(store, store, dictionary, notifier, 1000, logging.GetLogger())
+	routes = NewRouter(Dependencies{
+		Products:      alerts.Watch(tracked, matcher),
+		Sellers:       tracked,
+		Categories:    store,
+		Buyers:        store,
+		Carts:         store,
+		Orders:        store,
+		Suggestions:   suggestions,
+		Searches:      searches,
+		SearchLog:     store,
+		SavedSearches: store,
+		Synonyms:      dictionary,
+		AdminToken:    adminToken,
+	})
 	exitCode := m.Run()
 
-	// Clean up
-	err := dropTestDatabase("products", "sellers")
-	if err != nil {
-		fmt.Printf("Failed to drop test database: %v\n", err)
-	}
 	// Exit with the appropriate exit code
 	os.Exit(exitCode)
 }
 
-func setupTestDatabase() error {
-	db, err := sql.Open("mysql", fmt.Sprintf("%s:%s@tcp(%s:%s)/%s", dbUser, dbPassword, dbHostName, dbPort, dbName))
-	if err != nil {
-		return fmt.Errorf("failed to connect to the database: %v", err)
-	}
-	defer db.Close()
-
-	insertSellerQuery := `
-		INSERT INTO sellers (name, location)
-		VALUES (?, ?)
-	`
-	sellers := []struct {
-		Name     string
-		Location string
-	}{
+// seedStore fills the store with sellers and products rotating between them
+func seedStore(store *memstore.Store) error {
+	ctx := context.Background()
+	sellers := []models.Seller{
 		{Name: "Seller A", Location: "IND"},
 		{Name: "Seller B", Location: "US"},
 		{Name: "Seller C", Location: "IND"},
 		{Name: "Seller D", Location: "UK"},
 		{Name: "Seller E", Location: "US"},
 	}
-	for _, seller := range sellers {
-		_, err = db.Exec(insertSellerQuery, seller.Name, seller.Location)
-		if err != nil {
+	for i := range sellers {
+		if err := store.CreateSeller(ctx, &sellers[i]); err != nil {
 			return fmt.Errorf("failed to insert seller: %v", err)
 		}
 	}
 
 	// Insert products with different sellers
-	insertProductQuery := `
-		INSERT INTO products (seller_id, product_name, price, quantity)
-		VALUES (?, ?, ?, ?)
-	`
 	productNames := []string{
 		"Smartphone", "Laptop", "Tablet", "Smartwatch", "Headphones",
 		"Wireless Earbuds", "Gaming Console", "VR Headset", "Fitness Tracker", "Bluetooth Speaker",
@@ -201,15 +198,268 @@ func setupTestDatabase() error {
 		"Smart Home Hub", "Smart TV", "Wireless Router", "Wireless Mouse", "E-book Reader",
 	}
 	for i := 0; i < 30; i++ {
-		sellerID := (i % 5) + 1 // Rotate between seller IDs 1 to 5
-		productName := productNames[i%len(productNames)]
-		price := float64((i + 1) * 10)
-		quantity := i + 1
-
-		_, err = db.Exec(insertProductQuery, sellerID, productName, price, quantity)
-		if err != nil {
+		product := models.Product{
+			SellerID:    (i % 5) + 1, // Rotate between seller IDs 1 to 5
+			ProductName: productNames[i%len(productNames)],
+			Price:       float64((i + 1) * 10),
+			Quantity:    i + 1,
+		}
+		if err := store.CreateProduct(ctx, &product); err != nil {
 			return fmt.Errorf("failed to insert product: %v", err)
 		}
 	}
 	return nil
 }
+
+// createTestProduct adds a product to the shared store for tests changing it
+func createTestProduct(t *testing.T) models.Product {
+	t.Helper()
+	return createTestProductNamed(t, "Test Product", 15)
+}
+
+// createTestProductNamed adds a product with the name and price to the shared store
+func createTestProductNamed(t *testing.T, name string, price float64) models.Product {
+	t.Helper()
+	product := models.Product{SellerID: 1, ProductName: name, Price: price, Quantity: 2}
+	if err := store.CreateProduct(context.Background(), &product); err != nil {
+		t.Fatalf("Failed to create product: %v", err)
+	}
+	return product
+}
+
+func serve(method, target string, body []byte) *httptest.ResponseRecorder {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	recorder := httptest.NewRecorder()
+	routes.ServeHTTP(recorder, httptest.NewRequest(method, target, reader))
+	return recorder
+}
+
+// decodeData unmarshals the data of the response envelope into v
+func decodeData(t *testing.T, recorder *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	envelope := struct {
+		Data interface{} `json:"data"`
+	}{Data: v}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", err)
+	}
+}
+
+// decodeError unmarshals the error of the response envelope
+func decodeError(t *testing.T, recorder *httptest.ResponseRecorder) response.Error {
+	t.Helper()
+	var envelope struct {
+		Error response.Error `json:"error"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &envelope); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", err)
+	}
+	return envelope.Error
+}
+
+func TestGetProduct(t *testing.T) {
+	product := createTestProduct(t)
+	recorder := serve(http.MethodGet, fmt.Sprintf("/api/v1/product/%d", product.ID), nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d", recorder.Code)
+	}
+	var got models.Product
+	decodeData(t, recorder, &got)
+	if !reflect.DeepEqual(got, product) {
+		t.Errorf("Expected %+v, got %+v", product, got)
+	}
+}
+
+func TestGetProduct_Errors(t *testing.T) {
+	tests := []struct {
+		target string
+		status int
+		code   string
+	}{
+		{"/api/v1/product/99999", http.StatusNotFound, "product_not_found"},
+		{"/api/v1/product/abc", http.StatusBadRequest, response.CodeInvalidID},
+		{"/api/v1/nothing", http.StatusNotFound, response.CodeNotFound},
+	}
+	for _, tt := range tests {
+		recorder := serve(http.MethodGet, tt.target, nil)
+		if recorder.Code != tt.status {
+			t.Errorf("%s: expected status %d, got %d", tt.target, tt.status, recorder.Code)
+		}
+		if got := recorder.Header().Get("Content-Type"); got != "application/json" {
+			t.Errorf("%s: expected a JSON error, got %q", tt.target, got)
+		}
+		if apiErr := decodeError(t, recorder); apiErr.Code != tt.code || apiErr.Message == "" {
+			t.Errorf("%s: expected error code %s, got %+v", tt.target, tt.code, apiErr)
+		}
+	}
+}
+
+func TestGetProduct_ErrorRequestID(t *testing.T) {
+	// served as in main, the request timeout wraps the handlers in http.TimeoutHandler
+	h := middleware.Chain(routes, middleware.Defaults(logging.GetLogger(), 15*time.Second)...)
+	for _, id := range []string{"client-id", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/product/99999", nil)
+		if id != "" {
+			req.Header.Set(middleware.RequestIDHeader, id)
+		}
+		recorder := httptest.NewRecorder()
+		h.ServeHTTP(recorder, req)
+		if recorder.Code != http.StatusNotFound {
+			t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+		}
+		header := recorder.Header().Get(middleware.RequestIDHeader)
+		if apiErr := decodeError(t, recorder); apiErr.RequestID == "" || apiErr.RequestID != header || id != "" && header != id {
+			t.Errorf("Expected the request id %q of the header in the error, got %+v", header, apiErr)
+		}
+	}
+}
+
+func TestUpdateProduct(t *testing.T) {
+	product := createTestProduct(t)
+	target := fmt.Sprintf("/api/v1/product/%d", product.ID)
+
+	recorder := serve(http.MethodPatch, target, []byte(`{"price": 12.5}`))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	got, _ := store.GetProductByID(context.Background(), product.ID)
+	if got.Price != 12.5 || got.Quantity != product.Quantity || got.ProductName != product.ProductName {
+		t.Errorf("Expected only the price to change, got %+v", got)
+	}
+
+	recorder = serve(http.MethodPut, target, []byte(`{"productName": "Restocked", "price": 11, "quantity": 40}`))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	got, _ = store.GetProductByID(context.Background(), product.ID)
+	if got.ProductName != "Restocked" || got.Price != 11 || got.Quantity != 40 {
+		t.Errorf("Unexpected product after PUT: %+v", got)
+	}
+}
+
+func TestUpdateProduct_Errors(t *testing.T) {
+	product := createTestProduct(t)
+	target := fmt.Sprintf("/api/v1/product/%d", product.ID)
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"PUT needs every field", http.MethodPut, target, `{"price": 10}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPatch, target, `{"sellerId": 2}`, http.StatusBadRequest},
+		{"nothing to update", http.MethodPatch, target, `{}`, http.StatusBadRequest},
+		{"negative price", http.MethodPatch, target, `{"price": -1}`, http.StatusBadRequest},
+		{"negative quantity", http.MethodPatch, target, `{"quantity": -1}`, http.StatusBadRequest},
+		{"empty name", http.MethodPatch, target, `{"productName": " "}`, http.StatusBadRequest},
+		{"invalid body", http.MethodPatch, target, `{"price": `, http.StatusBadRequest},
+		{"unknown product", http.MethodPatch, "/api/v1/product/99999", `{"price": 10}`, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := serve(tt.method, tt.target, []byte(tt.body)).Code; code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, code)
+			}
+		})
+	}
+}
+
+func TestDeleteProduct(t *testing.T) {
+	product := createTestProduct(t)
+	target := fmt.Sprintf("/api/v1/product/%d", product.ID)
+	if code := serve(http.MethodDelete, target, nil).Code; code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+	if code := serve(http.MethodGet, target, nil).Code; code != http.StatusNotFound {
+		t.Errorf("Expected the product to be gone, got %d", code)
+	}
+	if code := serve(http.MethodDelete, target, nil).Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 deleting twice, got %d", code)
+	}
+}
+
+// errorFields lists the fields of the details of an error response
+func errorFields(t *testing.T, recorder *httptest.ResponseRecorder) []string {
+	t.Helper()
+	var fields []string
+	for _, d := range decodeError(t, recorder).Details {
+		fields = append(fields, d.Field)
+	}
+	return fields
+}
+
+func TestCreateProduct_Validation(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		code   string
+		fields []string
+	}{
+		{
+			"every violation at once",
+			`{"sellerId": 0, "productName": " ", "price": -1, "quantity": 0}`,
+			response.CodeValidationFailed,
+			[]string{"sellerId", "productName", "price", "quantity"},
+		},
+		{
+			"name too long and price out of range",
+			`{"sellerId": 1, "productName": "` + strings.Repeat("a", 10000) + `", "price": 100000000, "quantity": 1}`,
+			response.CodeValidationFailed,
+			[]string{"productName", "price"},
+		},
+		{
+			"unknown field",
+			`{"sellerId": 1, "productName": "Pen", "price": 1, "quantity": 1, "qty": 2}`,
+			response.CodeInvalidBody,
+			[]string{"qty"},
+		},
+		{
+			"seller set by the searches",
+			`{"sellerId": 1, "productName": "Pen", "price": 1, "quantity": 1, "seller": {"name": "FAKE"}}`,
+			response.CodeInvalidBody,
+			[]string{"seller"},
+		},
+		{
+			"distance set by the searches",
+			`{"sellerId": 1, "productName": "Pen", "price": 1, "quantity": 1, "distanceKm": 3}`,
+			response.CodeInvalidBody,
+			[]string{"distanceKm"},
+		},
+		{
+			"score set by the searches",
+			`{"sellerId": 1, "productName": "Pen", "price": 1, "quantity": 1, "score": 9}`,
+			response.CodeInvalidBody,
+			[]string{"score"},
+		},
+		{
+			"wrong type",
+			`{"sellerId": 1, "productName": "Pen", "price": "cheap", "quantity": 1}`,
+			response.CodeInvalidBody,
+			[]string{"price"},
+		},
+		{
+			"trailing data",
+			`{"sellerId": 1, "productName": "Pen", "price": 1, "quantity": 1} {}`,
+			response.CodeInvalidBody,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(http.MethodPost, "/api/v1/product", []byte(tt.body))
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("Expected 400, got %d", recorder.Code)
+			}
+			if code := decodeError(t, recorder).Code; code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, code)
+			}
+			if fields := errorFields(t, recorder); !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Expected errors on %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+}
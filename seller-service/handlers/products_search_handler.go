@@ -1,28 +1,53 @@
 package handlers
 
 import (
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
 	"net/http"
-	"strconv"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/analytics"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/suggest"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/synonyms"
 )
 
 // SearchProducts will search for products matching this API
-// supports based-on-input GET call
+// It is mounted on GET /api/v1/product/search, parameters that cannot be parsed or are out
+// of range are answered with a 400 listing all of them
 //
 // Query Parameters: below are list of available query params
 //
+//	`q` (optional): Full-text query on the name and description of products: words, prefixes such as `wire*` and phrases such as `"wireless mouse"`, all of them must match
 //	`productName` (optional): Product name for filtering products
 //	`desiredQty` (optional): Desired quantity for filtering products
-//	`location` (optional): Location for filtering products
+//	`location` (optional): Location of the sellers, matched exactly without regard to case
+//	`country` (optional): Comma separated ISO 3166-1 alpha-2 codes of the countries of the sellers, e.g. IN,US
+//	`near` (optional): Latitude and longitude separated by a comma, keeps the products of the sellers with coordinates and adds their `distanceKm`
+//	`radiusKm` (optional): With `near`, the largest distance of the sellers in km
 //	`minPrice` (optional): Minimum price for filtering products
 //	`maxPrice` (optional): Maximum price for filtering products
-//	`sortBy` (optional): Field to sort the products (available:  "price", "productName", "sellerId", "productId")
+//	`category` (optional): Id of a category, products of the category and of its descendants match
+//	`attr.<name>` (optional): Condition on an attribute, e.g. `attr.color=red`, `attr.color!=red` or `attr.ram>=16`, see models.ParseAttributeFilter
+//	`tags` (optional): Comma separated tags the products must all carry
+//	`sortBy` (optional): Comma separated fields to sort the products on, each prefixed with - for descending order (available:  "price", "productName", "sellerId", "productId", "sellerName", "location", "relevance" with `q`, "distance" with `near`)
 //	`page` (optional): Page number for pagination
-//	`perPage` (optional): Number of products per page
+//	`perPage` (optional): Number of products per page, at most 100
+//	`cursor` (optional): The nextCursor or prevCursor of a page, to read the page after or before it
+//	`withTotal` (optional): `true` to count the products matching the filters
+//	`facets` (optional): Comma separated facets to count the matching products by (available: "location", "priceRange", "sellerId"),
+//	the counts of a facet ignore its own filter so they show what choosing another value would return
 //
-// Returns:
+// Returns the page of products, with the cursors of the pages around it in the meta and in a
+// Link header. Cursors hold the position of a product in the sort order, unlike pages they do
+// not shift when products are added or removed. Every search answered is recorded by searches
+// for the search analytics
 //
-//	[ {
+// The product name and the words and phrases of q also match their synonyms in the dictionary,
+// e.g. earphones finds Wireless Earbuds. When the first page has fewer than 3 products the meta
+// holds a `didYouMean` text, q or else productName with its misspelled words replaced by the
+// closest words of the product names in the vocabulary, e.g. smartphone for smartfone
+//
+//	{"data": [ {
 //		  "id": 1,
 //		  "sellerId": 1,
 //		  "productName": "Product Name",
@@ -36,47 +61,99 @@ import (
 //		  "price": 20.0,
 //		  "quantity": 3
 //		},
-//		... ]
-func SearchProducts(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
-		return
-	}
-	productName := r.URL.Query().Get("productName")
-	desiredQty := r.URL.Query().Get("desiredQty")
-	location := r.URL.Query().Get("location")
-	minPrice := r.URL.Query().Get("minPrice")
-	maxPrice := r.URL.Query().Get("maxPrice")
-	sortBy := r.URL.Query().Get("sortBy")
-	page := r.URL.Query().Get("page")
-	perPage := r.URL.Query().Get("perPage")
-	desiredQuantity, err := strconv.Atoi(desiredQty)
-	if err != nil {
-		desiredQuantity = 1
-	}
-	page1, err := strconv.ParseUint(page, 10, 64)
-	if err != nil {
-		page1 = 1
-	}
-	perPage1, err := strconv.ParseUint(perPage, 10, 64)
-	if err != nil {
-		perPage1 = 10
-	}
-	minimumPrice, err := strconv.ParseFloat(minPrice, 64)
-	if err != nil {
-		minimumPrice = 0
-	}
-	maximumPrice, err := strconv.ParseFloat(maxPrice, 64)
-	if err != nil {
-		maximumPrice = 0
+//		... ],
+//	 "meta": {"perPage": 10, "nextCursor": "eyJzIjoicHJpY2UiLCJwIjoyMCwiaWQiOjJ9", "total": 42}}
+func SearchProducts(products models.ProductStore, searches *analytics.Recorder, vocabulary *suggest.Index, dictionary *synonyms.Dictionary) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		query := newQueryParams(r)
+		page, perPage := query.Pagination()
+		var productRequest = models.NewProductRequest(
+			query.String("productName", ""),
+			query.Int("desiredQty", 1),
+			query.String("location", ""),
+			query.Float("minPrice", 0),
+			query.Float("maxPrice", 0),
+			query.String("sortBy", ""),
+			page,
+			perPage,
+		)
+		productRequest.CategoryID = query.Int("category", 0)
+		productRequest.Attributes = query.Attributes()
+		productRequest.Tags = query.List("tags")
+		productRequest.Countries = query.List("country")
+		productRequest.Near = query.GeoPoint("near")
+		productRequest.RadiusKm = query.Float("radiusKm", 0)
+		productRequest.Query = query.String("q", "")
+		productRequest.Facets = query.List("facets")
+		productRequest.Cursor = query.Cursor()
+		productRequest.Synonyms = dictionary.Synonyms()
+		withTotal := query.Bool("withTotal", false)
+
+		err := query.Validate(productRequest.Validate)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		if results, ok := writeProductPage(w, r, products, productRequest, withTotal, vocabulary); ok {
+			searches.Record(models.NewSearchEvent(productRequest, results, start, time.Since(start)))
+		}
 	}
-	var productRequest = models.NewProductRequest(productName, desiredQuantity, location, minimumPrice, maximumPrice, sortBy, page1, perPage1)
+}
+
+// searchBody is the body of a POST search, the filters of a GET search along with a filter tree
+type searchBody struct {
+	models.ProductRequest
+	// Cursor is the nextCursor or prevCursor of a page, to read the page after or before it
+	Cursor string `json:"cursor"`
+	// WithTotal adds the number of products matching the filters to the meta
+	WithTotal bool `json:"withTotal"`
+}
+
+// FilterProducts searches for products matching a JSON filter tree
+// It is mounted on POST /api/v1/product/search and accepts the parameters of the GET search
+// as fields of the body, along with a `filter` tree combining conditions with and, or and not
+//
+//	{
+//	  "filter": {"or": [
+//	    {"and": [{"eq": {"field": "location", "value": "IND"}}, {"range": {"field": "price", "gte": 10, "lt": 50}}]},
+//	    {"contains": {"field": "productName", "value": "smart"}}
+//	  ]},
+//	  "sortBy": "-price",
+//	  "perPage": 20
+//	}
+//
+// The tree is limited to models.MaxFilterNodes nodes nested models.MaxFilterDepth levels deep,
+// invalid nodes are answered with a 400 naming their path in the tree, e.g. filter.or[0].eq.value.
+// Returns the page of products like the GET search, without the Link header, and is recorded
+// for the search analytics too. Synonyms and didYouMean apply as for the GET search
+func FilterProducts(products models.ProductStore, searches *analytics.Recorder, vocabulary *suggest.Index, dictionary *synonyms.Dictionary) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		body := searchBody{ProductRequest: models.ProductRequest{Page: 1, PerPage: 10}}
+		if err := decodeJSON(r, &body); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		productRequest := &body.ProductRequest
+
+		if body.Cursor != "" {
+			cursor, err := models.DecodeCursor(body.Cursor)
+			if err != nil {
+				writeError(w, r, validation.Errors{{Field: "cursor", Message: "is not a cursor returned by the API"}})
+				return
+			}
+			productRequest.Cursor = &cursor
+		}
+		if err := productRequest.Validate(); err != nil {
+			writeError(w, r, err)
+			return
+		}
+		productRequest.Synonyms = dictionary.Synonyms()
 
-	resp, err := productRequest.SearchProducts()
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
+		if results, ok := writeProductPage(w, r, products, productRequest, body.WithTotal, vocabulary); ok {
+			searches.Record(models.NewSearchEvent(productRequest, results, start, time.Since(start)))
+		}
 	}
-	w.Header().Set("Content-Type", "application/json")
-	_, _ = w.Write(resp)
 }
@@ -2,21 +2,28 @@ package handlers
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
 )
 
 func TestSearchProductsHandler(t *testing.T) {
-	req, err := http.NewRequest(http.MethodGet, "/search/products?productName=SmartPhone&desiredQty=1&location=IND&minPrice=10&maxPrice=100&sortBy=price&page=1&perPage=10", nil)
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/product/search?productName=SmartPhone&desiredQty=1&location=IND&minPrice=10&maxPrice=100&sortBy=price&page=1&perPage=10", nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	recorder := httptest.NewRecorder()
 
-	SearchProducts(recorder, req)
+	routes.ServeHTTP(recorder, req)
 	if recorder.Code != http.StatusOK {
 		t.Errorf("Expected status code %d, got %d", http.StatusOK, recorder.Code)
 	}
@@ -26,8 +33,320 @@ func TestSearchProductsHandler(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	expectedResp := []byte(`[{"ID":1,"sellerId":1,"productName":"Smartphone","price":10,"quantity":1}]`)
-	if !bytes.Equal(respBody, expectedResp) {
+	expectedResp := []byte(`{"data":[{"id":1,"sellerId":1,"productName":"Smartphone","price":10,"quantity":1,"description":"","categoryId":0,"seller":{"id":1,"name":"Seller A","location":"IND","address":{"country":"IN"}}}],"meta":{"perPage":10}}`)
+	if !bytes.Equal(bytes.TrimSpace(respBody), expectedResp) {
 		t.Errorf("Unexpected response body. Expected: %s, Got: %s", expectedResp, respBody)
 	}
 }
+
+func TestSearchProductsHandler_InvalidParameters(t *testing.T) {
+	tests := []struct {
+		query  string
+		fields []string
+	}{
+		{"page=abc&minPrice=cheap&desiredQty=1.5", []string{"page", "desiredQty", "minPrice"}},
+		{"perPage=1000&sortBy=rating&page=0", []string{"page", "perPage", "sortBy"}},
+		{"minPrice=50&maxPrice=10", []string{"maxPrice"}},
+		{"minPrice=NaN&maxPrice=Inf", []string{"minPrice", "maxPrice"}},
+		{"minPrice=-1&productName=" + strings.Repeat("a", 300), []string{"productName", "minPrice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			recorder := serve(http.MethodGet, "/api/v1/product/search?"+tt.query, nil)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("Expected 400, got %d", recorder.Code)
+			}
+			if fields := errorFields(t, recorder); !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Expected errors on %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+
+	if code := serve(http.MethodGet, "/api/v1/product/search?minPrice=500", nil).Code; code != http.StatusOK {
+		t.Errorf("Expected a minimum price without maximum to be accepted, got %d", code)
+	}
+}
+
+func TestSearchProductsHandler_SortOrder(t *testing.T) {
+	recorder := serve(http.MethodGet, "/api/v1/product/search?sortBy=-location,-price&perPage=100", nil)
+	var products []models.Product
+	decodeData(t, recorder, &products)
+	for i := 1; i < len(products); i++ {
+		prev, p := products[i-1], products[i]
+		if p.Seller.Location > prev.Seller.Location || p.Seller.Location == prev.Seller.Location && p.Price > prev.Price {
+			t.Fatalf("Expected products sorted by location then price descending, got %+v after %+v", p, prev)
+		}
+	}
+
+	recorder = serve(http.MethodGet, "/api/v1/product/search?sortBy=price,rating", nil)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for an unknown sort field, got %d", recorder.Code)
+	}
+	details := decodeError(t, recorder).Details
+	if len(details) != 1 || details[0].Field != "sortBy" || !strings.Contains(details[0].Message, "price, productName, sellerId, productId, sellerName, location, relevance") {
+		t.Errorf("Expected the error to list the sort fields, got %+v", details)
+	}
+}
+
+// searchPage is a page of the search response with its pagination
+type searchPage struct {
+	Data []models.Product `json:"data"`
+	Meta struct {
+		PerPage    uint64 `json:"perPage"`
+		NextCursor string `json:"nextCursor"`
+		PrevCursor string `json:"prevCursor"`
+		Total      *int   `json:"total"`
+		DidYouMean string `json:"didYouMean"`
+	} `json:"meta"`
+}
+
+func TestSearchProductsHandler_Cursor(t *testing.T) {
+	search := func(query string) (searchPage, *httptest.ResponseRecorder) {
+		t.Helper()
+		recorder := serve(http.MethodGet, "/api/v1/product/search?productName=smart&sortBy=price&perPage=4&"+query, nil)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+		}
+		var page searchPage
+		if err := json.Unmarshal(recorder.Body.Bytes(), &page); err != nil {
+			t.Fatalf("Failed to unmarshal response body: %v", err)
+		}
+		return page, recorder
+	}
+
+	first, recorder := search("withTotal=true")
+	if len(first.Data) != 4 || first.Meta.NextCursor == "" || first.Meta.PrevCursor != "" {
+		t.Fatalf("Expected a first page of 4 with only a next cursor, got %+v", first)
+	}
+	if first.Meta.Total == nil || *first.Meta.Total != 6 {
+		t.Errorf("Expected a total of 6, got %v", first.Meta.Total)
+	}
+	link := recorder.Header().Get("Link")
+	if !strings.Contains(link, "?cursor="+first.Meta.NextCursor+"&perPage=4&productName=smart&sortBy=price&withTotal=true>; rel=\"next\"") || strings.Contains(link, `rel="prev"`) {
+		t.Errorf("Unexpected Link header %q", link)
+	}
+
+	second, recorder := search("cursor=" + first.Meta.NextCursor)
+	if len(second.Data) != 2 || second.Meta.NextCursor != "" || second.Meta.PrevCursor == "" || second.Meta.Total != nil {
+		t.Fatalf("Expected a last page of 2 with only a prev cursor, got %+v", second)
+	}
+	if second.Data[0].Price < first.Data[3].Price {
+		t.Errorf("Expected the second page to continue the sort order, got %+v after %+v", second.Data, first.Data)
+	}
+	if link := recorder.Header().Get("Link"); !strings.Contains(link, `rel="prev"`) || strings.Contains(link, `rel="next"`) {
+		t.Errorf("Unexpected Link header %q", link)
+	}
+
+	// a product added before the cursor does not shift the page after it
+	createTestProductNamed(t, "Smart Plug", 1)
+	again, _ := search("cursor=" + first.Meta.NextCursor)
+	if !reflect.DeepEqual(again.Data, second.Data) {
+		t.Errorf("Expected the same page after an insert, got %+v, want %+v", again.Data, second.Data)
+	}
+
+	back, _ := search("cursor=" + second.Meta.PrevCursor)
+	if len(back.Data) != 4 || back.Data[3].ID != first.Data[3].ID || back.Meta.NextCursor == "" || back.Meta.PrevCursor == "" {
+		t.Errorf("Expected the previous page to end with %+v and have both cursors, got %+v", first.Data[3], back)
+	}
+
+	recorder = serve(http.MethodGet, "/api/v1/product/search?sortBy=productName&cursor="+first.Meta.NextCursor, nil)
+	if fields := errorFields(t, recorder); recorder.Code != http.StatusBadRequest || !reflect.DeepEqual(fields, []string{"cursor"}) {
+		t.Errorf("Expected 400 for a cursor of another sort, got %d %v", recorder.Code, fields)
+	}
+	if code := serve(http.MethodGet, "/api/v1/product/search?cursor=garbage", nil).Code; code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an invalid cursor, got %d", code)
+	}
+}
+
+func TestSearchProductsHandler_FullText(t *testing.T) {
+	for _, name := range []string{"Speaker, Bluetooth Portable", "Bluetooth Headset"} {
+		createTestProductNamed(t, name, 40)
+	}
+
+	var page searchPage
+	recorder := serve(http.MethodGet, "/api/v1/product/search?q=portable+bluetooth+speak*&sortBy=relevance&perPage=1", nil)
+	if err := json.Unmarshal(recorder.Body.Bytes(), &page); err != nil || recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response %d %s", recorder.Code, recorder.Body)
+	}
+	if len(page.Data) != 1 || page.Data[0].ProductName != "Speaker, Bluetooth Portable" || page.Data[0].Score <= 0 {
+		t.Errorf("Expected the portable speaker with its score, got %+v", page.Data)
+	}
+
+	recorder = serve(http.MethodGet, "/api/v1/product/search?sortBy=relevance&perPage=100&q=bluetooth", nil)
+	if err := json.Unmarshal(recorder.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	for i, p := range page.Data {
+		if i > 0 && p.Score > page.Data[i-1].Score {
+			t.Errorf("Expected the most relevant products first, got %+v", page.Data)
+		}
+	}
+	if len(page.Data) < 3 {
+		t.Errorf("Expected every bluetooth product, got %+v", page.Data)
+	}
+
+	tests := []struct {
+		query  string
+		fields []string
+	}{
+		{"q=a+of", []string{"q"}},
+		{"sortBy=relevance", []string{"sortBy"}},
+	}
+	for _, tt := range tests {
+		recorder := serve(http.MethodGet, "/api/v1/product/search?"+tt.query, nil)
+		if fields := errorFields(t, recorder); recorder.Code != http.StatusBadRequest || !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%s: expected 400 on %v, got %d %v", tt.query, tt.fields, recorder.Code, fields)
+		}
+	}
+}
+
+func TestSearchProductsHandler_Facets(t *testing.T) {
+	recorder := serve(http.MethodGet, "/api/v1/product/search?location=US&maxPrice=100&facets=location,priceRange,sellerId", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var page struct {
+		Meta struct {
+			Facets models.Facets `json:"facets"`
+		} `json:"meta"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &page); err != nil {
+		t.Fatal(err)
+	}
+	facets := page.Meta.Facets
+
+	// sellers 2 and 5 are in the US, products of US sellers cost up to 300
+	if got := facets[models.FacetLocation]; len(got) != 3 || got[0].Value != "IND" || got[0].Count < 4 {
+		t.Errorf("Expected the location counts to ignore the location filter, got %+v", got)
+	}
+	for _, b := range facets[models.FacetSellerID] {
+		if b.Value != "2" && b.Value != "5" || b.Label == "" {
+			t.Errorf("Expected only the labelled US sellers, got %+v", b)
+		}
+	}
+	bands := facets[models.FacetPriceRange]
+	if len(bands) != len(models.PriceRanges) || bands[0].Value != "0-10" || bands[len(bands)-1].Value != "500+" || bands[3].Count == 0 {
+		t.Errorf("Expected the price ranges to ignore the price filter, got %+v", bands)
+	}
+
+	recorder = serve(http.MethodGet, "/api/v1/product/search?facets=color", nil)
+	if fields := errorFields(t, recorder); recorder.Code != http.StatusBadRequest || !reflect.DeepEqual(fields, []string{"facets"}) {
+		t.Errorf("Expected 400 for an unknown facet, got %d %v", recorder.Code, fields)
+	}
+}
+
+func TestFilterProductsHandler(t *testing.T) {
+	body := `{
+		"filter": {"and": [
+			{"or": [{"eq": {"field": "location", "value": "ind"}}, {"contains": {"field": "sellerName", "value": "seller d"}}]},
+			{"range": {"field": "price", "gte": 50, "lt": 200}},
+			{"not": {"in": {"field": "sellerId", "values": [1]}}}
+		]},
+		"sortBy": "-price",
+		"perPage": 100,
+		"withTotal": true
+	}`
+	recorder := serve(http.MethodPost, "/api/v1/product/search", []byte(body))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", recorder.Code, recorder.Body)
+	}
+	var page searchPage
+	decodeData(t, recorder, &page.Data)
+	if len(page.Data) == 0 {
+		t.Fatal("Expected products matching the filter")
+	}
+	for i, p := range page.Data {
+		if p.Seller.Location != "IND" && p.Seller.Name != "Seller D" || p.Price < 50 || p.Price >= 200 || p.SellerID == 1 {
+			t.Errorf("Product %+v does not match the filter", p)
+		}
+		if i > 0 && p.Price > page.Data[i-1].Price {
+			t.Errorf("Expected products sorted by price descending, got %+v after %+v", p, page.Data[i-1])
+		}
+	}
+	if recorder.Header().Get("Link") != "" {
+		t.Errorf("Expected no Link header on a POST search, got %q", recorder.Header().Get("Link"))
+	}
+}
+
+func TestFilterProductsHandler_Invalid(t *testing.T) {
+	deep := `{"eq": {"field": "price", "value": 1}}`
+	for i := 0; i < models.MaxFilterDepth; i++ {
+		deep = `{"not": ` + deep + `}`
+	}
+	tests := []struct {
+		name   string
+		body   string
+		code   string
+		fields []string
+	}{
+		{"unknown field", `{"filter": {"eq": {"field": "rating", "value": 1}}}`, response.CodeValidationFailed, []string{"filter.eq.field"}},
+		{"wrong value type", `{"filter": {"or": [{"eq": {"field": "price", "value": "cheap"}}, {"in": {"field": "sellerId", "values": [1.5]}}]}}`,
+			response.CodeValidationFailed, []string{"filter.or[0].eq.value", "filter.or[1].in.values[0]"}},
+		{"two operators", `{"filter": {"eq": {"field": "price", "value": 1}, "not": {"eq": {"field": "price", "value": 2}}}}`, response.CodeValidationFailed, []string{"filter"}},
+		{"range on text", `{"filter": {"range": {"field": "location", "gt": 1}}}`, response.CodeValidationFailed, []string{"filter.range.field"}},
+		{"too deep", `{"filter": ` + deep + `}`, response.CodeValidationFailed, []string{"filter.not.not.not.not"}},
+		{"unknown operator", `{"filter": {"like": {"field": "location", "value": "IND"}}}`, response.CodeInvalidBody, []string{"like"}},
+		{"bad cursor", `{"cursor": "nope"}`, response.CodeValidationFailed, []string{"cursor"}},
+		{"bad sort", `{"sortBy": "rating", "perPage": 1000}`, response.CodeValidationFailed, []string{"perPage", "sortBy"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(http.MethodPost, "/api/v1/product/search", []byte(tt.body))
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("Expected 400, got %d", recorder.Code)
+			}
+			if code := decodeError(t, recorder).Code; code != tt.code {
+				t.Errorf("Expected code %s, got %s", tt.code, code)
+			}
+			if fields := errorFields(t, recorder); !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Expected errors on %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+}
+
+func TestSearchProductsHandler_Near(t *testing.T) {
+	for _, s := range []struct {
+		city     string
+		lat, lng float64
+	}{{"Bengaluru", 12.9716, 77.5946}, {"Mysuru", 12.2958, 76.6394}, {"Chennai", 13.0827, 80.2707}} {
+		seller := createGeoSeller(t, s.city, s.lat, s.lng)
+		product := models.Product{SellerID: seller.ID, ProductName: "Geo Lamp " + s.city, Price: 20, Quantity: 1}
+		if err := store.CreateProduct(context.Background(), &product); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+
+	var products []models.Product
+	decodeData(t, serve(http.MethodGet, "/api/v1/product/search?productName=geo+lamp&near=12.9716,77.5946&radiusKm=200&sortBy=distance", nil), &products)
+	if len(products) != 2 || products[0].ProductName != "Geo Lamp Bengaluru" || products[1].ProductName != "Geo Lamp Mysuru" {
+		t.Fatalf("Expected the products within 200km closest first, got %+v", products)
+	}
+	if d := products[1].Distance; d == nil || *d < 120 || *d > 135 || *products[0].Distance != 0 {
+		t.Errorf("Expected Mysuru about 128km away, got %v", d)
+	}
+
+	decodeData(t, serve(http.MethodGet, "/api/v1/product/search?productName=geo+lamp&near=13.0827,80.2707&sortBy=-distance&country=IN", nil), &products)
+	if len(products) != 3 || products[0].ProductName != "Geo Lamp Mysuru" {
+		t.Errorf("Expected every product, farthest first, got %+v", products)
+	}
+
+	tests := []struct {
+		query  string
+		fields []string
+	}{
+		{"near=north", []string{"near"}},
+		{"near=95,10", []string{"near"}},
+		{"radiusKm=10", []string{"radiusKm"}},
+		{"near=1,1&radiusKm=-1", []string{"radiusKm"}},
+		{"near=1,1&radiusKm=nan", []string{"radiusKm"}},
+		{"sortBy=distance", []string{"sortBy"}},
+		{"country=IN,XX", []string{"country"}},
+	}
+	for _, tt := range tests {
+		recorder := serve(http.MethodGet, "/api/v1/product/search?"+tt.query, nil)
+		if fields := errorFields(t, recorder); recorder.Code != http.StatusBadRequest || !reflect.DeepEqual(fields, tt.fields) {
+			t.Errorf("%s: expected 400 on %v, got %d %v", tt.query, tt.fields, recorder.Code, fields)
+		}
+	}
+}
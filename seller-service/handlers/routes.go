@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/analytics"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/middleware"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/router"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/suggest"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/synonyms"
+)
+
+// Dependencies are the stores the handlers are built from
+type Dependencies struct {
+	Products   models.ProductStore
+	Sellers    models.SellerStore
+	Categories models.CategoryStore
+	Buyers     models.BuyerStore
+	Carts      models.CartStore
+	Orders     models.OrderStore
+	// Suggestions is the index of the product names, kept up to date by Products
+	Suggestions *suggest.Index
+	// Searches records the product searches into SearchLog, which the admin reports read
+	Searches  *analytics.Recorder
+	SearchLog models.SearchLogStore
+	// SavedSearches are the saved searches of the buyers, Products alerts them of the products
+	// newly matching them
+	SavedSearches models.SavedSearchStore
+	// Synonyms is the synonym dictionary applied to the searches, managed by the admin endpoints
+	Synonyms *synonyms.Dictionary
+	// AdminToken is the bearer token of the admin endpoints, they are disabled when it is empty
+	AdminToken string
+}
+
+// NewRouter wires every route of the API, it is used by main and by the tests
+func NewRouter(deps Dependencies) *router.Router {
+	r := router.New()
+	r.NotFound = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.Err(w, r, response.NewError(http.StatusNotFound, response.CodeNotFound, "Not found"))
+	})
+	r.MethodNotAllowed = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response.Err(w, r, response.NewError(http.StatusMethodNotAllowed, response.CodeMethodNotAllowed, "Method not allowed"))
+	})
+
+	v1 := r.Group("/api/v1")
+	v1.Post("/product", CreateProduct(deps.Products, deps.Sellers, deps.Categories))
+	v1.Get("/product/search", SearchProducts(deps.Products, deps.Searches, deps.Suggestions, deps.Synonyms))
+	v1.Post("/product/search", FilterProducts(deps.Products, deps.Searches, deps.Suggestions, deps.Synonyms))
+	v1.Get("/product/suggest", SuggestProducts(deps.Suggestions))
+	v1.Get("/product/{id}", GetProduct(deps.Products))
+	v1.Get("/attributes", ListAttributes(deps.Products))
+	v1.Put("/product/{id}", UpdateProduct(deps.Products, false))
+	v1.Patch("/product/{id}", UpdateProduct(deps.Products, true))
+	v1.Delete("/product/{id}", DeleteProduct(deps.Products))
+	v1.Post("/seller", CreateSeller(deps.Sellers))
+	v1.Get("/sellers", ListSellers(deps.Sellers))
+	v1.Get("/seller/{id}", GetSeller(deps.Sellers))
+	v1.Put("/seller/{id}", UpdateSeller(deps.Sellers, false))
+	v1.Patch("/seller/{id}", UpdateSeller(deps.Sellers, true))
+	v1.Delete("/seller/{id}", DeleteSeller(deps.Sellers))
+	v1.Get("/seller/{id}/products", ListSellerProducts(deps.Products, deps.Sellers))
+	v1.Post("/category", CreateCategory(deps.Categories))
+	v1.Get("/categories", GetCategoryTree(deps.Categories))
+	v1.Get("/category/{id}", GetCategory(deps.Categories))
+	v1.Put("/category/{id}", UpdateCategory(deps.Categories, false))
+	v1.Patch("/category/{id}", UpdateCategory(deps.Categories, true))
+	v1.Delete("/category/{id}", DeleteCategory(deps.Categories))
+	v1.Post("/buyer", CreateBuyer(deps.Buyers))
+	v1.Get("/buyer/{id}", GetBuyer(deps.Buyers))
+	v1.Put("/buyer/{id}", UpdateBuyer(deps.Buyers, false))
+	v1.Patch("/buyer/{id}", UpdateBuyer(deps.Buyers, true))
+	v1.Delete("/buyer/{id}", DeleteBuyer(deps.Buyers))
+	v1.Get("/buyer/{id}/addresses", ListBuyerAddresses(deps.Buyers))
+	v1.Post("/buyer/{id}/addresses", CreateBuyerAddress(deps.Buyers))
+	v1.Put("/buyer/{id}/addresses/{addressId}", UpdateBuyerAddress(deps.Buyers))
+	v1.Delete("/buyer/{id}/addresses/{addressId}", DeleteBuyerAddress(deps.Buyers))
+	v1.Get("/buyer/{id}/cart", GetCart(deps.Carts))
+	v1.Delete("/buyer/{id}/cart", ClearCart(deps.Carts))
+	v1.Post("/buyer/{id}/cart/items", AddCartItem(deps.Carts))
+	v1.Put("/buyer/{id}/cart/items/{productId}", UpdateCartItem(deps.Carts))
+	v1.Delete("/buyer/{id}/cart/items/{productId}", DeleteCartItem(deps.Carts))
+	v1.Post("/orders", PlaceOrder(deps.Orders))
+	v1.Get("/order/{id}", GetOrder(deps.Orders))
+	v1.Post("/saved-search", CreateSavedSearch(deps.SavedSearches, deps.Buyers))
+	v1.Get("/saved-searches", ListSavedSearches(deps.SavedSearches))
+	v1.Get("/saved-search/{id}", GetSavedSearch(deps.SavedSearches))
+	v1.Put("/saved-search/{id}", UpdateSavedSearch(deps.SavedSearches))
+	v1.Delete("/saved-search/{id}", DeleteSavedSearch(deps.SavedSearches))
+
+	admin := v1.Group("/admin", middleware.RequireToken(deps.AdminToken))
+	admin.Get("/search/queries", TopSearchQueries(deps.SearchLog, false))
+	admin.Get("/search/zero-results", TopSearchQueries(deps.SearchLog, true))
+	admin.Get("/search/filters", TopSearchFilters(deps.SearchLog))
+	admin.Get("/synonyms", ListSynonymGroups(deps.Synonyms))
+	admin.Post("/synonyms", CreateSynonymGroup(deps.Synonyms))
+	admin.Put("/synonyms/{id}", UpdateSynonymGroup(deps.Synonyms))
+	admin.Delete("/synonyms/{id}", DeleteSynonymGroup(deps.Synonyms))
+
+	return r
+}
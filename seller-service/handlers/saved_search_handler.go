@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// CreateSavedSearch saves a product search of a buyer, who is alerted from then on of the
+// products created or restocked that match it
+//
+// It is mounted on POST /api/v1/saved-search. The buyer must exist and the filters are those
+// of the product search, at least the product name, the location or the price range must be given
+//
+// Input:
+//
+//	{
+//	  "buyerId": 7,
+//	  "name": "Cheap drones",
+//	  "productName": "drone",
+//	  "location": "IND",
+//	  "maxPrice": 150,
+//	  "desiredQty": 2
+//	}
+//
+// Output:
+//
+//	{
+//	  "data": {
+//	    "id": 1,
+//	    "buyerId": 7,
+//	    "name": "Cheap drones",
+//	    "productName": "drone",
+//	    "location": "IND",
+//	    "minPrice": 0,
+//	    "maxPrice": 150,
+//	    "desiredQty": 2,
+//	    "createdAt": "2024-01-31T17:58:02.113Z"
+//	  }
+//	}
+func CreateSavedSearch(searches models.SavedSearchStore, buyers models.BuyerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var search models.SavedSearch
+		err := decodeJSON(r, &search)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		err = search.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		_, err = buyers.GetBuyerByID(r.Context(), search.BuyerID)
+		if err == models.ErrBuyerNotFound {
+			err = invalidBuyer()
+		}
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = searches.CreateSavedSearch(r.Context(), &search)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, search)
+	}
+}
+
+// GetSavedSearch responds with the saved search matching the id of the path
+//
+// It is mounted on GET /api/v1/saved-search/{id}
+func GetSavedSearch(searches models.SavedSearchStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		search, err := searches.GetSavedSearchByID(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, search)
+	}
+}
+
+// ListSavedSearches responds with a page of the saved searches of a buyer ordered by id
+//
+// It is mounted on GET /api/v1/saved-searches
+//
+// Query Parameters:
+//
+//	`buyerId` (required): Id of the buyer
+//	`page` (optional): Page number for pagination
+//	`perPage` (optional): Number of saved searches per page, at most 100
+func ListSavedSearches(searches models.SavedSearchStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := newQueryParams(r)
+		page, perPage := query.Pagination()
+		filter := models.SavedSearchFilter{
+			BuyerID: query.Int("buyerId", 0),
+			Page:    page,
+			PerPage: perPage,
+		}
+		err := query.Validate(filter.Validate)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		result, err := searches.ListSavedSearches(r.Context(), filter)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if result == nil {
+			result = []models.SavedSearch{}
+		}
+		response.JSON(w, http.StatusOK, result)
+	}
+}
+
+// UpdateSavedSearch replaces the name and filters of the saved search matching the id of the path
+//
+// It is mounted on PUT /api/v1/saved-search/{id} and takes the body of CreateSavedSearch, the
+// buyer of a saved search cannot be changed and may be left out
+func UpdateSavedSearch(searches models.SavedSearchStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var search models.SavedSearch
+		err = decodeJSON(r, &search)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		current, err := searches.GetSavedSearchByID(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		search.ID, search.BuyerID = id, current.BuyerID
+		err = search.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = searches.UpdateSavedSearch(r.Context(), &search)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, search)
+	}
+}
+
+// DeleteSavedSearch deletes the saved search matching the id of the path, its buyer is no
+// longer alerted about it
+//
+// It is mounted on DELETE /api/v1/saved-search/{id} and responds with 204 No Content
+func DeleteSavedSearch(searches models.SavedSearchStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = searches.DeleteSavedSearch(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
+	}
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/alerts"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// recordingNotifier records the alerts sent by matcher
+type recordingNotifier struct {
+	mu     sync.Mutex
+	alerts []alerts.Alert
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, a alerts.Alert) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.alerts = append(n.alerts, a)
+	return nil
+}
+
+// alertsOf waits for the products written to be matched and returns the alerts of the buyer
+func alertsOf(t *testing.T, buyerID int) []alerts.Alert {
+	t.Helper()
+	if err := matcher.Flush(context.Background()); err != nil {
+		t.Fatalf("Failed to flush the matcher: %v", err)
+	}
+	notifier.mu.Lock()
+	defer notifier.mu.Unlock()
+	var sent []alerts.Alert
+	for _, a := range notifier.alerts {
+		if a.BuyerID == buyerID {
+			sent = append(sent, a)
+		}
+	}
+	return sent
+}
+
+func TestSavedSearches(t *testing.T) {
+	buyerID := createTestBuyer(t, "toasters@example.com").ID
+	body := fmt.Sprintf(`{"buyerId": %d, "name": "Toasters", "productName": "toaster", "maxPrice": 100, "desiredQty": 3}`, buyerID)
+	recorder := serve(http.MethodPost, "/api/v1/saved-search", []byte(body))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var search models.SavedSearch
+	decodeData(t, recorder, &search)
+	if search.ID == 0 || search.BuyerID != buyerID || search.CreatedAt.IsZero() {
+		t.Errorf("Unexpected saved search %+v", search)
+	}
+	target := fmt.Sprintf("/api/v1/saved-search/%d", search.ID)
+
+	recorder = serve(http.MethodGet, fmt.Sprintf("/api/v1/saved-searches?buyerId=%d", buyerID), nil)
+	var searches []models.SavedSearch
+	decodeData(t, recorder, &searches)
+	if len(searches) != 1 || searches[0].ID != search.ID {
+		t.Errorf("Expected the saved search of the buyer, got %+v", searches)
+	}
+
+	// a toaster short of the desired quantity, then restocked
+	recorder = serve(http.MethodPost, "/api/v1/product", []byte(`{"sellerId": 1, "productName": "Quantum Toaster", "price": 50, "quantity": 1}`))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var product models.Product
+	decodeData(t, recorder, &product)
+	if sent := alertsOf(t, buyerID); len(sent) != 0 {
+		t.Errorf("Expected no alert for a product short of the desired quantity, got %+v", sent)
+	}
+	recorder = serve(http.MethodPatch, fmt.Sprintf("/api/v1/product/%d", product.ID), []byte(`{"quantity": 5}`))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	sent := alertsOf(t, buyerID)
+	if len(sent) != 1 || sent[0].Product.ID != product.ID || sent[0].SavedSearch.ID != search.ID || !sent[0].Restocked {
+		t.Errorf("Expected an alert for the restocked toaster, got %+v", sent)
+	}
+
+	recorder = serve(http.MethodPut, target, []byte(`{"name": "Cheap toasters", "productName": "toaster", "maxPrice": 40}`))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	decodeData(t, recorder, &search)
+	if search.BuyerID != buyerID || search.MaxPrice != 40 || search.DesiredQty != 0 {
+		t.Errorf("Expected the filters to be replaced and the buyer kept, got %+v", search)
+	}
+
+	if code := serve(http.MethodDelete, target, nil).Code; code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+	if code := serve(http.MethodGet, target, nil).Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 for a deleted saved search, got %d", code)
+	}
+}
+
+func TestSavedSearches_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		fields []string
+	}{
+		{"no filter", http.MethodPost, "/api/v1/saved-search", `{"buyerId": 9002, "desiredQty": 2}`, []string{"productName"}},
+		{"no buyer", http.MethodPost, "/api/v1/saved-search", `{"productName": "drone", "minPrice": 20, "maxPrice": 10}`, []string{"buyerId", "maxPrice"}},
+		{"unknown buyer", http.MethodPost, "/api/v1/saved-search", `{"buyerId": 9999, "productName": "drone"}`, []string{"buyerId"}},
+		{"list without buyer", http.MethodGet, "/api/v1/saved-searches", ``, []string{"buyerId"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(tt.method, tt.target, []byte(tt.body))
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("Expected 400, got %d", recorder.Code)
+			}
+			if fields := errorFields(t, recorder); fmt.Sprint(fields) != fmt.Sprint(tt.fields) {
+				t.Errorf("Expected invalid fields %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+	recorder := serve(http.MethodPut, "/api/v1/saved-search/9999", []byte(`{"productName": "drone"}`))
+	if recorder.Code != http.StatusNotFound || decodeError(t, recorder).Code != "saved_search_not_found" {
+		t.Errorf("Expected saved_search_not_found, got %d %s", recorder.Code, recorder.Body)
+	}
+}
@@ -2,65 +2,226 @@
 package handlers
 
 import (
-	"encoding/json"
-	"fmt"
-	"io"
 	"net/http"
 
 	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/utils"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
 )
 
-// SellerHandler handles the creation of a seller,
+// CreateSeller handles the creation of a seller,
 //
-// POST is the only operation supported by this API. The below is json input
+// It is mounted on POST /api/v1/seller. The below is json input, the address is optional and
+// a seller without one gets the country its location names, if any
 //
 // Input:
 //
 //	{
 //	  "name": "Seller Name",
-//	  "location": "Seller Location"
+//	  "location": "Seller Location",
+//	  "address": {"country": "IN", "city": "Bengaluru", "postalCode": "560001", "latitude": 12.97, "longitude": 77.59}
 //	}
 //
 // Output:
 //
-//	Seller created with ID: 1
+//	{
+//	  "data": {
+//	    "id": 1,
+//	    "name": "Seller Name",
+//	    "location": "Seller Location",
+//	    "address": {"country": "IN", "city": "Bengaluru", "postalCode": "560001", "latitude": 12.97, "longitude": 77.59}
+//	  }
+//	}
 //
-// Returns a saved record id back as JSON response or error if any
-func SellerHandler(w http.ResponseWriter, r *http.Request) {
-	defer utils.PanicHandler(w)
+// Returns the saved seller back as JSON response or error if any
+func CreateSeller(sellers models.SellerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var seller models.Seller
+		err := decodeJSON(r, &seller)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
 
-	if r.Method != http.MethodPost {
-		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
-		return
+		err = seller.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = sellers.CreateSeller(r.Context(), &seller)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, seller)
 	}
+}
 
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
-		return
+// GetSeller responds with the seller matching the id of the path
+//
+// It is mounted on GET /api/v1/seller/{id}
+func GetSeller(sellers models.SellerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		seller, err := sellers.GetSellerByID(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, seller)
 	}
+}
 
-	defer r.Body.Close()
+// ListSellers responds with a page of sellers ordered by id
+//
+// It is mounted on GET /api/v1/sellers
+//
+// Query Parameters:
+//
+//	`location` (optional): Location of the sellers, matched exactly without regard to case
+//	`country` (optional): Comma separated ISO 3166-1 alpha-2 codes of the countries of the sellers
+//	`page` (optional): Page number for pagination
+//	`perPage` (optional): Number of sellers per page, at most 100
+func ListSellers(sellers models.SellerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := newQueryParams(r)
+		page, perPage := query.Pagination()
+		filter := models.SellerFilter{
+			Location:  query.String("location", ""),
+			Countries: query.List("country"),
+			Page:      page,
+			PerPage:   perPage,
+		}
+		err := query.Validate(filter.Validate)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
 
-	var seller models.Seller
-	err = json.Unmarshal(body, &seller)
-	if err != nil {
-		http.Error(w, "Invalid request body", http.StatusBadRequest)
-		return
+		result, err := sellers.ListSellers(r.Context(), filter)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		if result == nil {
+			result = []models.Seller{}
+		}
+		response.JSON(w, http.StatusOK, result)
 	}
+}
 
-	if seller.Name == "" || seller.Location == "" {
-		w.WriteHeader(http.StatusBadRequest)
-		http.Error(w, "Name and location cannot be empty", http.StatusBadRequest)
-		return
+// UpdateSeller changes the name, location or address of the seller matching the id of the path
+//
+// It is mounted on PUT /api/v1/seller/{id}, where the name and location are required, and on
+// PATCH /api/v1/seller/{id}, where only the fields given are changed. An address replaces the
+// one of the seller as a whole. The below is json input
+//
+// Input:
+//
+//	{
+//	  "name": "Seller Name",
+//	  "location": "Seller Location",
+//	  "address": {"country": "US", "city": "Austin"}
+//	}
+//
+// Returns the updated seller
+func UpdateSeller(sellers models.SellerStore, partial bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var update models.SellerUpdate
+		err = decodeJSON(r, &update)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		err = update.Validate(!partial)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		seller, err := sellers.UpdateSeller(r.Context(), id, update)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, seller)
 	}
+}
+
+// DeleteSeller deletes the seller matching the id of the path
+//
+// It is mounted on DELETE /api/v1/seller/{id}. A seller that still has products is not
+// deleted and the response is 409 Conflict, unless `cascade=true` is given in which case its
+// products are deleted along with it. Responds with 204 No Content
+func DeleteSeller(sellers models.SellerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		query := newQueryParams(r)
+		cascade := query.Bool("cascade", false)
+		err = query.errs.Err()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = sellers.DeleteSeller(r.Context(), id, cascade)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
+	}
+}
+
+// ListSellerProducts responds with a page of the products of the seller matching the id of the path
+//
+// It is mounted on GET /api/v1/seller/{id}/products and accepts the `sortBy`, `page`,
+// `perPage`, `cursor` and `withTotal` query parameters of the product search
+func ListSellerProducts(products models.ProductStore, sellers models.SellerStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		_, err = sellers.GetSellerByID(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		query := newQueryParams(r)
+		page, perPage := query.Pagination()
+		productRequest := &models.ProductRequest{
+			SellerID: id,
+			SortBy:   query.String("sortBy", ""),
+			Page:     page,
+			PerPage:  perPage,
+			Cursor:   query.Cursor(),
+		}
+		withTotal := query.Bool("withTotal", false)
+		err = query.Validate(productRequest.Validate)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
 
-	err = seller.Save()
-	if err != nil {
-		http.Error(w, "Failed to save seller", http.StatusInternalServerError)
-		return
+		writeProductPage(w, r, products, productRequest, withTotal, nil)
 	}
-	w.WriteHeader(http.StatusCreated)
-	fmt.Fprintf(w, "Seller created with ID: %d", seller.ID)
 }
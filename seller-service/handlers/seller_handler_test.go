@@ -2,17 +2,17 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
-	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 )
 
-func TestSellerHandler(t *testing.T) {
+func TestCreateSeller(t *testing.T) {
 	seller := models.Seller{
 		Name:     "Test Seller",
 		Location: "Test Location",
@@ -22,37 +22,33 @@ func TestSellerHandler(t *testing.T) {
 		t.Fatalf("Failed to marshal request payload: %v", err)
 	}
 
-	req := httptest.NewRequest(http.MethodPost, "/seller", bytes.NewReader(payload))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/seller", bytes.NewReader(payload))
 
 	recorder := httptest.NewRecorder()
 
-	SellerHandler(recorder, req)
+	routes.ServeHTTP(recorder, req)
 
 	res := recorder.Result()
 
-	body, err := io.ReadAll(res.Body)
-	if err != nil {
-		t.Fatalf("Failed to read response body: %v", err)
-	}
-
 	if res.StatusCode != http.StatusCreated {
 		t.Errorf("Unexpected response status code: %d", res.StatusCode)
 	}
 
-	expectedResponse := fmt.Sprintf("Seller created with ID: ")
-	if !strings.Contains(string(body), expectedResponse) {
-		t.Errorf("Unexpected response body. Expected: %s, Got: %s", expectedResponse, string(body))
+	var created models.Seller
+	decodeData(t, recorder, &created)
+	if created.ID == 0 || created.Name != seller.Name || created.Location != seller.Location {
+		t.Errorf("Expected the created seller back, got %+v", created)
 	}
 }
 
-func TestSellerHandler_InvalidPayload(t *testing.T) {
+func TestCreateSeller_InvalidPayload(t *testing.T) {
 	invalidPayload := []byte(`{"invalid": "payload"}`)
 
-	req := httptest.NewRequest(http.MethodPost, "/seller", bytes.NewReader(invalidPayload))
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/seller", bytes.NewReader(invalidPayload))
 
 	recorder := httptest.NewRecorder()
 
-	SellerHandler(recorder, req)
+	routes.ServeHTTP(recorder, req)
 
 	res := recorder.Result()
 
@@ -61,12 +57,12 @@ func TestSellerHandler_InvalidPayload(t *testing.T) {
 	}
 }
 
-func TestSellerHandler_InvalidMethod(t *testing.T) {
-	req := httptest.NewRequest(http.MethodGet, "/seller", nil)
+func TestCreateSeller_InvalidMethod(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/seller", nil)
 
 	recorder := httptest.NewRecorder()
 
-	SellerHandler(recorder, req)
+	routes.ServeHTTP(recorder, req)
 
 	res := recorder.Result()
 
@@ -74,3 +70,205 @@ func TestSellerHandler_InvalidMethod(t *testing.T) {
 		t.Errorf("Unexpected response status code: %d", res.StatusCode)
 	}
 }
+
+// createTestSeller adds a seller with products to the shared store for tests changing it
+func createTestSeller(t *testing.T, products int) models.Seller {
+	t.Helper()
+	ctx := context.Background()
+	seller := models.Seller{Name: "Temp Seller", Location: "FR"}
+	if err := store.CreateSeller(ctx, &seller); err != nil {
+		t.Fatalf("Failed to create seller: %v", err)
+	}
+	for i := 0; i < products; i++ {
+		product := models.Product{SellerID: seller.ID, ProductName: "Temp Product", Price: 5, Quantity: 1}
+		if err := store.CreateProduct(ctx, &product); err != nil {
+			t.Fatalf("Failed to create product: %v", err)
+		}
+	}
+	return seller
+}
+
+func TestGetSeller(t *testing.T) {
+	recorder := serve(http.MethodGet, "/api/v1/seller/2", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d", recorder.Code)
+	}
+	var seller models.Seller
+	decodeData(t, recorder, &seller)
+	if seller.ID != 2 || seller.Name != "Seller B" || seller.Location != "US" {
+		t.Errorf("Unexpected seller: %+v", seller)
+	}
+	if code := serve(http.MethodGet, "/api/v1/seller/99999", nil).Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown seller, got %d", code)
+	}
+}
+
+func TestListSellers(t *testing.T) {
+	recorder := serve(http.MethodGet, "/api/v1/sellers?location=IND&perPage=1&page=2", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d", recorder.Code)
+	}
+	var sellers []models.Seller
+	decodeData(t, recorder, &sellers)
+	if len(sellers) != 1 || sellers[0].Name != "Seller C" {
+		t.Errorf("Expected the second seller located in IND, got %+v", sellers)
+	}
+
+	if code := serve(http.MethodGet, "/api/v1/sellers?perPage=101", nil).Code; code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a page larger than the maximum, got %d", code)
+	}
+
+	recorder = serve(http.MethodGet, "/api/v1/sellers?location=nowhere", nil)
+	if body := strings.TrimSpace(recorder.Body.String()); body != `{"data":[]}` {
+		t.Errorf("Expected an empty list, got %s", body)
+	}
+}
+
+func TestUpdateSeller(t *testing.T) {
+	seller := createTestSeller(t, 0)
+	target := fmt.Sprintf("/api/v1/seller/%d", seller.ID)
+
+	if code := serve(http.MethodPatch, target, []byte(`{"location": "DE"}`)).Code; code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d", code)
+	}
+	got, _ := store.GetSellerByID(context.Background(), seller.ID)
+	if got.Location != "DE" || got.Name != seller.Name {
+		t.Errorf("Expected only the location to change, got %+v", got)
+	}
+
+	if code := serve(http.MethodPut, target, []byte(`{"name": "Renamed"}`)).Code; code != http.StatusBadRequest {
+		t.Errorf("Expected 400 when PUT misses a field, got %d", code)
+	}
+	if code := serve(http.MethodPatch, target, []byte(`{"name": ""}`)).Code; code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an empty name, got %d", code)
+	}
+	recorder := serve(http.MethodPut, target, []byte(`{"name": "", "location": "`+strings.Repeat("x", 256)+`"}`))
+	if fields := errorFields(t, recorder); recorder.Code != http.StatusBadRequest || len(fields) != 2 {
+		t.Errorf("Expected 400 listing name and location, got %d %v", recorder.Code, fields)
+	}
+	if code := serve(http.MethodPatch, "/api/v1/seller/99999", []byte(`{"name": "x"}`)).Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown seller, got %d", code)
+	}
+}
+
+func TestDeleteSeller(t *testing.T) {
+	seller := createTestSeller(t, 2)
+	target := fmt.Sprintf("/api/v1/seller/%d", seller.ID)
+
+	recorder := serve(http.MethodDelete, target, nil)
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("Expected 409 deleting a seller with products, got %d", recorder.Code)
+	}
+	if apiErr := decodeError(t, recorder); apiErr.Code != "seller_has_products" {
+		t.Errorf("Expected the seller_has_products error, got %+v", apiErr)
+	}
+	if code := serve(http.MethodDelete, target+"?cascade=maybe", nil).Code; code != http.StatusBadRequest {
+		t.Fatalf("Expected 400 for an invalid cascade, got %d", code)
+	}
+	if code := serve(http.MethodDelete, target+"?cascade=true", nil).Code; code != http.StatusNoContent {
+		t.Fatalf("Expected 204 deleting with cascade, got %d", code)
+	}
+	if code := serve(http.MethodGet, target, nil).Code; code != http.StatusNotFound {
+		t.Errorf("Expected the seller to be gone, got %d", code)
+	}
+	page, _ := store.SearchProducts(context.Background(), &models.ProductRequest{SellerID: seller.ID, Page: 1, PerPage: 10})
+	if len(page.Products) != 0 {
+		t.Errorf("Expected the products to be deleted, got %+v", page.Products)
+	}
+
+	empty := createTestSeller(t, 0)
+	if code := serve(http.MethodDelete, fmt.Sprintf("/api/v1/seller/%d", empty.ID), nil).Code; code != http.StatusNoContent {
+		t.Errorf("Expected 204 deleting a seller without products, got %d", code)
+	}
+}
+
+func TestListSellerProducts(t *testing.T) {
+	recorder := serve(http.MethodGet, "/api/v1/seller/2/products?sortBy=price&perPage=3", nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d", recorder.Code)
+	}
+	var products []models.Product
+	decodeData(t, recorder, &products)
+	if len(products) != 3 {
+		t.Fatalf("Expected a page of 3 products, got %+v", products)
+	}
+	for i, p := range products {
+		if p.SellerID != 2 {
+			t.Errorf("Expected products of seller 2, got %+v", p)
+		}
+		if i > 0 && p.Price < products[i-1].Price {
+			t.Errorf("Expected products sorted by price, got %+v", products)
+		}
+	}
+	if code := serve(http.MethodGet, "/api/v1/seller/99999/products", nil).Code; code != http.StatusNotFound {
+		t.Errorf("Expected 404 for an unknown seller, got %d", code)
+	}
+}
+
+// createGeoSeller creates a seller in India at the coordinates through the API
+func createGeoSeller(t *testing.T, city string, lat, lng float64) models.Seller {
+	t.Helper()
+	body := fmt.Sprintf(`{"name": "Seller %s", "location": "IND", "address": {"country": "in", "city": %q, "latitude": %v, "longitude": %v}}`, city, city, lat, lng)
+	recorder := serve(http.MethodPost, "/api/v1/seller", []byte(body))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Expected 201 creating seller in %s, got %d: %s", city, recorder.Code, recorder.Body)
+	}
+	var seller models.Seller
+	decodeData(t, recorder, &seller)
+	return seller
+}
+
+func TestSellerAddress(t *testing.T) {
+	seller := createGeoSeller(t, "Pune", 18.5204, 73.8567)
+	if a := seller.Address; a == nil || a.Country != "IN" || a.City != "Pune" || a.Latitude == nil || *a.Latitude != 18.5204 {
+		t.Errorf("Expected the address saved with the country in uppercase, got %+v", a)
+	}
+
+	// a location naming a country gives the seller that country, until it is replaced
+	legacy := createTestSeller(t, 0)
+	target := fmt.Sprintf("/api/v1/seller/%d", legacy.ID)
+	var updated models.Seller
+	decodeData(t, serve(http.MethodPatch, target, []byte(`{"location": "United Kingdom"}`)), &updated)
+	if updated.Address == nil || updated.Address.Country != "GB" {
+		t.Errorf("Expected the country derived from the location, got %+v", updated.Address)
+	}
+	decodeData(t, serve(http.MethodPatch, target, []byte(`{"location": "usa"}`)), &updated)
+	if updated.Address == nil || updated.Address.Country != "US" {
+		t.Errorf("Expected the derived country to follow the location, got %+v", updated.Address)
+	}
+
+	var sellers []models.Seller
+	decodeData(t, serve(http.MethodGet, "/api/v1/sellers?country=us&perPage=100", nil), &sellers)
+	found := false
+	for _, s := range sellers {
+		if s.Address == nil || s.Address.Country != "US" {
+			t.Errorf("Expected only sellers in the US, got %+v", s)
+		}
+		found = found || s.ID == legacy.ID
+	}
+	if !found {
+		t.Errorf("Expected the updated seller among the sellers in the US, got %+v", sellers)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		fields []string
+	}{
+		{"unknown country", `{"name": "X", "location": "Y", "address": {"country": "XX"}}`, []string{"address.country"}},
+		{"missing country", `{"name": "X", "location": "Y", "address": {"city": "Pune"}}`, []string{"address.country"}},
+		{"latitude alone", `{"name": "X", "location": "Y", "address": {"country": "IN", "latitude": 18.5}}`, []string{"address.longitude"}},
+		{"out of range", `{"name": "X", "location": "Y", "address": {"country": "IN", "latitude": 91, "longitude": 181}}`, []string{"address.latitude", "address.longitude"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(http.MethodPost, "/api/v1/seller", []byte(tt.body))
+			if fields := errorFields(t, recorder); recorder.Code != http.StatusBadRequest || strings.Join(fields, ",") != strings.Join(tt.fields, ",") {
+				t.Errorf("Expected 400 on %v, got %d %v", tt.fields, recorder.Code, fields)
+			}
+		})
+	}
+	if code := serve(http.MethodGet, "/api/v1/sellers?country=IND", nil).Code; code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an alpha-3 country filter, got %d", code)
+	}
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/suggest"
+)
+
+// SuggestProducts responds with the product names completing what a buyer has typed
+//
+// It is mounted on GET /api/v1/product/suggest and answered from the in-memory index, without
+// querying the store
+//
+// Query Parameters:
+//
+//	`q` (required): Beginning of a product name, or of one of its words
+//	`limit` (optional): Number of names to return, 10 by default and at most 20
+//
+// Names starting with q come first, then those with a later word starting with it, each
+// group ranked by the number of products with the name
+//
+//	{"data": [
+//	  {"name": "Smartphone", "productCount": 2},
+//	  {"name": "Smart TV", "productCount": 1},
+//	  {"name": "Wireless Smartwatch", "productCount": 1}
+//	]}
+func SuggestProducts(index *suggest.Index) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := newQueryParams(r)
+		req := models.SuggestRequest{
+			Query: query.String("q", ""),
+			Limit: query.Int("limit", 10),
+		}
+		err := query.Validate(req.Validate)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, index.Suggest(req.Query, req.Limit))
+	}
+}
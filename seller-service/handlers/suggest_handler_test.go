@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/suggest"
+)
+
+func suggestNames(t *testing.T, target string) []suggest.Suggestion {
+	t.Helper()
+	recorder := serve(http.MethodGet, target, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var suggestions []suggest.Suggestion
+	decodeData(t, recorder, &suggestions)
+	return suggestions
+}
+
+func TestSuggestProducts(t *testing.T) {
+	// the names of two seeded products each come first
+	got := suggestNames(t, "/api/v1/product/suggest?q=sma&limit=2")
+	want := []suggest.Suggestion{{Name: "Smartphone", ProductCount: 2}, {Name: "Smartwatch", ProductCount: 2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	got = suggestNames(t, "/api/v1/product/suggest?q=SMART%20t")
+	want = []suggest.Suggestion{{Name: "Smart TV", ProductCount: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	// a later word of the name completes too
+	got = suggestNames(t, "/api/v1/product/suggest?q=hub")
+	want = []suggest.Suggestion{{Name: "Smart Home Hub", ProductCount: 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+
+	if got = suggestNames(t, "/api/v1/product/suggest?q=nothing"); len(got) != 0 {
+		t.Errorf("Expected no suggestions, got %+v", got)
+	}
+}
+
+func TestSuggestProducts_FollowsWrites(t *testing.T) {
+	recorder := serve(http.MethodPost, "/api/v1/product", []byte(`{"sellerId": 1, "productName": "Zephyr Kettle", "price": 30, "quantity": 3}`))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var product struct {
+		ID int `json:"id"`
+	}
+	decodeData(t, recorder, &product)
+	if got := suggestNames(t, "/api/v1/product/suggest?q=zeph"); len(got) != 1 || got[0].Name != "Zephyr Kettle" {
+		t.Fatalf("Expected the created product to be suggested, got %+v", got)
+	}
+
+	target := fmt.Sprintf("/api/v1/product/%d", product.ID)
+	if code := serve(http.MethodPatch, target, []byte(`{"productName": "Zephyr Toaster"}`)).Code; code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", code)
+	}
+	if got := suggestNames(t, "/api/v1/product/suggest?q=zeph"); len(got) != 1 || got[0].Name != "Zephyr Toaster" {
+		t.Fatalf("Expected the renamed product to be suggested, got %+v", got)
+	}
+
+	if code := serve(http.MethodDelete, target, nil).Code; code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+	if got := suggestNames(t, "/api/v1/product/suggest?q=zeph"); len(got) != 0 {
+		t.Errorf("Expected the deleted product to be gone, got %+v", got)
+	}
+}
+
+func TestSuggestProducts_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		field  string
+	}{
+		{"missing q", "/api/v1/product/suggest", "q"},
+		{"limit too large", "/api/v1/product/suggest?q=sma&limit=21", "limit"},
+		{"limit not a number", "/api/v1/product/suggest?q=sma&limit=many", "limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := serve(http.MethodGet, tt.target, nil)
+			if recorder.Code != http.StatusBadRequest {
+				t.Fatalf("Expected 400, got %d", recorder.Code)
+			}
+			if fields := errorFields(t, recorder); !reflect.DeepEqual(fields, []string{tt.field}) {
+				t.Errorf("Expected an error on %s, got %v", tt.field, fields)
+			}
+		})
+	}
+}
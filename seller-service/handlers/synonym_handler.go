@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// ListSynonymGroups responds with every group of the synonym dictionary
+//
+// It is mounted on GET /api/v1/admin/synonyms
+//
+//	{"data": [
+//	  {"id": 1, "terms": ["earbuds", "earphones", "in-ear headphones"]},
+//	  {"id": 2, "terms": ["cellphone", "mobile", "smartphone"]}
+//	]}
+func ListSynonymGroups(synonyms models.SynonymStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		groups, err := synonyms.ListSynonymGroups(r.Context())
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, groups)
+	}
+}
+
+// CreateSynonymGroup adds a group of terms to the synonym dictionary, a search for one of them
+// also finds the products named with the others from then on
+//
+// It is mounted on POST /api/v1/admin/synonyms. The terms are saved in lowercase, a group
+// holds between 2 and 20 of them
+//
+// Input:
+//
+//	{"terms": ["Earphones", "earbuds", "in-ear headphones"]}
+//
+// Returns the saved group with a 201
+func CreateSynonymGroup(synonyms models.SynonymStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var group models.SynonymGroup
+		err := decodeJSON(r, &group)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		err = group.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = synonyms.CreateSynonymGroup(r.Context(), &group)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusCreated, group)
+	}
+}
+
+// UpdateSynonymGroup replaces the terms of the group matching the id of the path
+//
+// It is mounted on PUT /api/v1/admin/synonyms/{id} and takes the body of CreateSynonymGroup
+func UpdateSynonymGroup(synonyms models.SynonymStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		var group models.SynonymGroup
+		err = decodeJSON(r, &group)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		err = group.Validate()
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		group.ID = id
+		err = synonyms.UpdateSynonymGroup(r.Context(), &group)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.JSON(w, http.StatusOK, group)
+	}
+}
+
+// DeleteSynonymGroup deletes the group matching the id of the path
+//
+// It is mounted on DELETE /api/v1/admin/synonyms/{id} and responds with 204 No Content
+func DeleteSynonymGroup(synonyms models.SynonymStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := pathID(r)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+
+		err = synonyms.DeleteSynonymGroup(r.Context(), id)
+		if err != nil {
+			writeError(w, r, err)
+			return
+		}
+		response.NoContent(w)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// search runs a GET search and returns its products and didYouMean
+func search(t *testing.T, target string) ([]models.Product, string) {
+	t.Helper()
+	recorder := serve(http.MethodGet, target, nil)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var page searchPage
+	if err := json.Unmarshal(recorder.Body.Bytes(), &page); err != nil {
+		t.Fatalf("Failed to unmarshal response body: %v", err)
+	}
+	return page.Data, page.Meta.DidYouMean
+}
+
+func TestSynonyms(t *testing.T) {
+	products, didYouMean := search(t, "/api/v1/product/search?productName=earphones")
+	if len(products) != 0 || didYouMean != "headphones" {
+		t.Fatalf("Expected no earphones and headphones suggested, got %d products and %q", len(products), didYouMean)
+	}
+
+	recorder := serveAdmin(http.MethodPost, "/api/v1/admin/synonyms", []byte(`{"terms": ["Earphones", "earbuds"]}`))
+	if recorder.Code != http.StatusCreated {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	var group models.SynonymGroup
+	decodeData(t, recorder, &group)
+	if group.ID == 0 || !reflect.DeepEqual(group.Terms, []string{"earbuds", "earphones"}) {
+		t.Errorf("Unexpected group %+v", group)
+	}
+	target := fmt.Sprintf("/api/v1/admin/synonyms/%d", group.ID)
+	defer serveAdmin(http.MethodDelete, target, nil)
+
+	for _, query := range []string{"productName=earphones", "q=earphones"} {
+		products, didYouMean = search(t, "/api/v1/product/search?"+query)
+		if len(products) != 2 || products[0].ProductName != "Wireless Earbuds" || didYouMean != "" {
+			t.Errorf("Expected %s to find the Wireless Earbuds, got %+v and %q", query, products, didYouMean)
+		}
+	}
+
+	recorder = serveAdmin(http.MethodPut, target, []byte(`{"terms": ["earphones", "headphones"]}`))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Unexpected response status code: %d %s", recorder.Code, recorder.Body)
+	}
+	if products, _ = search(t, "/api/v1/product/search?productName=earphones"); len(products) < 2 || !strings.Contains(products[0].ProductName, "Headphones") {
+		t.Errorf("Expected the updated group to find the Headphones, got %+v", products)
+	}
+
+	recorder = serveAdmin(http.MethodGet, "/api/v1/admin/synonyms", nil)
+	var groups []models.SynonymGroup
+	decodeData(t, recorder, &groups)
+	if len(groups) != 1 || !reflect.DeepEqual(groups[0].Terms, []string{"earphones", "headphones"}) {
+		t.Errorf("Unexpected groups %+v", groups)
+	}
+
+	if code := serveAdmin(http.MethodDelete, target, nil).Code; code != http.StatusNoContent {
+		t.Fatalf("Expected 204, got %d", code)
+	}
+	if products, _ = search(t, "/api/v1/product/search?productName=earphones"); len(products) != 0 {
+		t.Errorf("Expected the deleted group not to apply, got %+v", products)
+	}
+}
+
+func TestSynonyms_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		status int
+	}{
+		{"one term", http.MethodPost, "/api/v1/admin/synonyms", `{"terms": ["earbuds"]}`, http.StatusBadRequest},
+		{"unknown field", http.MethodPost, "/api/v1/admin/synonyms", `{"words": ["a", "b"]}`, http.StatusBadRequest},
+		{"unknown group", http.MethodPut, "/api/v1/admin/synonyms/9999", `{"terms": ["tv", "television"]}`, http.StatusNotFound},
+		{"delete unknown group", http.MethodDelete, "/api/v1/admin/synonyms/9999", ``, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := serveAdmin(tt.method, tt.target, []byte(tt.body)).Code; code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, code)
+			}
+		})
+	}
+	if code := serve(http.MethodGet, "/api/v1/admin/synonyms", nil).Code; code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", code)
+	}
+}
+
+func TestSearchProducts_DidYouMean(t *testing.T) {
+	products, didYouMean := search(t, "/api/v1/product/search?productName=smartfone")
+	if len(products) != 0 || didYouMean != "smartphone" {
+		t.Errorf("Expected smartphone to be suggested, got %d products and %q", len(products), didYouMean)
+	}
+	if _, didYouMean = search(t, "/api/v1/product/search?productName=smartphone"); didYouMean != "" {
+		t.Errorf("Expected no suggestion for a known word, got %q", didYouMean)
+	}
+	// the first page only
+	if _, didYouMean = search(t, "/api/v1/product/search?productName=smartfone&page=2"); didYouMean != "" {
+		t.Errorf("Expected no suggestion past the first page, got %q", didYouMean)
+	}
+}
@@ -2,65 +2,205 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/db"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/handlers"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+	"fmt"
 	"io"
 	"log/syslog"
 	"net/http"
 	"os"
 	"os/signal"
-	"time"
-)
 
-var (
-	ServiceName = "seller-service"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/alerts"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/analytics"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/config"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/db"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/handlers"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/memstore"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/middleware"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/suggest"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/synonyms"
 )
 
+const usage = `usage: seller-service [flags]
+       seller-service [flags] migrate up|down|status|to <version>
+       seller-service [flags] config
+
+The config subcommand prints the effective configuration with secrets redacted.
+
+flags:
+`
+
 func main() {
-	var (
-		serviceName   = flag.String("service.name", ServiceName, "Name of service")
-		sysLogAddress = flag.String("syslog.address", "localhost:514", "default location for the syslogger")
-	)
-	flag.Parse()
-	if *serviceName == "" {
-		serviceName = &ServiceName
+	flags := flag.NewFlagSet("seller-service", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	cfg, err := config.Load(flags, os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		os.Exit(2)
+	}
+
+	// Print the configuration and exit
+	if flags.Arg(0) == "config" {
+		if err := cfg.Print(os.Stdout); err != nil {
+			os.Exit(1)
+		}
+		return
 	}
 
 	var logOutput io.Writer
-	if os.Getenv("ENABLE_DEV_MODE") == "true" {
+	if cfg.DevMode {
 		logOutput = os.Stdout
 	} else {
-		sysLogger, err := syslog.Dial("udp", *sysLogAddress, syslog.LOG_EMERG|syslog.LOG_LOCAL6, *serviceName)
+		sysLogger, err := syslog.Dial("udp", cfg.SyslogAddr, syslog.LOG_INFO|syslog.LOG_LOCAL6, cfg.ServiceName)
 		if err != nil {
 			panic("failed to connect to syslog! application will now exit")
 		}
 		defer sysLogger.Close()
 		logOutput = sysLogger
 	}
+	logLevel, err := logging.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		panic(err)
+	}
 	// Initialize the logger
 	logging.Init(logging.Config{
 		Output:   logOutput,
-		Prefix:   *serviceName,
-		LogLevel: logging.DEBUG,
+		Prefix:   cfg.ServiceName,
+		LogLevel: logLevel,
+		Format:   cfg.LogFormat,
 	})
 	logger := logging.GetLogger()
 
+	// Run the migrate subcommand and exit
+	if flags.Arg(0) == "migrate" {
+		conn, err := db.Open(cfg.MySQL)
+		if err == nil {
+			defer conn.Close()
+			var migrator *db.Migrator
+			migrator, err = db.NewMigrator(conn)
+			if err == nil {
+				err = runMigrate(context.Background(), migrator, flags.Args()[1:], os.Stdout)
+			}
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if flags.NArg() > 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	logger.Infow("Effective configuration", cfg.KeyValues()...)
+
 	// Initialize the datastore
-	db.Init()
+	var store interface {
+		models.ProductStore
+		models.SellerStore
+		models.CategoryStore
+		models.SearchLogStore
+		models.SynonymStore
+		models.SavedSearchStore
+		models.BuyerStore
+		models.CartStore
+		models.OrderStore
+	}
+	switch cfg.StoreDriver {
+	case "mysql":
+		conn, err := db.Open(cfg.MySQL)
+		if err != nil {
+			logger.Errorw("Failed to connect to mysql", "err", err)
+			os.Exit(1)
+		}
+		defer conn.Close()
+		migrator, err := db.NewMigrator(conn)
+		if err != nil {
+			panic(err)
+		}
+		if err := checkSchema(context.Background(), migrator, cfg.SchemaCheck, logger); err != nil {
+			logger.Error(err)
+			os.Exit(1)
+		}
+		store = db.NewStore(conn)
+	case "memory":
+		logger.Warn("Using the in-memory store, data will be lost on shutdown")
+		store = memstore.New()
+	}
+
+	// Build the index of the product names, the products written through tracked keep it up to date
+	suggestions := suggest.New()
+	if err := suggestions.Rebuild(context.Background(), store); err != nil {
+		logger.Errorw("Failed to build the product name index", "err", err)
+		os.Exit(1)
+	}
+	tracked := suggest.Track(store, suggestions)
+
+	// The synonyms are applied to the searches from memory, the dictionary is reloaded when the
+	// admin endpoints change it
+	dictionary := synonyms.New(store)
+	if err := dictionary.Load(context.Background()); err != nil {
+		logger.Errorw("Failed to load the synonym dictionary", "err", err)
+		os.Exit(1)
+	}
+
+	// The searches are recorded in the background, the events still queued are written on shutdown
+	searches := analytics.NewRecorder(store, cfg.SearchEventBuffer, logger)
+
+	// The products created and restocked through watched are matched against the saved searches
+	// in the background, the buyers are alerted at most once per product within the window
+	var notifier alerts.Notifier = alerts.NewLogNotifier(logger)
+	if cfg.Alerts.Notifier == "file" {
+		file, err := alerts.OpenFileNotifier(cfg.Alerts.File)
+		if err != nil {
+			logger.Errorw("Failed to open the alerts file", "err", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		notifier = file
+	}
+	matcher := alerts.NewMatcher(store, tracked, dictionary, alerts.Deduplicate(notifier, cfg.Alerts.DedupWindow), cfg.Alerts.Buffer, logger)
+	watched := alerts.Watch(tracked, matcher)
 
 	// setup routes
-	http.HandleFunc("/api/v1/product", handlers.ProductHandler)
-	http.HandleFunc("/api/v1/product/search", handlers.SearchProducts)
-	http.HandleFunc("/api/v1/seller/", handlers.SellerHandler)
+	routes := handlers.NewRouter(handlers.Dependencies{
+		Products:      watched,
+		Sellers:       tracked,
+		Categories:    store,
+		Buyers:        store,
+		Carts:         store,
+		Orders:        store,
+		Suggestions:   suggestions,
+		Searches:      searches,
+		SearchLog:     store,
+		SavedSearches: store,
+		Synonyms:      dictionary,
+		AdminToken:    cfg.AdminToken,
+	})
 
-	server := http.Server{Addr: ":8080"}
-	logger.Debug("Starting Application")
+	server := http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           middleware.Chain(routes, middleware.Defaults(logger, cfg.HTTP.RequestTimeout)...),
+		ReadTimeout:       cfg.HTTP.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTP.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTP.WriteTimeout,
+		IdleTimeout:       cfg.HTTP.IdleTimeout,
+	}
+	logger.Infow("Starting Application", "addr", cfg.HTTP.Addr)
 	go func() {
 		// Start Server
 		if err := server.ListenAndServe(); err != http.ErrServerClosed {
-			logger.Error("Server listen failed: %v", err)
+			logger.Errorw("Server listen failed", "err", err)
 		}
 	}()
 
@@ -70,12 +210,18 @@ func main() {
 	<-interrupt
 
 	// Create a context with a timeout
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTP.ShutdownTimeout)
 	defer cancel()
 
-	err := server.Shutdown(ctx)
+	err = server.Shutdown(ctx)
 	if err != nil {
-		logger.Error("Server shutdown failed: %v", err)
+		logger.Errorw("Server shutdown failed", "err", err)
+	}
+	if err := searches.Close(ctx); err != nil {
+		logger.Errorw("Failed to write the queued search events", "err", err)
+	}
+	if err := matcher.Close(ctx); err != nil {
+		logger.Errorw("Failed to match the queued products against the saved searches", "err", err)
 	}
 	logger.Info("Server shutdown complete")
 }
@@ -0,0 +1,179 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// CreateBuyer saves the buyer and sets its ID and CreatedAt, the email must not be taken
+func (s *Store) CreateBuyer(_ context.Context, b *models.Buyer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b.Normalize()
+	if s.emailTaken(b.Email, 0) {
+		return models.ErrBuyerEmailTaken
+	}
+	s.lastBuyerID++
+	b.ID = s.lastBuyerID
+	// mysql keeps milliseconds
+	b.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	s.buyers[b.ID] = *b
+	return nil
+}
+
+// emailTaken tells whether a buyer other than id has the email, the equivalent of the unique
+// index of mysql. Callers hold the lock
+func (s *Store) emailTaken(email string, id int) bool {
+	for _, b := range s.buyers {
+		if b.Email == email && b.ID != id {
+			return true
+		}
+	}
+	return false
+}
+
+// GetBuyerByID retrieves a buyer by ID, returns models.ErrBuyerNotFound if it does not exist
+func (s *Store) GetBuyerByID(_ context.Context, id int) (models.Buyer, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	b, ok := s.buyers[id]
+	if !ok {
+		return models.Buyer{}, models.ErrBuyerNotFound
+	}
+	return b, nil
+}
+
+// UpdateBuyer applies the update to the buyer and returns the updated buyer
+func (s *Store) UpdateBuyer(_ context.Context, id int, update models.BuyerUpdate) (models.Buyer, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.buyers[id]
+	if !ok {
+		return models.Buyer{}, models.ErrBuyerNotFound
+	}
+	update.Apply(&b)
+	if s.emailTaken(b.Email, id) {
+		return models.Buyer{}, models.ErrBuyerEmailTaken
+	}
+	s.buyers[id] = b
+	return b, nil
+}
+
+// DeleteBuyer deletes the buyer along with its addresses, saved searches and cart
+func (s *Store) DeleteBuyer(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buyers[id]; !ok {
+		return models.ErrBuyerNotFound
+	}
+	for _, a := range s.buyerAddresses {
+		if a.BuyerID == id {
+			delete(s.buyerAddresses, a.ID)
+		}
+	}
+	for _, search := range s.savedSearches {
+		if search.BuyerID == id {
+			delete(s.savedSearches, search.ID)
+		}
+	}
+	s.deleteCartItems(func(key cartKey) bool { return key.buyerID == id })
+	delete(s.buyers, id)
+	return nil
+}
+
+// ListBuyerAddresses returns the address book of the buyer ordered by ID
+func (s *Store) ListBuyerAddresses(_ context.Context, buyerID int) ([]models.BuyerAddress, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.buyers[buyerID]; !ok {
+		return nil, models.ErrBuyerNotFound
+	}
+	return s.addressesOf(buyerID), nil
+}
+
+// addressesOf returns the addresses of the buyer ordered by ID. Callers hold the lock
+func (s *Store) addressesOf(buyerID int) []models.BuyerAddress {
+	addresses := []models.BuyerAddress{}
+	for _, a := range s.buyerAddresses {
+		if a.BuyerID == buyerID {
+			addresses = append(addresses, a)
+		}
+	}
+	sort.Slice(addresses, func(i, j int) bool { return addresses[i].ID < addresses[j].ID })
+	return addresses
+}
+
+// CreateBuyerAddress adds the address to the book of its buyer and sets its ID
+func (s *Store) CreateBuyerAddress(_ context.Context, a *models.BuyerAddress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buyers[a.BuyerID]; !ok {
+		return models.ErrBuyerNotFound
+	}
+	book := s.addressesOf(a.BuyerID)
+	if len(book) >= models.MaxBuyerAddresses {
+		return models.ErrTooManyBuyerAddresses
+	}
+	a.Normalize()
+	a.Default = a.Default || len(book) == 0
+	s.lastBuyerAddressID++
+	a.ID = s.lastBuyerAddressID
+	s.saveAddress(*a)
+	return nil
+}
+
+// UpdateBuyerAddress replaces the address of the buyer
+func (s *Store) UpdateBuyerAddress(_ context.Context, a *models.BuyerAddress) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.buyerAddresses[a.ID]
+	if !ok || previous.BuyerID != a.BuyerID {
+		return models.ErrBuyerAddressNotFound
+	}
+	a.Normalize()
+	a.Default = a.Default || previous.Default
+	s.saveAddress(*a)
+	return nil
+}
+
+// saveAddress saves the address, taking the default off the other addresses of the buyer
+// when it is the default one. Callers hold the lock
+func (s *Store) saveAddress(a models.BuyerAddress) {
+	if a.Default {
+		for _, other := range s.buyerAddresses {
+			if other.BuyerID == a.BuyerID && other.Default {
+				other.Default = false
+				s.buyerAddresses[other.ID] = other
+			}
+		}
+	}
+	s.buyerAddresses[a.ID] = a
+}
+
+// DeleteBuyerAddress deletes the address of the buyer, the oldest remaining one becomes the
+// default address when it was
+func (s *Store) DeleteBuyerAddress(_ context.Context, buyerID, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.buyerAddresses[id]
+	if !ok || a.BuyerID != buyerID {
+		return models.ErrBuyerAddressNotFound
+	}
+	delete(s.buyerAddresses, id)
+	if book := s.addressesOf(buyerID); a.Default && len(book) > 0 {
+		book[0].Default = true
+		s.buyerAddresses[book[0].ID] = book[0]
+	}
+	return nil
+}
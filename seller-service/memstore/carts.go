@@ -0,0 +1,139 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// cartKey identifies an item of a cart, the primary key of the cart items in mysql
+type cartKey struct {
+	buyerID   int
+	productID int
+}
+
+// CartItems returns the items of the cart of the buyer in the order they were added, with their
+// products and sellers
+func (s *Store) CartItems(_ context.Context, buyerID int) ([]models.CartItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.buyers[buyerID]; !ok {
+		return nil, models.ErrBuyerNotFound
+	}
+	items := s.cartOf(buyerID)
+	for i := range items {
+		p := s.products[items[i].ProductID]
+		seller := s.sellers[p.SellerID]
+		p.Seller = &seller
+		items[i].Product = p
+	}
+	return items, nil
+}
+
+// cartOf returns the items of the cart of the buyer in the order they were added. Callers hold
+// the lock
+func (s *Store) cartOf(buyerID int) []models.CartItem {
+	items := []models.CartItem{}
+	for key, item := range s.cartItems {
+		if key.buyerID == buyerID {
+			items = append(items, item)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if !items[i].AddedAt.Equal(items[j].AddedAt) {
+			return items[i].AddedAt.Before(items[j].AddedAt)
+		}
+		return items[i].ProductID < items[j].ProductID
+	})
+	return items
+}
+
+// AddCartItem puts the quantity of the product in the cart of the buyer, on top of the quantity
+// already in it, within the stock of the product
+func (s *Store) AddCartItem(_ context.Context, item *models.CartItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buyers[item.BuyerID]; !ok {
+		return models.ErrBuyerNotFound
+	}
+	p, ok := s.products[item.ProductID]
+	if !ok {
+		return models.ErrProductNotFound
+	}
+	key := cartKey{item.BuyerID, item.ProductID}
+	previous, inCart := s.cartItems[key]
+	if !inCart && len(s.cartOf(item.BuyerID)) >= models.MaxCartItems {
+		return models.ErrTooManyCartItems
+	}
+	if item.Quantity+previous.Quantity > p.Quantity {
+		return models.ErrInsufficientStock
+	}
+	item.Quantity += previous.Quantity
+	item.AddedAt = previous.AddedAt
+	if !inCart {
+		// mysql keeps milliseconds
+		item.AddedAt = time.Now().UTC().Truncate(time.Millisecond)
+	}
+	item.Snapshot(p)
+	s.cartItems[key] = *item
+	return nil
+}
+
+// UpdateCartItem sets the quantity of the product in the cart of the buyer, within its stock
+func (s *Store) UpdateCartItem(_ context.Context, item *models.CartItem) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := cartKey{item.BuyerID, item.ProductID}
+	previous, ok := s.cartItems[key]
+	if !ok {
+		return models.ErrCartItemNotFound
+	}
+	p := s.products[item.ProductID]
+	if item.Quantity > p.Quantity {
+		return models.ErrInsufficientStock
+	}
+	item.AddedAt = previous.AddedAt
+	item.Snapshot(p)
+	s.cartItems[key] = *item
+	return nil
+}
+
+// DeleteCartItem takes the product out of the cart of the buyer
+func (s *Store) DeleteCartItem(_ context.Context, buyerID, productID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := cartKey{buyerID, productID}
+	if _, ok := s.cartItems[key]; !ok {
+		return models.ErrCartItemNotFound
+	}
+	delete(s.cartItems, key)
+	return nil
+}
+
+// ClearCart empties the cart of the buyer
+func (s *Store) ClearCart(_ context.Context, buyerID int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buyers[buyerID]; !ok {
+		return models.ErrBuyerNotFound
+	}
+	s.deleteCartItems(func(key cartKey) bool { return key.buyerID == buyerID })
+	return nil
+}
+
+// deleteCartItems deletes the cart items whose key matches, the equivalent of the cascading
+// foreign keys of mysql. Callers hold the lock
+func (s *Store) deleteCartItems(match func(key cartKey) bool) {
+	for key := range s.cartItems {
+		if match(key) {
+			delete(s.cartItems, key)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package memstore
+
+import (
+	"context"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// CreateCategory saves the category and sets its ID, the parent must exist
+func (s *Store) CreateCategory(_ context.Context, c *models.Category) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if c.ParentID > 0 {
+		if _, ok := s.categories[c.ParentID]; !ok {
+			return models.ErrCategoryParentNotFound
+		}
+		if s.depth(c.ParentID) >= models.MaxCategoryDepth {
+			return models.ErrCategoryTooDeep
+		}
+	}
+	s.lastCategoryID++
+	c.ID = s.lastCategoryID
+	s.categories[c.ID] = *c
+	return nil
+}
+
+// GetCategoryByID retrieves a category by ID, returns models.ErrCategoryNotFound if it does not exist
+func (s *Store) GetCategoryByID(_ context.Context, id int) (models.Category, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	category, ok := s.categories[id]
+	if !ok {
+		return models.Category{}, models.ErrCategoryNotFound
+	}
+	return category, nil
+}
+
+// UpdateCategory applies the update to the category and returns the updated category, its
+// descendants follow it when it moves
+func (s *Store) UpdateCategory(_ context.Context, id int, update models.CategoryUpdate) (models.Category, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	category, ok := s.categories[id]
+	if !ok {
+		return models.Category{}, models.ErrCategoryNotFound
+	}
+	update.Apply(&category)
+	if parent := category.ParentID; parent > 0 {
+		if _, ok := s.categories[parent]; !ok {
+			return models.Category{}, models.ErrCategoryParentNotFound
+		}
+		if s.inCategory(parent, id) {
+			return models.Category{}, models.ErrCategoryCycle
+		}
+		if s.depth(parent)+s.height(id) > models.MaxCategoryDepth {
+			return models.Category{}, models.ErrCategoryTooDeep
+		}
+	}
+	s.categories[id] = category
+	return category, nil
+}
+
+// DeleteCategory deletes the category, it must have no subcategories nor products
+func (s *Store) DeleteCategory(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.categories[id]; !ok {
+		return models.ErrCategoryNotFound
+	}
+	for _, c := range s.categories {
+		if c.ParentID == id {
+			return models.ErrCategoryNotEmpty
+		}
+	}
+	for _, p := range s.products {
+		if p.CategoryID == id {
+			return models.ErrCategoryNotEmpty
+		}
+	}
+	delete(s.categories, id)
+	return nil
+}
+
+// CategoryTree returns the roots of the category tree with the product counts of each node
+func (s *Store) CategoryTree(_ context.Context) ([]*models.CategoryNode, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	categories := make([]models.Category, 0, len(s.categories))
+	for _, c := range s.categories {
+		categories = append(categories, c)
+	}
+	counts := make(map[int]int)
+	for _, p := range s.products {
+		if p.CategoryID > 0 {
+			counts[p.CategoryID]++
+		}
+	}
+	return models.CategoryTree(categories, counts), nil
+}
+
+// inCategory tells whether the category id is the ancestor category or one of its descendants. Callers hold the lock
+func (s *Store) inCategory(id, ancestor int) bool {
+	for id > 0 {
+		if id == ancestor {
+			return true
+		}
+		id = s.categories[id].ParentID
+	}
+	return false
+}
+
+// depth is the level of the category in the tree, 1 for the roots. Callers hold the lock
+func (s *Store) depth(id int) int {
+	depth := 0
+	for ; id > 0; id = s.categories[id].ParentID {
+		depth++
+	}
+	return depth
+}
+
+// height is the number of levels of the subtree of the category, 1 for a leaf. Callers hold the lock
+func (s *Store) height(id int) int {
+	height := 1
+	for _, c := range s.categories {
+		if c.ParentID == id {
+			if h := s.height(c.ID) + 1; h > height {
+				height = h
+			}
+		}
+	}
+	return height
+}
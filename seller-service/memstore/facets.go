@@ -0,0 +1,144 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// FacetProducts counts the products matching the request by each of req.Facets, the counts of
+// a facet ignore its own filter
+func (s *Store) FacetProducts(_ context.Context, req *models.ProductRequest) (models.Facets, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	facets := make(models.Facets, len(req.Facets))
+	for _, facet := range req.Facets {
+		products := s.filterProducts(req.WithoutFacetFilter(facet))
+		switch facet {
+		case models.FacetLocation:
+			facets[facet] = s.locationBuckets(products)
+		case models.FacetPriceRange:
+			facets[facet] = priceRangeBuckets(products)
+		case models.FacetSellerID:
+			facets[facet] = s.sellerBuckets(products)
+		}
+	}
+	return facets, nil
+}
+
+// locationBuckets groups the products by the location of their seller, locations differing
+// only in case are one bucket as under the mysql collation
+func (s *Store) locationBuckets(products []models.Product) []models.FacetBucket {
+	index := make(map[string]int)
+	var buckets []models.FacetBucket
+	for _, p := range products {
+		location := s.sellers[p.SellerID].Location
+		key := strings.ToLower(location)
+		i, ok := index[key]
+		if !ok {
+			i = len(buckets)
+			index[key] = i
+			buckets = append(buckets, models.FacetBucket{Value: location})
+		}
+		buckets[i].Count++
+	}
+	sort.SliceStable(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return strings.ToLower(buckets[i].Value) < strings.ToLower(buckets[j].Value)
+	})
+	return top(buckets)
+}
+
+// sellerBuckets groups the products by seller, labelled with the seller name
+func (s *Store) sellerBuckets(products []models.Product) []models.FacetBucket {
+	counts := make(map[int]int)
+	for _, p := range products {
+		counts[p.SellerID]++
+	}
+	ids := make([]int, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if counts[ids[i]] != counts[ids[j]] {
+			return counts[ids[i]] > counts[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+	buckets := make([]models.FacetBucket, len(ids))
+	for i, id := range ids {
+		buckets[i] = models.FacetBucket{Value: strconv.Itoa(id), Count: counts[id], Label: s.sellers[id].Name}
+	}
+	return top(buckets)
+}
+
+// priceRangeBuckets counts the products in each band of models.PriceRanges, empty bands included
+func priceRangeBuckets(products []models.Product) []models.FacetBucket {
+	counts := make([]int, len(models.PriceRanges))
+	for _, p := range products {
+		counts[models.PriceRangeOf(p.Price)]++
+	}
+	buckets := make([]models.FacetBucket, len(models.PriceRanges))
+	for i, r := range models.PriceRanges {
+		buckets[i] = r.Bucket(counts[i])
+	}
+	return buckets
+}
+
+// top keeps the first models.MaxFacetBuckets buckets
+func top(buckets []models.FacetBucket) []models.FacetBucket {
+	if buckets == nil {
+		return []models.FacetBucket{}
+	}
+	if len(buckets) > models.MaxFacetBuckets {
+		return buckets[:models.MaxFacetBuckets]
+	}
+	return buckets
+}
+
+// ListAttributes counts the products having each value of each attribute
+func (s *Store) ListAttributes(_ context.Context) ([]models.AttributeSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	counts := make(map[string]map[string]int)
+	for _, p := range s.products {
+		for name, value := range p.Attributes {
+			if counts[name] == nil {
+				counts[name] = make(map[string]int)
+			}
+			counts[name][value]++
+		}
+	}
+	return models.SummarizeAttributes(counts), nil
+}
+
+// CountProductNames counts the products of each name, names differing only in case are
+// counted under the name of the first product created with one of them
+func (s *Store) CountProductNames(_ context.Context) (map[string]int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	ids := make([]int, 0, len(s.products))
+	for id := range s.products {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	names := make(map[string]string)
+	counts := make(map[string]int)
+	for _, id := range ids {
+		name := s.products[id].ProductName
+		key := strings.ToLower(name)
+		if _, ok := names[key]; !ok {
+			names[key] = name
+		}
+		counts[names[key]]++
+	}
+	return counts, nil
+}
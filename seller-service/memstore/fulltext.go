@@ -0,0 +1,42 @@
+package memstore
+
+import (
+	"math"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// scoreProducts returns the relevance of the products matching every term of the full-text
+// query, the products missing from the result do not match. As with the InnoDB ranking a
+// term weighs more the fewer products hold it and the name and description count alike, the
+// scores still differ from those of mysql and only approximate its order
+func scoreProducts(query models.TextQuery, products map[int]models.Product) map[int]float64 {
+	type tokens struct{ name, description []string }
+	tokenized := make(map[int]tokens, len(products))
+	for id, p := range products {
+		tokenized[id] = tokens{models.Tokenize(p.ProductName), models.Tokenize(p.Description)}
+	}
+
+	scores := make(map[int]float64)
+	for id := range products {
+		scores[id] = 0
+	}
+	for _, term := range query {
+		counts := make(map[int]int)
+		for id, t := range tokenized {
+			if n := term.Matches(t.name) + term.Matches(t.description); n > 0 {
+				counts[id] = n
+			}
+		}
+		idf := math.Log(1 + float64(len(products))/float64(len(counts)+1))
+		for id := range scores {
+			n, ok := counts[id]
+			if !ok {
+				delete(scores, id)
+				continue
+			}
+			scores[id] += float64(n) * idf
+		}
+	}
+	return scores
+}
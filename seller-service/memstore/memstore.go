@@ -0,0 +1,358 @@
+// Package memstore - in-memory implementation of the product, seller, category, search log,
+// synonym, saved search, buyer, cart and order stores.
+//
+// It mirrors the behaviour of the mysql store (filtering, sorting, pagination and
+// foreign key checks) so that the service and its tests can run without a database. The
+// full-text relevance is scored in memory and only approximates the order of the mysql
+// FULLTEXT ranking.
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// Store is a thread-safe in-memory implementation of models.ProductStore, models.SellerStore,
+// models.CategoryStore, models.SearchLogStore, models.SynonymStore, models.SavedSearchStore,
+// models.BuyerStore, models.CartStore and models.OrderStore
+type Store struct {
+	mu                 sync.RWMutex
+	sellers            map[int]models.Seller
+	products           map[int]models.Product
+	categories         map[int]models.Category
+	searches           []models.SearchEvent
+	synonymGroups      map[int]models.SynonymGroup
+	savedSearches      map[int]models.SavedSearch
+	buyers             map[int]models.Buyer
+	buyerAddresses     map[int]models.BuyerAddress
+	cartItems          map[cartKey]models.CartItem
+	orders             map[int]models.Order
+	lastSellerID       int
+	lastProductID      int
+	lastCategoryID     int
+	lastSynonymID      int
+	lastSavedSearchID  int
+	lastBuyerID        int
+	lastBuyerAddressID int
+	lastOrderID        int
+}
+
+// New creates an empty Store
+func New() *Store {
+	return &Store{
+		sellers:        make(map[int]models.Seller),
+		products:       make(map[int]models.Product),
+		categories:     make(map[int]models.Category),
+		synonymGroups:  make(map[int]models.SynonymGroup),
+		savedSearches:  make(map[int]models.SavedSearch),
+		buyers:         make(map[int]models.Buyer),
+		buyerAddresses: make(map[int]models.BuyerAddress),
+		cartItems:      make(map[cartKey]models.CartItem),
+		orders:         make(map[int]models.Order),
+	}
+}
+
+// CreateProduct saves the product and sets its ID, the seller and category must exist
+func (s *Store) CreateProduct(_ context.Context, p *models.Product) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sellers[p.SellerID]; !ok {
+		return models.ErrSellerNotFound
+	}
+	if _, ok := s.categories[p.CategoryID]; p.CategoryID > 0 && !ok {
+		return models.ErrCategoryNotFound
+	}
+	s.lastProductID++
+	p.ID = s.lastProductID
+	p.Score, p.Distance, p.Seller = 0, nil, nil
+	p.Attributes, p.Tags = models.NormalizeAttributes(p.Attributes, p.Tags)
+	s.products[p.ID] = *p
+	return nil
+}
+
+// GetProductByID retrieves a product by ID, returns models.ErrProductNotFound if it does not exist
+func (s *Store) GetProductByID(_ context.Context, id int) (models.Product, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	product, ok := s.products[id]
+	if !ok {
+		return models.Product{}, models.ErrProductNotFound
+	}
+	return product, nil
+}
+
+// UpdateProduct applies the update to the product and returns the updated product
+func (s *Store) UpdateProduct(_ context.Context, id int, update models.ProductUpdate) (models.Product, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, ok := s.products[id]
+	if !ok {
+		return models.Product{}, models.ErrProductNotFound
+	}
+	if update.CategoryID != nil && *update.CategoryID > 0 {
+		if _, ok := s.categories[*update.CategoryID]; !ok {
+			return models.Product{}, models.ErrCategoryNotFound
+		}
+	}
+	update.Apply(&product)
+	s.products[id] = product
+	return product, nil
+}
+
+// DeleteProduct deletes the product and takes it out of the carts, returns
+// models.ErrProductNotFound if it does not exist
+func (s *Store) DeleteProduct(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.products[id]; !ok {
+		return models.ErrProductNotFound
+	}
+	delete(s.products, id)
+	s.deleteCartItems(func(key cartKey) bool { return key.productID == id })
+	return nil
+}
+
+// SearchProducts filters, sorts and paginates the products the same way the mysql store does
+func (s *Store) SearchProducts(_ context.Context, req *models.ProductRequest) (models.ProductPage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	products := s.filterProducts(req)
+	order := req.SortOrder()
+	sort.Slice(products, func(i, j int) bool {
+		return models.CompareProducts(products[i], products[j], order) < 0
+	})
+
+	if req.Cursor == nil {
+		page := paginate(products, req.Offset(), req.PerPage+1)
+		return pageOf(page, req.PerPage, false), nil
+	}
+	after := req.Cursor.Product()
+	if req.Cursor.Backward {
+		// walk backwards from the cursor, the page is put back in order by pageOf
+		var before []models.Product
+		for i := len(products) - 1; i >= 0 && uint64(len(before)) <= req.PerPage; i-- {
+			if models.CompareProducts(products[i], after, order) < 0 {
+				before = append(before, products[i])
+			}
+		}
+		return pageOf(before, req.PerPage, true), nil
+	}
+	start := sort.Search(len(products), func(i int) bool {
+		return models.CompareProducts(products[i], after, order) > 0
+	})
+	return pageOf(paginate(products[start:], 0, req.PerPage+1), req.PerPage, false), nil
+}
+
+// CountProducts returns the number of products matching the filters of the request
+func (s *Store) CountProducts(_ context.Context, req *models.ProductRequest) (int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return len(s.filterProducts(req)), nil
+}
+
+// filterProducts returns the products matching the filters of the request, with their
+// seller, their relevance when it has a full-text query and their distance when it has a
+// near point. Callers hold the lock
+func (s *Store) filterProducts(req *models.ProductRequest) []models.Product {
+	var scores map[int]float64
+	if req.Query != "" {
+		scores = scoreProducts(req.TextQuery(), s.products)
+	}
+	var products []models.Product
+	for _, p := range s.products {
+		seller := s.sellers[p.SellerID]
+		if !s.matches(req, p, seller) {
+			continue
+		}
+		p.Seller = &seller
+		if req.Near != nil {
+			point, ok := seller.Address.Point()
+			if !ok {
+				continue
+			}
+			distance := models.DistanceKm(*req.Near, point)
+			if req.RadiusKm > 0 && distance > req.RadiusKm {
+				continue
+			}
+			p.Distance = &distance
+		}
+		if scores != nil {
+			score, ok := scores[p.ID]
+			if !ok {
+				continue
+			}
+			p.Score = score
+		}
+		products = append(products, p)
+	}
+	return products
+}
+
+// pageOf trims the perPage+1 products read for a page to perPage, the extra one telling
+// whether there are more. Products read backwards are reversed into the sort order
+func pageOf(products []models.Product, perPage uint64, backward bool) models.ProductPage {
+	page := models.ProductPage{Products: products}
+	if uint64(len(products)) > perPage {
+		page.Products, page.HasMore = products[:perPage], true
+	}
+	if backward {
+		for i, j := 0, len(page.Products)-1; i < j; i, j = i+1, j-1 {
+			page.Products[i], page.Products[j] = page.Products[j], page.Products[i]
+		}
+	}
+	return page
+}
+
+// CreateSeller saves the seller and sets its ID
+func (s *Store) CreateSeller(_ context.Context, seller *models.Seller) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSellerID++
+	seller.ID = s.lastSellerID
+	seller.Normalize()
+	s.sellers[seller.ID] = *seller
+	return nil
+}
+
+// GetSellerByID retrieves a seller by ID, returns models.ErrSellerNotFound if it does not exist
+func (s *Store) GetSellerByID(_ context.Context, id int) (models.Seller, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	seller, ok := s.sellers[id]
+	if !ok {
+		return models.Seller{}, models.ErrSellerNotFound
+	}
+	return seller, nil
+}
+
+// ListSellers returns the page of sellers matching the filter, ordered by ID
+func (s *Store) ListSellers(_ context.Context, filter models.SellerFilter) ([]models.Seller, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var sellers []models.Seller
+	for _, seller := range s.sellers {
+		if filter.Location != "" && !strings.EqualFold(seller.Location, filter.Location) {
+			continue
+		}
+		if len(filter.Countries) > 0 && !seller.MatchesCountry(filter.Countries) {
+			continue
+		}
+		sellers = append(sellers, seller)
+	}
+	sort.Slice(sellers, func(i, j int) bool { return sellers[i].ID < sellers[j].ID })
+	return paginate(sellers, filter.Offset(), filter.PerPage), nil
+}
+
+// UpdateSeller applies the update to the seller and returns the updated seller
+func (s *Store) UpdateSeller(_ context.Context, id int, update models.SellerUpdate) (models.Seller, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seller, ok := s.sellers[id]
+	if !ok {
+		return models.Seller{}, models.ErrSellerNotFound
+	}
+	update.Apply(&seller)
+	s.sellers[id] = seller
+	return seller, nil
+}
+
+// DeleteSeller deletes the seller, and its products when cascade is set
+func (s *Store) DeleteSeller(_ context.Context, id int, cascade bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sellers[id]; !ok {
+		return models.ErrSellerNotFound
+	}
+	var products []int
+	for _, p := range s.products {
+		if p.SellerID == id {
+			products = append(products, p.ID)
+		}
+	}
+	if len(products) > 0 && !cascade {
+		return models.ErrSellerHasProducts
+	}
+	for _, productID := range products {
+		delete(s.products, productID)
+		s.deleteCartItems(func(key cartKey) bool { return key.productID == productID })
+	}
+	delete(s.sellers, id)
+	return nil
+}
+
+// matches applies the filters of the request to a product and its seller. Callers hold the lock
+func (s *Store) matches(req *models.ProductRequest, p models.Product, seller models.Seller) bool {
+	if req.SellerID > 0 && p.SellerID != req.SellerID {
+		return false
+	}
+	if req.ProductName != "" && !containsAnyFold(p.ProductName, req.ProductNames()) {
+		return false
+	}
+	if req.DesiredQty > 0 && p.Quantity < req.DesiredQty {
+		return false
+	}
+	if req.Location != "" && !strings.EqualFold(seller.Location, req.Location) {
+		return false
+	}
+	if len(req.Countries) > 0 && !seller.MatchesCountry(req.Countries) {
+		return false
+	}
+	if req.MinPrice > 0 && p.Price < req.MinPrice {
+		return false
+	}
+	if req.MaxPrice > 0 && p.Price > req.MaxPrice {
+		return false
+	}
+	if req.CategoryID > 0 && !s.inCategory(p.CategoryID, req.CategoryID) {
+		return false
+	}
+	if !models.MatchesAttributes(p, req.Attributes, req.Tags) {
+		return false
+	}
+	if req.Filter != nil && !req.Filter.Matches(p, seller) {
+		return false
+	}
+	return true
+}
+
+// containsFold is the equivalent of LIKE '%substr%' under mysql's case-insensitive collation
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// containsAnyFold tells whether s contains one of the substrings, without regard to case
+func containsAnyFold(s string, substrs []string) bool {
+	for _, substr := range substrs {
+		if containsFold(s, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// paginate is the equivalent of LIMIT perPage OFFSET offset
+func paginate[T any](items []T, offset, perPage uint64) []T {
+	if offset >= uint64(len(items)) {
+		return nil
+	}
+	end := offset + perPage
+	if end > uint64(len(items)) {
+		end = uint64(len(items))
+	}
+	return items[offset:end]
+}
@@ -0,0 +1,486 @@
+package memstore
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+func newSeededStore(t *testing.T) *Store {
+	t.Helper()
+	ctx := context.Background()
+	store := New()
+	for _, seller := range []models.Seller{
+		{Name: "Seller A", Location: "IND"},
+		{Name: "Seller B", Location: "US"},
+	} {
+		seller := seller
+		if err := store.CreateSeller(ctx, &seller); err != nil {
+			t.Fatalf("CreateSeller: %v", err)
+		}
+	}
+	for _, product := range []models.Product{
+		{SellerID: 1, ProductName: "Smartphone", Price: 30, Quantity: 1},
+		{SellerID: 2, ProductName: "laptop", Price: 10, Quantity: 5},
+		{SellerID: 1, ProductName: "Smartwatch", Price: 20, Quantity: 3},
+		{SellerID: 2, ProductName: "Tablet", Price: 20, Quantity: 2},
+	} {
+		product := product
+		if err := store.CreateProduct(ctx, &product); err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+	}
+	return store
+}
+
+func productIDs(products []models.Product) []int {
+	ids := make([]int, 0, len(products))
+	for _, p := range products {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestCreateProduct_UnknownSeller(t *testing.T) {
+	store := New()
+	err := store.CreateProduct(context.Background(), &models.Product{SellerID: 42, ProductName: "Drone"})
+	if !errors.Is(err, models.ErrSellerNotFound) {
+		t.Errorf("Expected ErrSellerNotFound, got %v", err)
+	}
+}
+
+func TestCreateProduct_SearchFieldsNotSaved(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+	distance := 3.0
+	product := models.Product{SellerID: 1, ProductName: "Drone", Price: 10, Quantity: 1,
+		Score: 9, Distance: &distance, Seller: &models.Seller{Name: "FAKE"}}
+	if err := store.CreateProduct(ctx, &product); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	if product.Score != 0 || product.Distance != nil || product.Seller != nil {
+		t.Errorf("Expected the fields of the searches to be cleared, got %+v", product)
+	}
+	got, err := store.GetProductByID(ctx, product.ID)
+	if err != nil {
+		t.Fatalf("GetProductByID: %v", err)
+	}
+	if got.Score != 0 || got.Distance != nil || got.Seller != nil {
+		t.Errorf("Expected the fields of the searches not to be saved, got %+v", got)
+	}
+}
+
+func TestGetSellerByID(t *testing.T) {
+	store := newSeededStore(t)
+	seller, err := store.GetSellerByID(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("GetSellerByID: %v", err)
+	}
+	if seller.Name != "Seller B" || seller.Location != "US" {
+		t.Errorf("Unexpected seller: %+v", seller)
+	}
+	if _, err := store.GetSellerByID(context.Background(), 3); !errors.Is(err, models.ErrSellerNotFound) {
+		t.Errorf("Expected ErrSellerNotFound, got %v", err)
+	}
+}
+
+func TestSearchProducts(t *testing.T) {
+	store := newSeededStore(t)
+	tests := []struct {
+		name string
+		req  models.ProductRequest
+		want []int
+	}{
+		{"no filters", models.ProductRequest{Page: 1, PerPage: 10}, []int{1, 2, 3, 4}},
+		{"name is case insensitive", models.ProductRequest{ProductName: "SMART", Page: 1, PerPage: 10}, []int{1, 3}},
+		{"location", models.ProductRequest{Location: "us", Page: 1, PerPage: 10}, []int{2, 4}},
+		{"desired quantity", models.ProductRequest{DesiredQty: 3, Page: 1, PerPage: 10}, []int{2, 3}},
+		{"price range", models.ProductRequest{MinPrice: 15, MaxPrice: 25, Page: 1, PerPage: 10}, []int{3, 4}},
+		{"sort by price keeps id order on ties", models.ProductRequest{SortBy: "price", Page: 1, PerPage: 10}, []int{2, 3, 4, 1}},
+		{"sort by name", models.ProductRequest{SortBy: "productName", Page: 1, PerPage: 10}, []int{2, 1, 3, 4}},
+		{"sort by seller", models.ProductRequest{SortBy: "sellerId", Page: 1, PerPage: 10}, []int{1, 3, 2, 4}},
+		{"second page", models.ProductRequest{SortBy: "price", Page: 2, PerPage: 3}, []int{1}},
+		{"page past the end", models.ProductRequest{Page: 3, PerPage: 3}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := store.SearchProducts(context.Background(), &tt.req)
+			if err != nil {
+				t.Fatalf("SearchProducts: %v", err)
+			}
+			ids := productIDs(got.Products)
+			if len(ids) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, ids)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, ids)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchProducts_Cursor(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+	for _, sortBy := range []string{"", "price", "productName", "sellerId", "productId", "-price", "-price,productName", "location,-sellerName", "-productId"} {
+		t.Run(sortBy, func(t *testing.T) {
+			all, _ := store.SearchProducts(ctx, &models.ProductRequest{SortBy: sortBy, Page: 1, PerPage: 10})
+			want := productIDs(all.Products)
+
+			// walk forwards one product at a time, then backwards from the last one
+			var forward []int
+			req := models.ProductRequest{SortBy: sortBy, Page: 1, PerPage: 1}
+			for {
+				page, err := store.SearchProducts(ctx, &req)
+				if err != nil {
+					t.Fatalf("SearchProducts: %v", err)
+				}
+				forward = append(forward, productIDs(page.Products)...)
+				if !page.HasMore {
+					break
+				}
+				cursor := models.CursorAt(page.Products[0], sortBy, false)
+				req.Cursor = &cursor
+			}
+			if !reflect.DeepEqual(forward, want) {
+				t.Fatalf("Expected %v walking forwards, got %v", want, forward)
+			}
+
+			cursor := models.CursorAt(all.Products[len(all.Products)-1], sortBy, true)
+			page, _ := store.SearchProducts(ctx, &models.ProductRequest{SortBy: sortBy, PerPage: 2, Cursor: &cursor})
+			if got := productIDs(page.Products); !reflect.DeepEqual(got, want[1:3]) || !page.HasMore {
+				t.Errorf("Expected %v and more before them, got %v %v", want[1:3], got, page.HasMore)
+			}
+		})
+	}
+}
+
+func TestSearchProducts_SortOrder(t *testing.T) {
+	store := newSeededStore(t)
+	tests := []struct {
+		sortBy string
+		want   []int
+	}{
+		{"-price,productName", []int{1, 3, 4, 2}},
+		{"-location,price", []int{2, 4, 3, 1}},
+		{"sellerName,-productId", []int{3, 1, 4, 2}},
+		{"-price", []int{1, 3, 4, 2}},
+	}
+	for _, tt := range tests {
+		page, err := store.SearchProducts(context.Background(), &models.ProductRequest{SortBy: tt.sortBy, Page: 1, PerPage: 10})
+		if got := productIDs(page.Products); err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sortBy=%s: got %v, %v, want %v", tt.sortBy, got, err, tt.want)
+		}
+	}
+}
+
+func TestCountProducts(t *testing.T) {
+	store := newSeededStore(t)
+	count, err := store.CountProducts(context.Background(), &models.ProductRequest{MinPrice: 20, Page: 1, PerPage: 1})
+	if err != nil || count != 3 {
+		t.Errorf("CountProducts = %d, %v, want 3", count, err)
+	}
+}
+
+func TestUpdateAndDeleteProduct(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+	price := 99.5
+	updated, err := store.UpdateProduct(ctx, 2, models.ProductUpdate{Price: &price})
+	if err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	if updated.Price != price || updated.ProductName != "laptop" {
+		t.Errorf("Unexpected product after update: %+v", updated)
+	}
+	if got, _ := store.GetProductByID(ctx, 2); !reflect.DeepEqual(got, updated) {
+		t.Errorf("Expected the update to be stored, got %+v", got)
+	}
+
+	if err := store.DeleteProduct(ctx, 2); err != nil {
+		t.Fatalf("DeleteProduct: %v", err)
+	}
+	if _, err := store.GetProductByID(ctx, 2); !errors.Is(err, models.ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound after delete, got %v", err)
+	}
+	if _, err := store.UpdateProduct(ctx, 2, models.ProductUpdate{Price: &price}); !errors.Is(err, models.ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound updating a deleted product, got %v", err)
+	}
+	if err := store.DeleteProduct(ctx, 2); !errors.Is(err, models.ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestListSellers(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+	sellers, err := store.ListSellers(ctx, models.SellerFilter{Location: "us", Page: 1, PerPage: 10})
+	if err != nil || len(sellers) != 1 || sellers[0].ID != 2 {
+		t.Errorf("ListSellers(us) = %+v, %v", sellers, err)
+	}
+	sellers, err = store.ListSellers(ctx, models.SellerFilter{Page: 2, PerPage: 1})
+	if err != nil || len(sellers) != 1 || sellers[0].ID != 2 {
+		t.Errorf("ListSellers(page 2) = %+v, %v", sellers, err)
+	}
+}
+
+func TestUpdateAndDeleteSeller(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+	name := "Renamed"
+	updated, err := store.UpdateSeller(ctx, 1, models.SellerUpdate{Name: &name})
+	if err != nil || updated.Name != name || updated.Location != "IND" {
+		t.Fatalf("UpdateSeller = %+v, %v", updated, err)
+	}
+
+	if err := store.DeleteSeller(ctx, 1, false); !errors.Is(err, models.ErrSellerHasProducts) {
+		t.Fatalf("Expected ErrSellerHasProducts, got %v", err)
+	}
+	if err := store.DeleteSeller(ctx, 1, true); err != nil {
+		t.Fatalf("DeleteSeller with cascade: %v", err)
+	}
+	if _, err := store.GetSellerByID(ctx, 1); !errors.Is(err, models.ErrSellerNotFound) {
+		t.Errorf("Expected ErrSellerNotFound after delete, got %v", err)
+	}
+	page, _ := store.SearchProducts(ctx, &models.ProductRequest{Page: 1, PerPage: 10})
+	if ids := productIDs(page.Products); len(ids) != 2 || ids[0] != 2 || ids[1] != 4 {
+		t.Errorf("Expected only the products of seller 2 to remain, got %v", ids)
+	}
+	if err := store.DeleteSeller(ctx, 1, true); !errors.Is(err, models.ErrSellerNotFound) {
+		t.Errorf("Expected ErrSellerNotFound deleting twice, got %v", err)
+	}
+}
+
+func TestSearchProducts_FullText(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+	for _, product := range []models.Product{
+		{SellerID: 1, ProductName: "Mouse, Wireless", Price: 15, Quantity: 1, Description: "Ergonomic"},            // 5
+		{SellerID: 1, ProductName: "Keyboard", Price: 25, Quantity: 1, Description: "Wireless keyboard and mouse"}, // 6
+		{SellerID: 2, ProductName: "Wired Mouse", Price: 5, Quantity: 1},                                           // 7
+		{SellerID: 2, ProductName: "Mouse pad", Price: 3, Quantity: 1, Description: "For any wireless mouse"},      // 8
+	} {
+		product := product
+		if err := store.CreateProduct(ctx, &product); err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+	}
+	tests := []struct {
+		name string
+		req  models.ProductRequest
+		want []int
+	}{
+		{"words in any order", models.ProductRequest{Query: "wireless mouse"}, []int{5, 6, 8}},
+		{"punctuation and case", models.ProductRequest{Query: "MOUSE, wireless"}, []int{5, 6, 8}},
+		{"prefix", models.ProductRequest{Query: "wire*"}, []int{5, 6, 7, 8}},
+		{"phrase", models.ProductRequest{Query: `"wireless mouse"`}, []int{8}},
+		{"other filters apply", models.ProductRequest{Query: "mouse", MaxPrice: 10}, []int{7, 8}},
+		{"more matches rank first", models.ProductRequest{Query: "wireless mouse", SortBy: "relevance"}, []int{8, 5, 6}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.req.Page, tt.req.PerPage = 1, 10
+			page, err := store.SearchProducts(ctx, &tt.req)
+			if err != nil {
+				t.Fatalf("SearchProducts: %v", err)
+			}
+			if got := productIDs(page.Products); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			for _, p := range page.Products {
+				if p.Score <= 0 {
+					t.Errorf("Expected a relevance score, got %+v", p)
+				}
+			}
+		})
+	}
+
+	if count, _ := store.CountProducts(ctx, &models.ProductRequest{Query: "wire*"}); count != 4 {
+		t.Errorf("Expected 4 products to match wire*, got %d", count)
+	}
+	if got, _ := store.GetProductByID(ctx, 5); got.Score != 0 {
+		t.Errorf("Expected scores not to be stored, got %+v", got)
+	}
+}
+
+func TestFacetProducts(t *testing.T) {
+	store := newSeededStore(t)
+	facets, err := store.FacetProducts(context.Background(), &models.ProductRequest{
+		Location: "ind",
+		MinPrice: 15,
+		Facets:   []string{models.FacetLocation, models.FacetPriceRange, models.FacetSellerID},
+	})
+	if err != nil {
+		t.Fatalf("FacetProducts: %v", err)
+	}
+
+	// every facet ignores its own filter but applies the others
+	if got, want := facets[models.FacetLocation], []models.FacetBucket{{Value: "IND", Count: 2}, {Value: "US", Count: 1}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected location buckets %+v, got %+v", want, got)
+	}
+	if got, want := facets[models.FacetSellerID], []models.FacetBucket{{Value: "1", Count: 2, Label: "Seller A"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected seller buckets %+v, got %+v", want, got)
+	}
+	var counts []int
+	for _, b := range facets[models.FacetPriceRange] {
+		counts = append(counts, b.Count)
+	}
+	if want := []int{0, 2, 0, 0, 0}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("Expected price range counts %v, got %v", want, counts)
+	}
+}
+
+func TestCategories(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+
+	// a chain as deep as the tree allows
+	var chain []models.Category
+	for i := 0; i < models.MaxCategoryDepth; i++ {
+		category := models.Category{Name: "Level"}
+		if i > 0 {
+			category.ParentID = chain[i-1].ID
+		}
+		if err := store.CreateCategory(ctx, &category); err != nil {
+			t.Fatalf("CreateCategory at depth %d: %v", i+1, err)
+		}
+		chain = append(chain, category)
+	}
+	last := chain[len(chain)-1]
+	if err := store.CreateCategory(ctx, &models.Category{Name: "Too deep", ParentID: last.ID}); !errors.Is(err, models.ErrCategoryTooDeep) {
+		t.Errorf("Expected ErrCategoryTooDeep, got %v", err)
+	}
+	other := models.Category{Name: "Other"}
+	if err := store.CreateCategory(ctx, &other); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	parent := chain[1].ID
+	if _, err := store.UpdateCategory(ctx, other.ID, models.CategoryUpdate{ParentID: &parent}); err != nil {
+		t.Errorf("Expected a leaf to move under the chain, got %v", err)
+	}
+	if _, err := store.UpdateCategory(ctx, chain[1].ID, models.CategoryUpdate{ParentID: &other.ID}); !errors.Is(err, models.ErrCategoryCycle) {
+		t.Errorf("Expected ErrCategoryCycle, got %v", err)
+	}
+	if _, err := store.UpdateCategory(ctx, chain[1].ID, models.CategoryUpdate{ParentID: &chain[0].ID}); err != nil {
+		t.Errorf("Expected the chain to stay under the same parent, got %v", err)
+	}
+	root := models.Category{Name: "Root"}
+	if err := store.CreateCategory(ctx, &root); err != nil {
+		t.Fatalf("CreateCategory: %v", err)
+	}
+	if _, err := store.UpdateCategory(ctx, chain[0].ID, models.CategoryUpdate{ParentID: &root.ID}); !errors.Is(err, models.ErrCategoryTooDeep) {
+		t.Errorf("Expected moving the whole chain one level down to fail, got %v", err)
+	}
+
+	product := models.Product{SellerID: 1, ProductName: "Deep", Price: 1, Quantity: 1, CategoryID: last.ID}
+	if err := store.CreateProduct(ctx, &product); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	if err := store.CreateProduct(ctx, &models.Product{SellerID: 1, ProductName: "X", Price: 1, Quantity: 1, CategoryID: 999}); !errors.Is(err, models.ErrCategoryNotFound) {
+		t.Errorf("Expected ErrCategoryNotFound, got %v", err)
+	}
+	tree, err := store.CategoryTree(ctx)
+	if err != nil || len(tree) != 2 || tree[0].ID != chain[0].ID || tree[0].ProductCount != 1 || tree[1].ProductCount != 0 {
+		t.Errorf("Unexpected tree %+v, %v", tree, err)
+	}
+	if err := store.DeleteCategory(ctx, last.ID); !errors.Is(err, models.ErrCategoryNotEmpty) {
+		t.Errorf("Expected ErrCategoryNotEmpty, got %v", err)
+	}
+}
+
+func TestSearchProducts_Near(t *testing.T) {
+	store := newSeededStore(t)
+	ctx := context.Background()
+	for _, s := range []struct {
+		city     string
+		lat, lng float64
+	}{{"Mysuru", 12.2958, 76.6394}, {"Chennai", 13.0827, 80.2707}, {"Bengaluru", 12.9716, 77.5946}} {
+		lat, lng := s.lat, s.lng
+		seller := models.Seller{Name: s.city, Location: s.city, Address: &models.Address{Country: "IN", Latitude: &lat, Longitude: &lng}}
+		if err := store.CreateSeller(ctx, &seller); err != nil {
+			t.Fatalf("CreateSeller: %v", err)
+		}
+		if err := store.CreateProduct(ctx, &models.Product{SellerID: seller.ID, ProductName: "Lamp", Price: 20, Quantity: 1}); err != nil {
+			t.Fatalf("CreateProduct: %v", err)
+		}
+	}
+
+	// the seeded sellers have no coordinates and are left out
+	req := &models.ProductRequest{Near: &models.GeoPoint{Lat: 12.9716, Lng: 77.5946}, SortBy: "distance", Page: 1, PerPage: 2}
+	page, err := store.SearchProducts(ctx, req)
+	if got := productIDs(page.Products); err != nil || !reflect.DeepEqual(got, []int{7, 5}) || !page.HasMore {
+		t.Fatalf("Expected Bengaluru then Mysuru and more, got %v %v", got, err)
+	}
+	cursor := models.CursorAt(page.Products[1], req.SortBy, false)
+	req.Cursor = &cursor
+	if page, _ := store.SearchProducts(ctx, req); !reflect.DeepEqual(productIDs(page.Products), []int{6}) || page.HasMore {
+		t.Errorf("Expected Chennai after the cursor, got %v", productIDs(page.Products))
+	}
+
+	req = &models.ProductRequest{Near: req.Near, RadiusKm: 200, Countries: []string{"in"}, Page: 1, PerPage: 10}
+	if count, err := store.CountProducts(ctx, req); err != nil || count != 2 {
+		t.Errorf("Expected 2 products within 200km, got %d %v", count, err)
+	}
+}
+
+func TestSearchReports(t *testing.T) {
+	ctx := context.Background()
+	store := New()
+	now := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+	event := func(query string, results int, firstPage bool, ago time.Duration, filters ...string) models.SearchEvent {
+		return models.SearchEvent{At: now.Add(-ago), Query: query, Filters: filters, Results: results, FirstPage: firstPage, Latency: 2 * time.Millisecond}
+	}
+	err := store.RecordSearches(ctx, []models.SearchEvent{
+		event("drone", 3, true, time.Hour, "q"),
+		event("drone", 0, false, 2*time.Hour, "q"),
+		event("drone", 1, true, 3*time.Hour, "maxPrice", "q"),
+		event("flux capacitor", 0, true, time.Hour, "productName"),
+		event("flux capacitor", 0, true, 2*time.Hour, "productName"),
+		event("camera", 0, true, time.Minute, "q"),
+		event("", 5, true, time.Minute),
+		// outside of the window
+		event("tablet", 0, true, 48*time.Hour, "q"),
+	})
+	if err != nil {
+		t.Fatalf("RecordSearches: %v", err)
+	}
+
+	req := &models.SearchReportRequest{From: now.Add(-24 * time.Hour), To: now, Limit: 10}
+	queries, err := store.TopSearchQueries(ctx, req)
+	if err != nil {
+		t.Fatalf("TopSearchQueries: %v", err)
+	}
+	want := []models.SearchQueryStat{
+		{Query: "drone", Searches: 3, ZeroResults: 0, AvgLatencyMs: 2, LastSearchedAt: now.Add(-time.Hour)},
+		{Query: "flux capacitor", Searches: 2, ZeroResults: 2, AvgLatencyMs: 2, LastSearchedAt: now.Add(-time.Hour)},
+		{Query: "camera", Searches: 1, ZeroResults: 1, AvgLatencyMs: 2, LastSearchedAt: now.Add(-time.Minute)},
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("Expected %+v, got %+v", want, queries)
+	}
+
+	req.ZeroResults = true
+	queries, _ = store.TopSearchQueries(ctx, req)
+	if len(queries) != 2 || queries[0].Query != "flux capacitor" || queries[1].Query != "camera" {
+		t.Errorf("Expected the queries without results, got %+v", queries)
+	}
+
+	req.ZeroResults, req.Limit = false, 2
+	filters, err := store.TopSearchFilters(ctx, req)
+	if err != nil {
+		t.Fatalf("TopSearchFilters: %v", err)
+	}
+	wantFilters := []models.SearchFilterStat{
+		{Filters: []string{"q"}, Searches: 3, ZeroResults: 1},
+		{Filters: []string{"productName"}, Searches: 2, ZeroResults: 2},
+	}
+	if !reflect.DeepEqual(filters, wantFilters) {
+		t.Errorf("Expected %+v, got %+v", wantFilters, filters)
+	}
+}
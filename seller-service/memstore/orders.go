@@ -0,0 +1,55 @@
+package memstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// PlaceOrder checks and takes the stock of the products of the order and saves it under the
+// write lock, the equivalent of the transaction of the mysql store
+func (s *Store) PlaceOrder(_ context.Context, o *models.Order) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.buyers[o.BuyerID]; !ok {
+		return models.ErrBuyerNotFound
+	}
+	products := make(map[int]models.Product, len(o.Lines))
+	for _, id := range o.ProductIDs() {
+		if p, ok := s.products[id]; ok {
+			products[id] = p
+		}
+	}
+	if err := o.Take(products); err != nil {
+		return err
+	}
+	for _, line := range o.Lines {
+		p := s.products[line.ProductID]
+		p.Quantity -= line.Quantity
+		s.products[p.ID] = p
+		delete(s.cartItems, cartKey{o.BuyerID, line.ProductID})
+	}
+	s.lastOrderID++
+	o.ID = s.lastOrderID
+	// mysql keeps milliseconds
+	o.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	saved := *o
+	saved.Lines = append([]models.OrderLine(nil), o.Lines...)
+	s.orders[o.ID] = saved
+	return nil
+}
+
+// GetOrderByID retrieves an order by ID, returns models.ErrOrderNotFound if it does not exist
+func (s *Store) GetOrderByID(_ context.Context, id int) (models.Order, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	o, ok := s.orders[id]
+	if !ok {
+		return models.Order{}, models.ErrOrderNotFound
+	}
+	o.Lines = append([]models.OrderLine(nil), o.Lines...)
+	return o, nil
+}
@@ -0,0 +1,102 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// CreateSavedSearch saves the search and sets its ID and CreatedAt
+func (s *Store) CreateSavedSearch(_ context.Context, search *models.SavedSearch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lastSavedSearchID++
+	search.ID = s.lastSavedSearchID
+	// mysql keeps milliseconds
+	search.CreatedAt = time.Now().UTC().Truncate(time.Millisecond)
+	s.savedSearches[search.ID] = *search
+	return nil
+}
+
+// GetSavedSearchByID retrieves a saved search by ID, returns models.ErrSavedSearchNotFound if it does not exist
+func (s *Store) GetSavedSearchByID(_ context.Context, id int) (models.SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	search, ok := s.savedSearches[id]
+	if !ok {
+		return models.SavedSearch{}, models.ErrSavedSearchNotFound
+	}
+	return search, nil
+}
+
+// ListSavedSearches returns the page of saved searches of the buyer of the filter, ordered by ID
+func (s *Store) ListSavedSearches(_ context.Context, filter models.SavedSearchFilter) ([]models.SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var searches []models.SavedSearch
+	for _, search := range s.savedSearches {
+		if search.BuyerID == filter.BuyerID {
+			searches = append(searches, search)
+		}
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].ID < searches[j].ID })
+	return paginate(searches, filter.Offset(), filter.PerPage), nil
+}
+
+// UpdateSavedSearch replaces the name and filters of the search, keeping its buyer and creation time
+func (s *Store) UpdateSavedSearch(_ context.Context, search *models.SavedSearch) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, ok := s.savedSearches[search.ID]
+	if !ok {
+		return models.ErrSavedSearchNotFound
+	}
+	search.BuyerID, search.CreatedAt = previous.BuyerID, previous.CreatedAt
+	s.savedSearches[search.ID] = *search
+	return nil
+}
+
+// DeleteSavedSearch deletes the saved search
+func (s *Store) DeleteSavedSearch(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.savedSearches[id]; !ok {
+		return models.ErrSavedSearchNotFound
+	}
+	delete(s.savedSearches, id)
+	return nil
+}
+
+// CandidateSavedSearches returns the saved searches whose location, price range and desired
+// quantity accept the product of the seller, ordered by ID
+func (s *Store) CandidateSavedSearches(_ context.Context, p models.Product, seller models.Seller) ([]models.SavedSearch, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var searches []models.SavedSearch
+	for _, search := range s.savedSearches {
+		if search.DesiredQty > p.Quantity {
+			continue
+		}
+		if search.MinPrice > 0 && p.Price < search.MinPrice {
+			continue
+		}
+		if search.MaxPrice > 0 && p.Price > search.MaxPrice {
+			continue
+		}
+		if search.Location != "" && !strings.EqualFold(seller.Location, search.Location) {
+			continue
+		}
+		searches = append(searches, search)
+	}
+	sort.Slice(searches, func(i, j int) bool { return searches[i].ID < searches[j].ID })
+	return searches, nil
+}
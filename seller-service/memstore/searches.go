@@ -0,0 +1,115 @@
+package memstore
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// RecordSearches saves the events, their times kept to the millisecond like the mysql store
+func (s *Store) RecordSearches(_ context.Context, events []models.SearchEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range events {
+		e.At = e.At.UTC().Truncate(time.Millisecond)
+		e.Filters = append([]string(nil), e.Filters...)
+		s.searches = append(s.searches, e)
+	}
+	return nil
+}
+
+// searchGroup accumulates the searches sharing a query or a combination of filters
+type searchGroup struct {
+	key         string
+	searches    int
+	zeroResults int
+	latency     time.Duration
+	last        time.Time
+}
+
+// groupSearches groups the searches within the window of the request by the key of each,
+// searches with an empty key are left out
+func (s *Store) groupSearches(req *models.SearchReportRequest, key func(models.SearchEvent) string) []*searchGroup {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	groups := make(map[string]*searchGroup)
+	for _, e := range s.searches {
+		k := key(e)
+		if k == "" || e.At.Before(req.From) || !e.At.Before(req.To) {
+			continue
+		}
+		g, ok := groups[k]
+		if !ok {
+			g = &searchGroup{key: k}
+			groups[k] = g
+		}
+		g.searches++
+		if e.ZeroResults() {
+			g.zeroResults++
+		}
+		g.latency += e.Latency
+		if e.At.After(g.last) {
+			g.last = e.At
+		}
+	}
+	result := make([]*searchGroup, 0, len(groups))
+	for _, g := range groups {
+		if !req.ZeroResults || g.zeroResults > 0 {
+			result = append(result, g)
+		}
+	}
+	// ordered like the mysql store: by the number of searches, or of searches without results,
+	// then by key
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if req.ZeroResults && a.zeroResults != b.zeroResults {
+			return a.zeroResults > b.zeroResults
+		}
+		if a.searches != b.searches {
+			return a.searches > b.searches
+		}
+		return a.key < b.key
+	})
+	if len(result) > req.Limit {
+		result = result[:req.Limit]
+	}
+	return result
+}
+
+// TopSearchQueries returns the queries searched most within the window of the request
+func (s *Store) TopSearchQueries(_ context.Context, req *models.SearchReportRequest) ([]models.SearchQueryStat, error) {
+	groups := s.groupSearches(req, func(e models.SearchEvent) string { return e.Query })
+	stats := make([]models.SearchQueryStat, len(groups))
+	for i, g := range groups {
+		// the average in microseconds rounded to the hundredth of millisecond, as mysql computes it
+		avg := float64(g.latency.Microseconds()) / float64(g.searches) / 1000
+		stats[i] = models.SearchQueryStat{
+			Query:          g.key,
+			Searches:       g.searches,
+			ZeroResults:    g.zeroResults,
+			AvgLatencyMs:   math.Round(avg*100) / 100,
+			LastSearchedAt: g.last,
+		}
+	}
+	return stats, nil
+}
+
+// TopSearchFilters returns the combinations of filters used most within the window of the request
+func (s *Store) TopSearchFilters(_ context.Context, req *models.SearchReportRequest) ([]models.SearchFilterStat, error) {
+	groups := s.groupSearches(req, func(e models.SearchEvent) string { return strings.Join(e.Filters, ",") })
+	stats := make([]models.SearchFilterStat, len(groups))
+	for i, g := range groups {
+		stats[i] = models.SearchFilterStat{
+			Filters:     strings.Split(g.key, ","),
+			Searches:    g.searches,
+			ZeroResults: g.zeroResults,
+		}
+	}
+	return stats, nil
+}
@@ -0,0 +1,59 @@
+package memstore
+
+import (
+	"context"
+	"sort"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// ListSynonymGroups returns every synonym group, ordered by ID
+func (s *Store) ListSynonymGroups(_ context.Context) ([]models.SynonymGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	groups := make([]models.SynonymGroup, 0, len(s.synonymGroups))
+	for _, g := range s.synonymGroups {
+		g.Terms = append([]string(nil), g.Terms...)
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].ID < groups[j].ID })
+	return groups, nil
+}
+
+// CreateSynonymGroup saves the group and sets its ID
+func (s *Store) CreateSynonymGroup(_ context.Context, g *models.SynonymGroup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g.Normalize()
+	s.lastSynonymID++
+	g.ID = s.lastSynonymID
+	s.synonymGroups[g.ID] = models.SynonymGroup{ID: g.ID, Terms: append([]string(nil), g.Terms...)}
+	return nil
+}
+
+// UpdateSynonymGroup replaces the terms of the group
+func (s *Store) UpdateSynonymGroup(_ context.Context, g *models.SynonymGroup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.synonymGroups[g.ID]; !ok {
+		return models.ErrSynonymGroupNotFound
+	}
+	g.Normalize()
+	s.synonymGroups[g.ID] = models.SynonymGroup{ID: g.ID, Terms: append([]string(nil), g.Terms...)}
+	return nil
+}
+
+// DeleteSynonymGroup deletes the group
+func (s *Store) DeleteSynonymGroup(_ context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.synonymGroups[id]; !ok {
+		return models.ErrSynonymGroupNotFound
+	}
+	delete(s.synonymGroups, id)
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package middleware - http middlewares wrapping every route of the service
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/response"
+)
+
+// RequestIDHeader carries the id of a request from the client and back in the response
+const RequestIDHeader = response.RequestIDHeader
+
+// maxRequestIDLength bounds the ids accepted from clients so they cannot flood the logs
+const maxRequestIDLength = 128
+
+// Middleware wraps a handler with extra behaviour
+type Middleware func(http.Handler) http.Handler
+
+// Chain wraps h with the middlewares, the first one is the outermost
+func Chain(h http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		h = middlewares[i](h)
+	}
+	return h
+}
+
+// RequestID propagates the X-Request-ID header of the request, or generates one, into the
+// response header and into the request context, for the error responses and the logger, see
+// response.RequestID and logging.FromContext
+func RequestID(logger *logging.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" || len(id) > maxRequestIDLength {
+				id = newRequestID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			ctx := response.WithRequestID(r.Context(), id)
+			ctx = logging.NewContext(ctx, logger.With("request_id", id))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AccessLog logs every request once it is served, with its status, response size and latency
+func AccessLog() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			logging.FromContext(r.Context()).Infow("Request served",
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", rec.Status(),
+				"bytes", rec.bytes,
+				"duration", time.Since(start),
+				"remote", r.RemoteAddr,
+			)
+		})
+	}
+}
+
+// Recover turns a panic in the handler into a 500 response and logs it with its stack trace
+func Recover() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				err := recover()
+				if err == nil {
+					return
+				}
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
+				logging.FromContext(r.Context()).Errorw("Panic", "panic", err, "stack", string(debug.Stack()))
+				response.Err(w, r, response.NewError(http.StatusInternalServerError, response.CodeInternal, "Internal Server Error"))
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Timeout cancels the context of requests running longer than d and responds with 503
+func Timeout(d time.Duration) Middleware {
+	timedOut := response.NewError(http.StatusServiceUnavailable, response.CodeTimeout, "Request timed out")
+	return func(next http.Handler) http.Handler {
+		if d <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// the headers set by next replace this one when it responds in time
+			w.Header().Set("Content-Type", "application/json")
+			body := response.Body(timedOut, response.RequestID(r.Context()))
+			http.TimeoutHandler(next, d, body).ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireToken answers with 401 the requests without the token as bearer in their
+// Authorization header. Every request is refused when the token is empty
+func RequireToken(token string) Middleware {
+	unauthorized := response.NewError(http.StatusUnauthorized, response.CodeUnauthorized, "Missing or invalid bearer token")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || token == "" || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				response.Err(w, r, unauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b)
+}
+
+// statusRecorder remembers the status and the size of the response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(b []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	n, err := s.ResponseWriter.Write(b)
+	s.bytes += n
+	return n, err
+}
+
+// Status - the status sent, 200 when the handler wrote nothing
+func (s *statusRecorder) Status() int {
+	if s.status == 0 {
+		return http.StatusOK
+	}
+	return s.status
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (s *statusRecorder) Unwrap() http.ResponseWriter {
+	return s.ResponseWriter
+}
+
+// Defaults is the chain every route of the service is served through: request ids, access
+// logs, the request timeout and panic recovery, the latter innermost so it sees the stack of the handler
+func Defaults(logger *logging.Logger, timeout time.Duration) []Middleware {
+	return []Middleware{RequestID(logger), AccessLog(), Timeout(timeout), Recover()}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/db"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+)
+
+const migrateUsage = "usage: seller-service [flags] migrate up|down|status|to <version>"
+
+// runMigrate executes the migrate subcommand with its arguments, e.g. ["to", "3"]
+func runMigrate(ctx context.Context, migrator *db.Migrator, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	switch args[0] {
+	case "up":
+		if err := migrator.Up(ctx); err != nil {
+			return err
+		}
+	case "down":
+		if err := migrator.Down(ctx); err != nil {
+			return err
+		}
+	case "to":
+		if len(args) != 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid migration version %q", args[1])
+		}
+		if err := migrator.To(ctx, version); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return errors.New(migrateUsage)
+	}
+	return printMigrationStatus(ctx, migrator, out)
+}
+
+func printMigrationStatus(ctx context.Context, migrator *db.Migrator, out io.Writer) error {
+	status, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, s := range status {
+		appliedAt := "pending"
+		if s.Applied {
+			appliedAt = s.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+	}
+	return w.Flush()
+}
+
+// checkSchema compares the database schema with the migrations embedded in the binary.
+// mode is one of "fail", "warn" or "migrate"
+func checkSchema(ctx context.Context, migrator *db.Migrator, mode string, logger *logging.Logger) error {
+	current, err := migrator.Version(ctx)
+	if err != nil {
+		return err
+	}
+	latest := migrator.Latest()
+	switch {
+	case current > latest:
+		logger.Warnf("Database schema version %d is newer than the latest migration %d known to this binary", current, latest)
+		return nil
+	case current == latest:
+		return nil
+	}
+	switch mode {
+	case "migrate":
+		logger.Infof("Migrating database schema from version %d to %d", current, latest)
+		return migrator.Up(ctx)
+	case "warn":
+		logger.Warnf("Database schema version %d is behind the binary (%d), run `seller-service migrate up`", current, latest)
+		return nil
+	default:
+		return fmt.Errorf("database schema version %d is behind the binary (%d), run `seller-service migrate up`", current, latest)
+	}
+}
@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// EarthRadiusKm is the mean radius of the earth the distances are computed with
+const EarthRadiusKm = 6371.0
+
+// Address is the structured location of a seller
+type Address struct {
+	// Country is the ISO 3166-1 alpha-2 code of the country, e.g. IN or US
+	Country    string `json:"country" validate:"required"`
+	City       string `json:"city,omitempty" validate:"max=255"`
+	PostalCode string `json:"postalCode,omitempty" validate:"max=32"`
+	// Latitude and Longitude are given together, in degrees, they place the seller on the
+	// map of the searches near a point
+	Latitude  *float64 `json:"latitude,omitempty" validate:"min=-90,max=90"`
+	Longitude *float64 `json:"longitude,omitempty" validate:"min=-180,max=180"`
+}
+
+// validate records the violations of the address in errs, under address.<field>
+func (a *Address) validate(errs *validation.Errors) {
+	for _, fe := range validation.Struct(a) {
+		errs.Add("address."+fe.Field, fe.Message)
+	}
+	if !errs.Has("address.country") && !IsCountryCode(a.Country) {
+		errs.Add("address.country", "must be an ISO 3166-1 alpha-2 country code, e.g. IN or US")
+	}
+	if a.Latitude == nil && a.Longitude != nil {
+		errs.Add("address.latitude", "is required with longitude")
+	}
+	if a.Longitude == nil && a.Latitude != nil {
+		errs.Add("address.longitude", "is required with latitude")
+	}
+}
+
+// Point returns the coordinates of the address, false when it has none
+func (a *Address) Point() (GeoPoint, bool) {
+	if a == nil || a.Latitude == nil || a.Longitude == nil {
+		return GeoPoint{}, false
+	}
+	return GeoPoint{Lat: *a.Latitude, Lng: *a.Longitude}, true
+}
+
+// normalized returns a copy of the address as the stores save it, the country in uppercase
+func (a *Address) normalized() *Address {
+	if a == nil {
+		return nil
+	}
+	address := *a
+	address.Country = strings.ToUpper(address.Country)
+	return &address
+}
+
+// GeoPoint is a point on the map, in degrees
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lng float64 `json:"lng"`
+}
+
+// errInvalidGeoPoint is returned by ParseGeoPoint, its message completes the name of the parameter
+var errInvalidGeoPoint = errors.New("must be a latitude and a longitude separated by a comma")
+
+// ParseGeoPoint parses a latitude and a longitude separated by a comma, e.g. 12.97,77.59
+func ParseGeoPoint(s string) (GeoPoint, error) {
+	lat, lng, ok := strings.Cut(s, ",")
+	if !ok {
+		return GeoPoint{}, errInvalidGeoPoint
+	}
+	var p GeoPoint
+	var errLat, errLng error
+	p.Lat, errLat = strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	p.Lng, errLng = strconv.ParseFloat(strings.TrimSpace(lng), 64)
+	if errLat != nil || errLng != nil {
+		return GeoPoint{}, errInvalidGeoPoint
+	}
+	return p, nil
+}
+
+// valid tells whether the latitude is within [-90, 90] and the longitude within [-180, 180]
+func (p GeoPoint) valid() bool {
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lng >= -180 && p.Lng <= 180
+}
+
+// DistanceKm returns the great-circle distance between two points with the haversine
+// formula, the one the mysql store evaluates
+func DistanceKm(a, b GeoPoint) float64 {
+	dLat := radians(b.Lat - a.Lat)
+	dLng := radians(b.Lng - a.Lng)
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(radians(a.Lat))*math.Cos(radians(b.Lat))*math.Pow(math.Sin(dLng/2), 2)
+	// rounding can take h of antipodal points past 1, where the arcsine is not defined
+	return EarthRadiusKm * 2 * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// KmPerDegree is the length of a degree of latitude, the distance between two points is at
+// least their difference of latitude times it
+const KmPerDegree = EarthRadiusKm * math.Pi / 180
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCountryCode(t *testing.T) {
+	tests := map[string]string{"IN": "IN", "ind": "IN", " India ": "IN", "UK": "GB", "Viet Nam": "VN", "vietnam": "VN", "Indiana": "", "": ""}
+	for location, want := range tests {
+		if got, ok := CountryCode(location); got != want || ok != (want != "") {
+			t.Errorf("CountryCode(%q) = %q %v, expected %q", location, got, ok, want)
+		}
+	}
+	if !IsCountryCode("us") || IsCountryCode("USA") || IsCountryCode("UK") {
+		t.Error("Expected only alpha-2 codes to be country codes")
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	bengaluru, chennai := GeoPoint{Lat: 12.9716, Lng: 77.5946}, GeoPoint{Lat: 13.0827, Lng: 80.2707}
+	if d := DistanceKm(bengaluru, chennai); math.Abs(d-290) > 2 {
+		t.Errorf("Expected about 290km from Bengaluru to Chennai, got %v", d)
+	}
+	if d := DistanceKm(GeoPoint{Lat: 0, Lng: 0}, GeoPoint{Lat: 0, Lng: 180}); math.IsNaN(d) || math.Abs(d-math.Pi*EarthRadiusKm) > 1e-6 {
+		t.Errorf("Expected half the circumference between antipodes, got %v", d)
+	}
+
+	if p, err := ParseGeoPoint(" 12.97, 77.59"); err != nil || p != (GeoPoint{Lat: 12.97, Lng: 77.59}) {
+		t.Errorf("Unexpected point %+v %v", p, err)
+	}
+	for _, s := range []string{"12.97", "a,b", "12.97,"} {
+		if _, err := ParseGeoPoint(s); err == nil {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestSellerUpdate_Apply(t *testing.T) {
+	seller := Seller{Name: "A", Location: "IND"}
+	seller.Normalize()
+	location := "US"
+	(&SellerUpdate{Location: &location}).Apply(&seller)
+	if seller.Address == nil || seller.Address.Country != "US" {
+		t.Fatalf("Expected the derived country to follow the location, got %+v", seller.Address)
+	}
+
+	seller.Address = &Address{Country: "us", City: "Austin"}
+	seller.Normalize()
+	location = "Somewhere"
+	(&SellerUpdate{Location: &location}).Apply(&seller)
+	if seller.Address == nil || seller.Address.Country != "US" || seller.Address.City != "Austin" {
+		t.Errorf("Expected a full address to be kept, got %+v", seller.Address)
+	}
+}
@@ -0,0 +1,260 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// Limits of the attributes and tags of a product and of the attribute filters of a search
+const (
+	MaxProductAttributes = 20
+	MaxProductTags       = 20
+	MaxAttributeFilters  = 10
+	// MaxAttributeValueLength is the number of characters of an attribute value, names and
+	// tags are at most 64 characters long
+	MaxAttributeValueLength = 255
+)
+
+// attributeName is the shape of attribute names and tags, e.g. color, screen_size or usb-c
+var attributeName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// NormalizeAttributes returns copies of the attributes and tags of a product as the stores
+// save them: names and tags in lowercase, tags sorted. nil stays nil
+func NormalizeAttributes(attributes map[string]string, tags []string) (map[string]string, []string) {
+	var normalized map[string]string
+	if attributes != nil {
+		normalized = make(map[string]string, len(attributes))
+		for name, value := range attributes {
+			normalized[strings.ToLower(name)] = value
+		}
+	}
+	var sorted []string
+	if tags != nil {
+		sorted = make([]string, len(tags))
+		for i, tag := range tags {
+			sorted[i] = strings.ToLower(tag)
+		}
+		sort.Strings(sorted)
+	}
+	return normalized, sorted
+}
+
+// validateAttributes records the violations of the attributes and tags of a product in errs.
+// Names and tags are compared without regard to case as they are saved in lowercase
+func validateAttributes(attributes map[string]string, tags []string, errs *validation.Errors) {
+	if len(attributes) > MaxProductAttributes {
+		errs.Add("attributes", fmt.Sprintf("must hold at most %d attributes", MaxProductAttributes))
+	}
+	seen := make(map[string]bool)
+	for _, name := range sortedKeys(attributes) {
+		switch {
+		case !attributeName.MatchString(name):
+			errs.Add("attributes."+name, "must be named with at most 64 letters, digits, - and _")
+		case seen[strings.ToLower(name)]:
+			errs.Add("attributes."+name, "is given more than once")
+		case utf8.RuneCountInString(attributes[name]) > MaxAttributeValueLength:
+			errs.Add("attributes."+name, fmt.Sprintf("must be at most %d characters long", MaxAttributeValueLength))
+		}
+		seen[strings.ToLower(name)] = true
+	}
+
+	if len(tags) > MaxProductTags {
+		errs.Add("tags", fmt.Sprintf("must hold at most %d tags", MaxProductTags))
+	}
+	seen = make(map[string]bool)
+	for _, tag := range tags {
+		if !attributeName.MatchString(tag) {
+			errs.Add("tags", "must be made of at most 64 letters, digits, - and _")
+			break
+		}
+		if seen[strings.ToLower(tag)] {
+			errs.Add("tags", "lists "+tag+" more than once")
+			break
+		}
+		seen[strings.ToLower(tag)] = true
+	}
+}
+
+// AttributeFilter compares an attribute of the products with a value: = and != compare
+// strings without regard to case, products without the attribute pass != but not =, and the
+// other operators compare numbers
+type AttributeFilter struct {
+	Name  string `json:"name"`
+	Op    string `json:"op"`
+	Value string `json:"value"`
+}
+
+// AttributeOps are the operators of attribute filters
+var AttributeOps = []string{"=", "!=", ">", ">=", "<", "<="}
+
+// ParseAttributeFilter parses a query parameter named attr.<name> followed by an optional
+// operator, e.g. attr.color=red, attr.ram>=16 or attr.ram>16, the latter arriving as a
+// parameter without value
+func ParseAttributeFilter(key, value string) (AttributeFilter, bool) {
+	name, ok := strings.CutPrefix(key, "attr.")
+	if !ok {
+		return AttributeFilter{}, false
+	}
+	if value == "" {
+		if i := strings.IndexAny(name, "<>"); i >= 0 {
+			return AttributeFilter{Name: name[:i], Op: name[i : i+1], Value: name[i+1:]}, true
+		}
+	}
+	// the = of the parameter completes the operators ending with it
+	for _, op := range []string{"!", ">", "<"} {
+		if strings.HasSuffix(name, op) {
+			return AttributeFilter{Name: strings.TrimSuffix(name, op), Op: op + "=", Value: value}, true
+		}
+	}
+	return AttributeFilter{Name: name, Op: "=", Value: value}, true
+}
+
+// Numeric tells whether the filter compares numbers
+func (f AttributeFilter) Numeric() bool {
+	return f.Op != "=" && f.Op != "!="
+}
+
+// validateAttributeFilters records the violations of the attribute filters of a search in errs
+func validateAttributeFilters(filters []AttributeFilter, errs *validation.Errors) {
+	if len(filters) > MaxAttributeFilters {
+		errs.Add("attributes", fmt.Sprintf("must hold at most %d filters", MaxAttributeFilters))
+		return
+	}
+	for _, f := range filters {
+		field := "attr." + f.Name
+		known := false
+		for _, op := range AttributeOps {
+			known = known || op == f.Op
+		}
+		switch {
+		case !attributeName.MatchString(f.Name):
+			errs.Add(field, "must be named with at most 64 letters, digits, - and _")
+		case !known:
+			errs.Add(field, "must be compared with one of "+strings.Join(AttributeOps, " "))
+		case f.Numeric():
+			if n, err := strconv.ParseFloat(f.Value, 64); err != nil || !isFinite(n) {
+				errs.Add(field, "must be compared with a number by "+f.Op)
+			}
+		case utf8.RuneCountInString(f.Value) > MaxAttributeValueLength:
+			errs.Add(field, fmt.Sprintf("must be at most %d characters long", MaxAttributeValueLength))
+		}
+	}
+}
+
+// AttributeNumber returns the value of an attribute as a number, false when it is not one.
+// NaN and infinities are not numbers, mysql cannot store them
+func AttributeNumber(value string) (float64, bool) {
+	n, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	return n, err == nil && isFinite(n)
+}
+
+// isFinite tells whether n is neither NaN nor an infinity
+func isFinite(n float64) bool {
+	return !math.IsNaN(n) && !math.IsInf(n, 0)
+}
+
+// MatchesAttributes tells whether the attributes and tags of the product pass the filters
+// and carry every tag, the way the mysql store evaluates them. The filters must be valid
+func MatchesAttributes(p Product, filters []AttributeFilter, tags []string) bool {
+	for _, f := range filters {
+		value, ok := attributeFold(p.Attributes, f.Name)
+		switch f.Op {
+		case "=":
+			ok = ok && strings.EqualFold(value, f.Value)
+		case "!=":
+			ok = !ok || !strings.EqualFold(value, f.Value)
+		default:
+			n, isNumber := AttributeNumber(value)
+			want, _ := strconv.ParseFloat(f.Value, 64)
+			ok = ok && isNumber && compareAttribute(n, f.Op, want)
+		}
+		if !ok {
+			return false
+		}
+	}
+	for _, tag := range tags {
+		found := false
+		for _, t := range p.Tags {
+			found = found || strings.EqualFold(t, tag)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func compareAttribute(n float64, op string, want float64) bool {
+	switch op {
+	case ">":
+		return n > want
+	case ">=":
+		return n >= want
+	case "<":
+		return n < want
+	case "<=":
+		return n <= want
+	}
+	return false
+}
+
+// attributeFold returns the attribute named name without regard to case
+func attributeFold(attributes map[string]string, name string) (string, bool) {
+	for k, v := range attributes {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// AttributeSummary describes an attribute used by the products, to discover what searches can filter on
+type AttributeSummary struct {
+	Name string `json:"name"`
+	// ProductCount is the number of products having the attribute
+	ProductCount int `json:"productCount"`
+	// Values are the most common values of the attribute, at most MaxFacetBuckets of them
+	Values []FacetBucket `json:"values"`
+}
+
+// SummarizeAttributes builds the summaries of the attributes from the number of products
+// having each value of each attribute. Summaries are sorted by name and their values by count
+// then value
+func SummarizeAttributes(counts map[string]map[string]int) []AttributeSummary {
+	summaries := []AttributeSummary{}
+	for _, name := range sortedKeys(counts) {
+		summary := AttributeSummary{Name: name, Values: []FacetBucket{}}
+		for value, count := range counts[name] {
+			summary.ProductCount += count
+			summary.Values = append(summary.Values, FacetBucket{Value: value, Count: count})
+		}
+		sort.Slice(summary.Values, func(i, j int) bool {
+			a, b := summary.Values[i], summary.Values[j]
+			if a.Count != b.Count {
+				return a.Count > b.Count
+			}
+			return a.Value < b.Value
+		})
+		if len(summary.Values) > MaxFacetBuckets {
+			summary.Values = summary.Values[:MaxFacetBuckets]
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,46 @@
+package models
+
+import "testing"
+
+func TestParseAttributeFilter(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       AttributeFilter
+		ok         bool
+	}{
+		{"attr.color", "red", AttributeFilter{Name: "color", Op: "=", Value: "red"}, true},
+		{"attr.color!", "red", AttributeFilter{Name: "color", Op: "!=", Value: "red"}, true},
+		{"attr.ram>", "16", AttributeFilter{Name: "ram", Op: ">=", Value: "16"}, true},
+		{"attr.ram<", "16", AttributeFilter{Name: "ram", Op: "<=", Value: "16"}, true},
+		{"attr.ram>16", "", AttributeFilter{Name: "ram", Op: ">", Value: "16"}, true},
+		{"attr.ram<8", "", AttributeFilter{Name: "ram", Op: "<", Value: "8"}, true},
+		{"attr.size", "", AttributeFilter{Name: "size", Op: "=", Value: ""}, true},
+		{"color", "red", AttributeFilter{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseAttributeFilter(tt.key, tt.value)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("ParseAttributeFilter(%q, %q) = %+v, %v, want %+v, %v", tt.key, tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestAttributeNumber(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+		ok    bool
+	}{
+		{" 16 ", 16, true},
+		{"-2.5", -2.5, true},
+		{"16GB", 0, false},
+		{"Infinity", 0, false},
+		{"-inf", 0, false},
+		{"NaN", 0, false},
+	}
+	for _, tt := range tests {
+		if n, ok := AttributeNumber(tt.value); ok != tt.ok || ok && n != tt.want {
+			t.Errorf("AttributeNumber(%q) = %v, %v, want %v, %v", tt.value, n, ok, tt.want, tt.ok)
+		}
+	}
+}
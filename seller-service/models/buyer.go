@@ -0,0 +1,166 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// ErrBuyerNotFound is returned when a buyer id does not match any buyer
+var ErrBuyerNotFound = errors.New("buyer not found")
+
+// ErrBuyerEmailTaken is returned when saving a buyer with the email of another buyer
+var ErrBuyerEmailTaken = errors.New("email is already registered")
+
+// ErrBuyerAddressNotFound is returned when an address id does not match any address of the buyer
+var ErrBuyerAddressNotFound = errors.New("buyer address not found")
+
+// ErrTooManyBuyerAddresses is returned when adding an address to a buyer who has MaxBuyerAddresses
+var ErrTooManyBuyerAddresses = errors.New("too many buyer addresses")
+
+// MaxBuyerAddresses is the number of addresses the address book of a buyer holds at most
+const MaxBuyerAddresses = 10
+
+// Buyer represents a buyer
+type Buyer struct {
+	ID   int    `json:"id"`
+	Name string `json:"name" validate:"required,max=255"`
+	// Email identifies the buyer, no two buyers share one. It is saved in lowercase
+	Email string `json:"email" validate:"required,max=255"`
+	Phone string `json:"phone,omitempty" validate:"max=32"`
+	// CreatedAt is set by the store when the buyer registers
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate checks the fields of the buyer and returns every violation as validation.Errors
+func (b *Buyer) Validate() error {
+	errs := validation.Struct(b)
+	validateEmail("email", b.Email, &errs)
+	validatePhone("phone", b.Phone, &errs)
+	return errs.Err()
+}
+
+// Normalize prepares the buyer to be saved: the email trimmed and in lowercase
+func (b *Buyer) Normalize() {
+	b.Email = normalizeEmail(b.Email)
+}
+
+// validateEmail records in errs an email that is not a bare address such as jane@example.com
+func validateEmail(field, email string, errs *validation.Errors) {
+	if errs.Has(field) {
+		return
+	}
+	email = strings.TrimSpace(email)
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email || address.Name != "" {
+		errs.Add(field, "must be an email address, e.g. jane@example.com")
+	}
+}
+
+// validatePhone records in errs a phone number holding other characters than digits, spaces,
+// +, -, ( and ), or fewer than 6 digits
+func validatePhone(field, phone string, errs *validation.Errors) {
+	if phone == "" || errs.Has(field) {
+		return
+	}
+	digits := 0
+	for _, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case strings.ContainsRune(" +-()", r):
+		default:
+			errs.Add(field, "must be a phone number, e.g. +91 80 1234 5678")
+			return
+		}
+	}
+	if digits < 6 {
+		errs.Add(field, "must be a phone number, e.g. +91 80 1234 5678")
+	}
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// BuyerUpdate holds the fields of a buyer that can be changed, nil fields are left unchanged
+type BuyerUpdate struct {
+	Name  *string `json:"name" validate:"required,max=255"`
+	Email *string `json:"email" validate:"required,max=255"`
+	// Phone replaces the phone number of the buyer, "" removes it
+	Phone *string `json:"phone" validate:"max=32"`
+}
+
+// Validate checks the fields being updated, when complete is set the name and email must be present
+func (u *BuyerUpdate) Validate(complete bool) error {
+	errs := validation.Struct(u)
+	if u.Email != nil {
+		validateEmail("email", *u.Email, &errs)
+	}
+	if u.Phone != nil {
+		validatePhone("phone", *u.Phone, &errs)
+	}
+	if complete {
+		if u.Name == nil {
+			errs.Add("name", "is required")
+		}
+		if u.Email == nil {
+			errs.Add("email", "is required")
+		}
+	}
+	if u.Name == nil && u.Email == nil && u.Phone == nil && !complete {
+		return ErrNothingToUpdate
+	}
+	return errs.Err()
+}
+
+// Apply copies the fields being updated onto the buyer and normalizes it, see Buyer.Normalize
+func (u *BuyerUpdate) Apply(b *Buyer) {
+	if u.Name != nil {
+		b.Name = *u.Name
+	}
+	if u.Email != nil {
+		b.Email = *u.Email
+	}
+	if u.Phone != nil {
+		b.Phone = *u.Phone
+	}
+	b.Normalize()
+}
+
+// BuyerAddress is an address of the address book of a buyer, where orders are delivered
+type BuyerAddress struct {
+	ID      int `json:"id"`
+	BuyerID int `json:"buyerId"`
+	// Label names the address for the buyer, e.g. Home or Office
+	Label      string `json:"label,omitempty" validate:"max=50"`
+	Recipient  string `json:"recipient" validate:"required,max=255"`
+	Line1      string `json:"line1" validate:"required,max=255"`
+	Line2      string `json:"line2,omitempty" validate:"max=255"`
+	City       string `json:"city" validate:"required,max=255"`
+	Region     string `json:"region,omitempty" validate:"max=255"`
+	PostalCode string `json:"postalCode,omitempty" validate:"max=32"`
+	// Country is the ISO 3166-1 alpha-2 code of the country, saved in uppercase
+	Country string `json:"country" validate:"required"`
+	Phone   string `json:"phone,omitempty" validate:"max=32"`
+	// Default marks the address used when the buyer does not choose one, a buyer with
+	// addresses has exactly one default address
+	Default bool `json:"default"`
+}
+
+// Validate checks the fields of the address and returns every violation as validation.Errors
+func (a *BuyerAddress) Validate() error {
+	errs := validation.Struct(a)
+	if !errs.Has("country") && !IsCountryCode(a.Country) {
+		errs.Add("country", "must be an ISO 3166-1 alpha-2 country code, e.g. IN or US")
+	}
+	validatePhone("phone", a.Phone, &errs)
+	return errs.Err()
+}
+
+// Normalize prepares the address to be saved: the country in uppercase
+func (a *BuyerAddress) Normalize() {
+	a.Country = strings.ToUpper(a.Country)
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+func TestBuyer_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		buyer  Buyer
+		fields []string
+	}{
+		{"valid", Buyer{Name: "Jane", Email: "jane@example.com", Phone: "+1 (555) 010-9999"}, nil},
+		{"padded email", Buyer{Name: "Jane", Email: " jane@example.com "}, nil},
+		{"display name", Buyer{Name: "Jane", Email: "Jane <jane@example.com>"}, []string{"email"}},
+		{"no domain", Buyer{Name: "Jane", Email: "jane"}, []string{"email"}},
+		{"letters in phone", Buyer{Name: "Jane", Email: "jane@example.com", Phone: "555-JANE"}, []string{"phone"}},
+		{"short phone", Buyer{Name: "Jane", Email: "jane@example.com", Phone: "+1 55"}, []string{"phone"}},
+		{"missing", Buyer{}, []string{"name", "email"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.buyer.Validate()
+			var errs validation.Errors
+			if errors.As(err, &errs) != (tt.fields != nil) || len(errs) != len(tt.fields) {
+				t.Fatalf("Expected invalid fields %v, got %v", tt.fields, err)
+			}
+			for _, field := range tt.fields {
+				if !errs.Has(field) {
+					t.Errorf("Expected %s to be invalid, got %v", field, err)
+				}
+			}
+		})
+	}
+}
+
+func TestBuyerUpdate(t *testing.T) {
+	if err := (&BuyerUpdate{}).Validate(false); err != ErrNothingToUpdate {
+		t.Errorf("Expected ErrNothingToUpdate, got %v", err)
+	}
+	email := "JANE.DOE@example.com "
+	update := BuyerUpdate{Email: &email}
+	if err := update.Validate(true); err == nil || !errors.As(err, new(validation.Errors)) {
+		t.Errorf("Expected the name to be required, got %v", err)
+	}
+	buyer := Buyer{Name: "Jane", Email: "jane@example.com"}
+	update.Apply(&buyer)
+	if buyer.Email != "jane.doe@example.com" || buyer.Name != "Jane" {
+		t.Errorf("Unexpected buyer %+v", buyer)
+	}
+}
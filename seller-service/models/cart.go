@@ -0,0 +1,138 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// ErrCartItemNotFound is returned when the cart of a buyer does not hold the product
+var ErrCartItemNotFound = errors.New("cart item not found")
+
+// ErrTooManyCartItems is returned when putting a new product in a cart holding MaxCartItems
+var ErrTooManyCartItems = errors.New("too many cart items")
+
+// ErrInsufficientStock is returned when asking for more of a product than it has in stock
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+// MaxCartItems is the number of distinct products a cart holds at most
+const MaxCartItems = 100
+
+// CartItem is a product in the cart of a buyer. The price and stock of the product are recorded
+// when it is put in the cart and when its quantity changes, a cart read compares them to the
+// live ones of the product
+type CartItem struct {
+	BuyerID   int `json:"-"`
+	ProductID int `json:"productId" validate:"required,min=1"`
+	Quantity  int `json:"quantity" validate:"required,min=1,max=10000"`
+	// AddedPrice and AddedStock are the price and quantity of the product when the item was
+	// last changed
+	AddedPrice float64 `json:"-"`
+	AddedStock int     `json:"-"`
+	// AddedAt is set by the store when the product is first put in the cart
+	AddedAt time.Time `json:"-"`
+	// Product is the live product with its seller, loaded when the cart is read
+	Product Product `json:"-"`
+}
+
+// Validate checks the product and quantity of the item and returns every violation as
+// validation.Errors
+func (i *CartItem) Validate() error {
+	return validation.Struct(i).Err()
+}
+
+// Snapshot records the price and stock of the product as those the buyer saw
+func (i *CartItem) Snapshot(p Product) {
+	i.AddedPrice, i.AddedStock = p.Price, p.Quantity
+}
+
+// CartLine is an item of a cart as the buyer sees it: with the live price and stock of its
+// product and flagged when they changed since it was added
+type CartLine struct {
+	ProductID   int     `json:"productId"`
+	ProductName string  `json:"productName"`
+	Quantity    int     `json:"quantity"`
+	Price       float64 `json:"price"`
+	// Stock is the quantity of the product in stock
+	Stock      int       `json:"stock"`
+	Subtotal   float64   `json:"subtotal"`
+	AddedPrice float64   `json:"addedPrice"`
+	AddedStock int       `json:"addedStock"`
+	AddedAt    time.Time `json:"addedAt"`
+	// PriceChanged and StockChanged tell the price or the stock of the product changed since
+	// the item was added
+	PriceChanged bool `json:"priceChanged"`
+	StockChanged bool `json:"stockChanged"`
+	// InsufficientStock tells the product has less in stock than the quantity of the item
+	InsufficientStock bool `json:"insufficientStock"`
+}
+
+// CartSellerGroup holds the lines of a cart sold by one seller
+type CartSellerGroup struct {
+	SellerID   int        `json:"sellerId"`
+	SellerName string     `json:"sellerName"`
+	Lines      []CartLine `json:"lines"`
+	// Quantity is the number of units of the lines
+	Quantity int     `json:"quantity"`
+	Subtotal float64 `json:"subtotal"`
+}
+
+// Cart is the cart of a buyer with its lines grouped by seller and its totals
+type Cart struct {
+	BuyerID int               `json:"buyerId"`
+	Sellers []CartSellerGroup `json:"sellers"`
+	// Items is the number of lines of the cart and Quantity the number of units of them
+	Items    int     `json:"items"`
+	Quantity int     `json:"quantity"`
+	Total    float64 `json:"total"`
+	// Changed tells one of the lines is flagged, the buyer should review them before ordering
+	Changed bool `json:"changed"`
+}
+
+// NewCart builds the cart of the buyer from its items with their products loaded. The sellers
+// come in the order of their first item, as do the lines of a seller. Amounts are rounded to
+// the cent
+func NewCart(buyerID int, items []CartItem) Cart {
+	cart := Cart{BuyerID: buyerID, Sellers: []CartSellerGroup{}}
+	groups := make(map[int]int)
+	for _, item := range items {
+		p := item.Product
+		line := CartLine{
+			ProductID:         item.ProductID,
+			ProductName:       p.ProductName,
+			Quantity:          item.Quantity,
+			Price:             p.Price,
+			Stock:             p.Quantity,
+			Subtotal:          roundCents(p.Price * float64(item.Quantity)),
+			AddedPrice:        item.AddedPrice,
+			AddedStock:        item.AddedStock,
+			AddedAt:           item.AddedAt,
+			PriceChanged:      roundCents(p.Price) != roundCents(item.AddedPrice),
+			StockChanged:      p.Quantity != item.AddedStock,
+			InsufficientStock: p.Quantity < item.Quantity,
+		}
+		i, ok := groups[p.SellerID]
+		if !ok {
+			i = len(cart.Sellers)
+			groups[p.SellerID] = i
+			cart.Sellers = append(cart.Sellers, CartSellerGroup{SellerID: p.SellerID, SellerName: p.seller().Name})
+		}
+		group := &cart.Sellers[i]
+		group.Lines = append(group.Lines, line)
+		group.Quantity += line.Quantity
+		group.Subtotal = roundCents(group.Subtotal + line.Subtotal)
+
+		cart.Items++
+		cart.Quantity += line.Quantity
+		cart.Total = roundCents(cart.Total + line.Subtotal)
+		cart.Changed = cart.Changed || line.PriceChanged || line.StockChanged || line.InsufficientStock
+	}
+	return cart
+}
+
+// roundCents rounds an amount to the cent
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
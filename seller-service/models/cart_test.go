@@ -0,0 +1,32 @@
+package models
+
+import "testing"
+
+func TestNewCart(t *testing.T) {
+	first, second := &Seller{ID: 1, Name: "First"}, &Seller{ID: 2, Name: "Second"}
+	items := []CartItem{
+		{ProductID: 10, Quantity: 3, AddedPrice: 0.1, AddedStock: 5, Product: Product{ID: 10, SellerID: 2, Price: 0.1, Quantity: 5, Seller: second}},
+		{ProductID: 11, Quantity: 1, AddedPrice: 4.5, AddedStock: 9, Product: Product{ID: 11, SellerID: 1, Price: 4.5, Quantity: 9, Seller: first}},
+		{ProductID: 12, Quantity: 2, AddedPrice: 2, AddedStock: 4, Product: Product{ID: 12, SellerID: 2, Price: 2.25, Quantity: 1, Seller: second}},
+	}
+	cart := NewCart(7, items)
+	if cart.BuyerID != 7 || cart.Items != 3 || cart.Quantity != 6 || cart.Total != 9.3 || !cart.Changed {
+		t.Errorf("Unexpected cart %+v", cart)
+	}
+	if len(cart.Sellers) != 2 || cart.Sellers[0].SellerName != "Second" || cart.Sellers[1].SellerName != "First" {
+		t.Fatalf("Expected the sellers in the order of their first item, got %+v", cart.Sellers)
+	}
+	if group := cart.Sellers[0]; len(group.Lines) != 2 || group.Quantity != 5 || group.Subtotal != 4.8 {
+		t.Errorf("Unexpected group %+v", group)
+	}
+	if line := cart.Sellers[0].Lines[0]; line.Subtotal != 0.3 || line.PriceChanged || line.StockChanged || line.InsufficientStock {
+		t.Errorf("Expected an unchanged line, got %+v", line)
+	}
+	if line := cart.Sellers[0].Lines[1]; !line.PriceChanged || !line.StockChanged || !line.InsufficientStock {
+		t.Errorf("Expected the changes of the line to be flagged, got %+v", line)
+	}
+
+	if empty := NewCart(7, nil); empty.Sellers == nil || empty.Total != 0 || empty.Changed {
+		t.Errorf("Unexpected empty cart %+v", empty)
+	}
+}
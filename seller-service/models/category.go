@@ -0,0 +1,110 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// MaxCategoryDepth is the number of levels of the category tree, roots being the first one
+const MaxCategoryDepth = 8
+
+// Category is a node of the category tree, e.g. Headphones under Audio under Electronics
+type Category struct {
+	ID int `json:"id"`
+	// ParentID is the id of the parent category, 0 for the roots of the tree
+	ParentID int    `json:"parentId" validate:"min=0"`
+	Name     string `json:"name" validate:"required,max=255"`
+}
+
+// Validate checks the fields of the category and that its parent exists, every violation
+// found is returned as validation.Errors
+func (c *Category) Validate(ctx context.Context, categories CategoryStore) error {
+	errs := validation.Struct(c)
+	if c.ParentID == 0 || errs.Has("parentId") {
+		return errs.Err()
+	}
+	_, err := categories.GetCategoryByID(ctx, c.ParentID)
+	if errors.Is(err, ErrCategoryNotFound) {
+		errs.Add("parentId", "category does not exist")
+	} else if err != nil {
+		return err
+	}
+	return errs.Err()
+}
+
+// CategoryUpdate holds the fields of a category that can be changed, nil fields are left
+// unchanged. A ParentID of 0 moves the category to the roots of the tree
+type CategoryUpdate struct {
+	Name     *string `json:"name" validate:"required,max=255"`
+	ParentID *int    `json:"parentId" validate:"min=0"`
+}
+
+// Validate checks the fields being updated, when complete is set the name must be present
+func (u *CategoryUpdate) Validate(complete bool) error {
+	errs := validation.Struct(u)
+	if complete && u.Name == nil {
+		errs.Add("name", "is required")
+	}
+	if u.Name == nil && u.ParentID == nil && !complete {
+		return ErrNothingToUpdate
+	}
+	return errs.Err()
+}
+
+// Apply copies the fields being updated onto the category
+func (u *CategoryUpdate) Apply(c *Category) {
+	if u.Name != nil {
+		c.Name = *u.Name
+	}
+	if u.ParentID != nil {
+		c.ParentID = *u.ParentID
+	}
+}
+
+// CategoryNode is a category of the tree with its subcategories
+type CategoryNode struct {
+	Category
+	// ProductCount is the number of products of the category and of its descendants
+	ProductCount int             `json:"productCount"`
+	Children     []*CategoryNode `json:"children"`
+}
+
+// CategoryTree builds the tree of the categories, counts holding the number of products of
+// each category by id. The roots and the children of each node are sorted by name then id
+func CategoryTree(categories []Category, counts map[int]int) []*CategoryNode {
+	nodes := make(map[int]*CategoryNode, len(categories))
+	for _, c := range categories {
+		nodes[c.ID] = &CategoryNode{Category: c, Children: []*CategoryNode{}}
+	}
+	roots := []*CategoryNode{}
+	for _, c := range categories {
+		node := nodes[c.ID]
+		if parent, ok := nodes[c.ParentID]; ok {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	sortCategoryNodes(roots, counts)
+	return roots
+}
+
+// sortCategoryNodes sorts the nodes and their descendants and sets their product counts
+func sortCategoryNodes(nodes []*CategoryNode, counts map[int]int) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if c := compareFold(nodes[i].Name, nodes[j].Name); c != 0 {
+			return c < 0
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+	for _, node := range nodes {
+		sortCategoryNodes(node.Children, counts)
+		node.ProductCount = counts[node.ID]
+		for _, child := range node.Children {
+			node.ProductCount += child.ProductCount
+		}
+	}
+}
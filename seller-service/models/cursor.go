@@ -0,0 +1,95 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// ErrInvalidCursor is returned when decoding a cursor that was not produced by Cursor.Encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks the position of a product in the results of a search sorted by SortBy. The
+// next page holds the products sorted after it, or the ones sorted before it when Backward
+// is set. Only the fields of the sort order and the ID, which breaks ties, are kept
+type Cursor struct {
+	SortBy      string  `json:"s,omitempty"`
+	Price       float64 `json:"p,omitempty"`
+	ProductName string  `json:"n,omitempty"`
+	SellerID    int     `json:"sid,omitempty"`
+	SellerName  string  `json:"sn,omitempty"`
+	Location    string  `json:"l,omitempty"`
+	Score       float64 `json:"r,omitempty"`
+	Distance    float64 `json:"d,omitempty"`
+	ID          int     `json:"id"`
+	Backward    bool    `json:"b,omitempty"`
+}
+
+// ProductPage is a page of products found by a search
+type ProductPage struct {
+	Products []Product
+	// HasMore tells whether there are products past the page in the direction it was read,
+	// after it or before it for a backward cursor
+	HasMore bool
+}
+
+// CursorAt returns the cursor of the product in the results of a search sorted by sortBy,
+// the seller fields are read from the Seller of the product
+func CursorAt(p Product, sortBy string, backward bool) Cursor {
+	c := Cursor{SortBy: sortBy, ID: p.ID, Backward: backward}
+	order, _ := ParseSortOrder(sortBy)
+	for _, key := range order {
+		switch key.Field {
+		case SortPrice:
+			c.Price = p.Price
+		case SortProductName:
+			c.ProductName = p.ProductName
+		case SortSellerID:
+			c.SellerID = p.SellerID
+		case SortSellerName:
+			c.SellerName = p.seller().Name
+		case SortLocation:
+			c.Location = p.seller().Location
+		case SortRelevance:
+			c.Score = p.Score
+		case SortDistance:
+			c.Distance = p.distance()
+		}
+	}
+	return c
+}
+
+// Product returns a product holding the sort keys of the cursor, to compare it with others
+func (c Cursor) Product() Product {
+	return Product{
+		ID:          c.ID,
+		SellerID:    c.SellerID,
+		ProductName: c.ProductName,
+		Price:       c.Price,
+		Score:       c.Score,
+		Distance:    &c.Distance,
+		Seller:      &Seller{ID: c.SellerID, Name: c.SellerName, Location: c.Location},
+	}
+}
+
+// Encode returns the cursor as an opaque string safe for query parameters
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor returned by Encode, it returns ErrInvalidCursor when s is not one
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	dec := json.NewDecoder(strings.NewReader(string(b)))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil || c.ID <= 0 {
+		return Cursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"strconv"
+)
+
+// Facets a search can count its results by
+const (
+	FacetLocation   = "location"
+	FacetPriceRange = "priceRange"
+	FacetSellerID   = "sellerId"
+)
+
+// MaxFacetBuckets is the number of buckets of the location and sellerId facets, the largest first
+const MaxFacetBuckets = 10
+
+// FacetBucket is the number of products matching a search that share a value
+type FacetBucket struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+	// Label is the name of the seller of a sellerId bucket
+	Label string `json:"label,omitempty"`
+	// Min and Max bound the prices of a priceRange bucket, Max is excluded and 0 for the last one
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// Facets are the buckets of each facet requested by a search
+type Facets map[string][]FacetBucket
+
+// PriceRange is a band of prices of the priceRange facet, from Min included to Max excluded
+type PriceRange struct {
+	Min, Max float64
+}
+
+// PriceRanges are the bands of the priceRange facet, the last one has no maximum
+var PriceRanges = []PriceRange{{0, 10}, {10, 50}, {50, 100}, {100, 500}, {500, 0}}
+
+// Name is the value of the bucket of the range, e.g. 10-50 or 500+ for the last one
+func (r PriceRange) Name() string {
+	min := strconv.FormatFloat(r.Min, 'f', -1, 64)
+	if r.Max == 0 {
+		return min + "+"
+	}
+	return min + "-" + strconv.FormatFloat(r.Max, 'f', -1, 64)
+}
+
+// Bucket returns the bucket of the range holding count products
+func (r PriceRange) Bucket(count int) FacetBucket {
+	b := FacetBucket{Value: r.Name(), Count: count, Min: &r.Min}
+	if r.Max != 0 {
+		b.Max = &r.Max
+	}
+	return b
+}
+
+// PriceRangeOf returns the index in PriceRanges of the band holding the price
+func PriceRangeOf(price float64) int {
+	for i, r := range PriceRanges {
+		if r.Max == 0 || price < r.Max {
+			return i
+		}
+	}
+	return len(PriceRanges) - 1
+}
+
+// WithoutFacetFilter returns a copy of the request without the filter of the facet, so the
+// counts of a facet show what selecting another of its values would return
+func (p *ProductRequest) WithoutFacetFilter(facet string) *ProductRequest {
+	req := *p
+	req.Cursor = nil
+	switch facet {
+	case FacetLocation:
+		req.Location = ""
+	case FacetPriceRange:
+		req.MinPrice, req.MaxPrice = 0, 0
+	case FacetSellerID:
+		req.SellerID = 0
+	}
+	return &req
+}
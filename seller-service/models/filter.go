@@ -0,0 +1,271 @@
+package models
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// Limits of a filter tree, so a request cannot make the store evaluate an arbitrarily large condition
+const (
+	// MaxFilterDepth is the number of levels of nested and, or and not nodes
+	MaxFilterDepth = 5
+	// MaxFilterNodes is the number of nodes of a tree, operators included
+	MaxFilterNodes = 50
+	// MaxFilterValues is the number of values of an in node
+	MaxFilterValues = 100
+)
+
+// Filter is a node of a filter tree, exactly one of its fields is set: the and, or and not
+// nodes combine the nodes below them and the others compare a field of the products
+//
+//	{"or": [
+//	  {"and": [{"eq": {"field": "location", "value": "IND"}}, {"range": {"field": "price", "lt": 50}}]},
+//	  {"not": {"in": {"field": "sellerId", "values": [1, 2]}}},
+//	  {"contains": {"field": "productName", "value": "smart"}}
+//	]}
+type Filter struct {
+	And      []*Filter    `json:"and,omitempty"`
+	Or       []*Filter    `json:"or,omitempty"`
+	Not      *Filter      `json:"not,omitempty"`
+	Eq       *FieldValue  `json:"eq,omitempty"`
+	In       *FieldValues `json:"in,omitempty"`
+	Range    *FieldRange  `json:"range,omitempty"`
+	Contains *FieldValue  `json:"contains,omitempty"`
+}
+
+// FieldValue compares a field with a value, a number or a string depending on the field
+type FieldValue struct {
+	Field string      `json:"field"`
+	Value interface{} `json:"value"`
+}
+
+// FieldValues compares a field with a list of values
+type FieldValues struct {
+	Field  string        `json:"field"`
+	Values []interface{} `json:"values"`
+}
+
+// FieldRange bounds a numeric field, the bounds that are not given are not checked
+type FieldRange struct {
+	Field string   `json:"field"`
+	Gt    *float64 `json:"gt,omitempty"`
+	Gte   *float64 `json:"gte,omitempty"`
+	Lt    *float64 `json:"lt,omitempty"`
+	Lte   *float64 `json:"lte,omitempty"`
+}
+
+// filterKind is the type of the values of a field
+type filterKind int
+
+const (
+	filterNumber filterKind = iota
+	filterInteger
+	filterString
+)
+
+// FilterFields lists the fields a filter can compare, in the order they are documented
+var FilterFields = []string{"productId", "sellerId", "productName", "description", "price", "quantity", "sellerName", "location", "country"}
+
+var filterKinds = map[string]filterKind{
+	"productId":   filterInteger,
+	"sellerId":    filterInteger,
+	"productName": filterString,
+	"description": filterString,
+	"price":       filterNumber,
+	"quantity":    filterInteger,
+	"sellerName":  filterString,
+	"location":    filterString,
+	"country":     filterString,
+}
+
+// validate records the violations of the tree below the node in errs, path is the name of
+// the node in the request. nodes counts the nodes seen so far to bound the size of the tree
+func (f *Filter) validate(path string, depth int, nodes *int, errs *validation.Errors) {
+	*nodes++
+	if *nodes == MaxFilterNodes+1 {
+		errs.Add("filter", fmt.Sprintf("must have at most %d nodes", MaxFilterNodes))
+	}
+	if *nodes > MaxFilterNodes {
+		return
+	}
+	if f == nil || f.operators() != 1 {
+		errs.Add(path, "must hold exactly one of and, or, not, eq, in, range, contains")
+		return
+	}
+	if (f.And != nil || f.Or != nil || f.Not != nil) && depth == MaxFilterDepth {
+		errs.Add(path, fmt.Sprintf("must be nested at most %d levels deep", MaxFilterDepth))
+		return
+	}
+	switch {
+	case f.And != nil || f.Or != nil:
+		op, children := "and", f.And
+		if f.Or != nil {
+			op, children = "or", f.Or
+		}
+		if len(children) == 0 {
+			errs.Add(path+"."+op, "must hold at least one filter")
+		}
+		for i, child := range children {
+			child.validate(fmt.Sprintf("%s.%s[%d]", path, op, i), depth+1, nodes, errs)
+		}
+	case f.Not != nil:
+		f.Not.validate(path+".not", depth+1, nodes, errs)
+	case f.Eq != nil:
+		if kind, ok := fieldKind(path+".eq", f.Eq.Field, errs); ok {
+			checkFilterValue(path+".eq.value", kind, f.Eq.Value, errs)
+		}
+	case f.In != nil:
+		kind, ok := fieldKind(path+".in", f.In.Field, errs)
+		if len(f.In.Values) == 0 || len(f.In.Values) > MaxFilterValues {
+			errs.Add(path+".in.values", fmt.Sprintf("must hold between 1 and %d values", MaxFilterValues))
+		} else if ok {
+			for i, v := range f.In.Values {
+				checkFilterValue(fmt.Sprintf("%s.in.values[%d]", path, i), kind, v, errs)
+			}
+		}
+	case f.Range != nil:
+		if kind, ok := fieldKind(path+".range", f.Range.Field, errs); ok && kind == filterString {
+			errs.Add(path+".range.field", "must be a numeric field")
+		}
+		if f.Range.Gt == nil && f.Range.Gte == nil && f.Range.Lt == nil && f.Range.Lte == nil {
+			errs.Add(path+".range", "must have at least one of gt, gte, lt, lte")
+		}
+		if f.Range.Gt != nil && f.Range.Gte != nil || f.Range.Lt != nil && f.Range.Lte != nil {
+			errs.Add(path+".range", "cannot combine gt with gte or lt with lte")
+		}
+	case f.Contains != nil:
+		if kind, ok := fieldKind(path+".contains", f.Contains.Field, errs); ok {
+			if kind != filterString {
+				errs.Add(path+".contains.field", "must be a text field")
+			} else {
+				checkFilterValue(path+".contains.value", kind, f.Contains.Value, errs)
+			}
+		}
+	}
+}
+
+// operators counts the fields set on the node
+func (f *Filter) operators() int {
+	n := 0
+	for _, set := range []bool{f.And != nil, f.Or != nil, f.Not != nil, f.Eq != nil, f.In != nil, f.Range != nil, f.Contains != nil} {
+		if set {
+			n++
+		}
+	}
+	return n
+}
+
+// fieldKind returns the kind of the field of a comparison, recording an error for unknown fields
+func fieldKind(path, field string, errs *validation.Errors) (filterKind, bool) {
+	kind, ok := filterKinds[field]
+	if !ok {
+		errs.Add(path+".field", "must be one of "+strings.Join(FilterFields, ", "))
+	}
+	return kind, ok
+}
+
+// checkFilterValue records an error when the value does not have the type of the field
+func checkFilterValue(path string, kind filterKind, v interface{}, errs *validation.Errors) {
+	switch kind {
+	case filterString:
+		s, ok := v.(string)
+		if !ok {
+			errs.Add(path, "must be a string")
+		} else if utf8.RuneCountInString(s) > 255 {
+			errs.Add(path, "must be at most 255 characters long")
+		}
+	case filterInteger:
+		if n, ok := v.(float64); !ok || n != math.Trunc(n) {
+			errs.Add(path, "must be an integer")
+		}
+	default:
+		if _, ok := v.(float64); !ok {
+			errs.Add(path, "must be a number")
+		}
+	}
+}
+
+// Matches tells whether the product of the seller passes the filter, it is evaluated the
+// way the mysql store evaluates it: strings are compared without regard to case. The filter
+// must be valid
+func (f *Filter) Matches(p Product, seller Seller) bool {
+	switch {
+	case f.And != nil:
+		for _, child := range f.And {
+			if !child.Matches(p, seller) {
+				return false
+			}
+		}
+		return true
+	case f.Or != nil:
+		for _, child := range f.Or {
+			if child.Matches(p, seller) {
+				return true
+			}
+		}
+		return false
+	case f.Not != nil:
+		return !f.Not.Matches(p, seller)
+	case f.Eq != nil:
+		return equalFold(filterValue(f.Eq.Field, p, seller), f.Eq.Value)
+	case f.In != nil:
+		v := filterValue(f.In.Field, p, seller)
+		for _, value := range f.In.Values {
+			if equalFold(v, value) {
+				return true
+			}
+		}
+		return false
+	case f.Range != nil:
+		v, _ := filterValue(f.Range.Field, p, seller).(float64)
+		r := f.Range
+		return (r.Gt == nil || v > *r.Gt) && (r.Gte == nil || v >= *r.Gte) &&
+			(r.Lt == nil || v < *r.Lt) && (r.Lte == nil || v <= *r.Lte)
+	case f.Contains != nil:
+		v, _ := filterValue(f.Contains.Field, p, seller).(string)
+		substr, _ := f.Contains.Value.(string)
+		return strings.Contains(strings.ToLower(v), strings.ToLower(substr))
+	}
+	return false
+}
+
+// filterValue returns the field of the product or of its seller, numbers as float64 like
+// the values of a decoded filter
+func filterValue(field string, p Product, seller Seller) interface{} {
+	switch field {
+	case "productId":
+		return float64(p.ID)
+	case "sellerId":
+		return float64(p.SellerID)
+	case "productName":
+		return p.ProductName
+	case "description":
+		return p.Description
+	case "price":
+		return p.Price
+	case "quantity":
+		return float64(p.Quantity)
+	case "sellerName":
+		return seller.Name
+	case "location":
+		return seller.Location
+	case "country":
+		if seller.Address == nil {
+			return ""
+		}
+		return seller.Address.Country
+	}
+	return nil
+}
+
+func equalFold(a, b interface{}) bool {
+	if s, ok := a.(string); ok {
+		t, ok := b.(string)
+		return ok && strings.EqualFold(s, t)
+	}
+	return a == b
+}
@@ -0,0 +1,62 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func decodeFilter(t *testing.T, s string) *Filter {
+	t.Helper()
+	var f Filter
+	if err := json.Unmarshal([]byte(s), &f); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", s, err)
+	}
+	return &f
+}
+
+func TestFilterMatches(t *testing.T) {
+	p := Product{ID: 3, SellerID: 2, ProductName: "Smartphone", Price: 30, Quantity: 4, Description: "OLED screen"}
+	seller := Seller{ID: 2, Name: "Seller B", Location: "US"}
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`{"eq": {"field": "location", "value": "us"}}`, true},
+		{`{"eq": {"field": "quantity", "value": 5}}`, false},
+		{`{"in": {"field": "sellerId", "values": [1, 2]}}`, true},
+		{`{"range": {"field": "price", "gt": 30}}`, false},
+		{`{"range": {"field": "price", "gte": 30, "lt": 31}}`, true},
+		{`{"contains": {"field": "description", "value": "oled"}}`, true},
+		{`{"not": {"contains": {"field": "productName", "value": "phone"}}}`, false},
+		{`{"and": [{"eq": {"field": "productId", "value": 3}}, {"eq": {"field": "sellerName", "value": "Seller A"}}]}`, false},
+		{`{"or": [{"eq": {"field": "productId", "value": 4}}, {"eq": {"field": "sellerName", "value": "SELLER B"}}]}`, true},
+	}
+	for _, tt := range tests {
+		f := decodeFilter(t, tt.filter)
+		if err := (&ProductRequest{Page: 1, PerPage: 1, Filter: f}).Validate(); err != nil {
+			t.Fatalf("Validate(%s): %v", tt.filter, err)
+		}
+		if got := f.Matches(p, seller); got != tt.want {
+			t.Errorf("Matches(%s) = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestFilterLimits(t *testing.T) {
+	leaves := make([]string, MaxFilterNodes)
+	for i := range leaves {
+		leaves[i] = `{"eq": {"field": "price", "value": 1}}`
+	}
+	wide := decodeFilter(t, `{"or": [`+strings.Join(leaves, ",")+`]}`)
+	err := (&ProductRequest{Page: 1, PerPage: 1, Filter: wide}).Validate()
+	if err == nil || err.Error() != "filter must have at most 50 nodes" {
+		t.Errorf("Expected the node limit to be enforced, got %v", err)
+	}
+
+	values := strings.Repeat("1,", MaxFilterValues) + "1"
+	in := decodeFilter(t, `{"in": {"field": "sellerId", "values": [`+values+`]}}`)
+	if err := (&ProductRequest{Page: 1, PerPage: 1, Filter: in}).Validate(); err == nil {
+		t.Error("Expected the value limit to be enforced")
+	}
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// ErrOrderNotFound is returned when an order id does not match any order
+var ErrOrderNotFound = errors.New("order not found")
+
+// MaxOrderLines is the number of lines an order holds at most
+const MaxOrderLines = 100
+
+// Order is a purchase of products by a buyer. Its lines keep the seller, name and price of the
+// products when it was placed, so the order outlives changes to them
+type Order struct {
+	ID      int         `json:"id"`
+	BuyerID int         `json:"buyerId" validate:"required,min=1"`
+	Lines   []OrderLine `json:"lines"`
+	// Total is the sum of the subtotals of the lines, set by the store
+	Total float64 `json:"total"`
+	// CreatedAt is set by the store when the order is placed
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// OrderLine is a quantity of a product in an order, the buyer gives the product and quantity
+// and the store sets the other fields from the product
+type OrderLine struct {
+	ProductID   int     `json:"productId" validate:"required,min=1"`
+	Quantity    int     `json:"quantity" validate:"required,min=1,max=10000"`
+	SellerID    int     `json:"sellerId"`
+	ProductName string  `json:"productName"`
+	UnitPrice   float64 `json:"unitPrice"`
+	Subtotal    float64 `json:"subtotal"`
+}
+
+// OrderLineError is returned when a line of an order cannot be placed, Err is ErrProductNotFound
+// or ErrInsufficientStock
+type OrderLineError struct {
+	ProductID int
+	// Available is the stock of the product when Err is ErrInsufficientStock
+	Available int
+	Err       error
+}
+
+func (e *OrderLineError) Error() string {
+	return fmt.Sprintf("product %d: %v", e.ProductID, e.Err)
+}
+
+func (e *OrderLineError) Unwrap() error {
+	return e.Err
+}
+
+// Validate checks the buyer and the lines of the order, a product is ordered on one line at most,
+// and returns every violation as validation.Errors. The fields of a line are named after its
+// index, e.g. lines[0].quantity
+func (o *Order) Validate() error {
+	errs := validation.Struct(o)
+	switch {
+	case len(o.Lines) == 0:
+		errs.Add("lines", "is required")
+	case len(o.Lines) > MaxOrderLines:
+		errs.Add("lines", "must hold at most "+strconv.Itoa(MaxOrderLines)+" lines")
+	}
+	ordered := make(map[int]bool)
+	for i := range o.Lines {
+		field := o.LineField(i, "")
+		for _, fe := range validation.Struct(&o.Lines[i]) {
+			errs.Add(field+fe.Field, fe.Message)
+		}
+		if id := o.Lines[i].ProductID; ordered[id] && !errs.Has(field+"productId") {
+			errs.Add(field+"productId", "is ordered on another line")
+		}
+		ordered[o.Lines[i].ProductID] = true
+	}
+	return errs.Err()
+}
+
+// LineField returns the name of a field of the line at index i in the validation errors
+func (o *Order) LineField(i int, field string) string {
+	return "lines[" + strconv.Itoa(i) + "]." + field
+}
+
+// ProductIDs returns the ids of the products of the lines
+func (o *Order) ProductIDs() []int {
+	ids := make([]int, len(o.Lines))
+	for i, line := range o.Lines {
+		ids[i] = line.ProductID
+	}
+	return ids
+}
+
+// Take checks the products, locked by the caller, have the quantities of the lines in stock and
+// records their seller, name and price in the lines along with the subtotals and the total. It
+// returns an *OrderLineError for the first line that cannot be placed. The caller then takes the
+// quantities off the stock of the products
+func (o *Order) Take(products map[int]Product) error {
+	for _, line := range o.Lines {
+		p, ok := products[line.ProductID]
+		if !ok {
+			return &OrderLineError{ProductID: line.ProductID, Err: ErrProductNotFound}
+		}
+		if p.Quantity < line.Quantity {
+			return &OrderLineError{ProductID: line.ProductID, Available: p.Quantity, Err: ErrInsufficientStock}
+		}
+	}
+	o.Total = 0
+	for i := range o.Lines {
+		line := &o.Lines[i]
+		p := products[line.ProductID]
+		line.SellerID, line.ProductName, line.UnitPrice = p.SellerID, p.ProductName, p.Price
+		line.Subtotal = roundCents(p.Price * float64(line.Quantity))
+		o.Total = roundCents(o.Total + line.Subtotal)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+func TestOrder_Validate(t *testing.T) {
+	order := Order{BuyerID: 7, Lines: []OrderLine{{ProductID: 1, Quantity: 1}, {ProductID: 2, Quantity: 10001}, {ProductID: 1, Quantity: 2}}}
+	var errs validation.Errors
+	if err := order.Validate(); !errors.As(err, &errs) || len(errs) != 2 || !errs.Has("lines[1].quantity") || !errs.Has("lines[2].productId") {
+		t.Errorf("Expected the quantity of the second line and the repeated product to be invalid, got %v", err)
+	}
+	if err := (&Order{BuyerID: 7, Lines: make([]OrderLine, MaxOrderLines+1)}).Validate(); !errors.As(err, &errs) || !errs.Has("lines") {
+		t.Errorf("Expected too many lines to be invalid, got %v", err)
+	}
+}
+
+func TestOrder_Take(t *testing.T) {
+	products := map[int]Product{
+		1: {ID: 1, SellerID: 3, ProductName: "Drone", Price: 19.99, Quantity: 2},
+		2: {ID: 2, SellerID: 4, ProductName: "Battery", Price: 0.1, Quantity: 5},
+	}
+	order := Order{Lines: []OrderLine{{ProductID: 2, Quantity: 3}, {ProductID: 1, Quantity: 3}}}
+	var lineErr *OrderLineError
+	if err := order.Take(products); !errors.As(err, &lineErr) || lineErr.ProductID != 1 || lineErr.Available != 2 || !errors.Is(err, ErrInsufficientStock) {
+		t.Errorf("Expected the drone to be short, got %v", err)
+	}
+	if order.Lines[0].UnitPrice != 0 || order.Total != 0 {
+		t.Errorf("Expected a refused order to be left unpriced, got %+v", order)
+	}
+
+	order.Lines[1].Quantity = 2
+	if err := order.Take(products); err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if line := order.Lines[0]; line.SellerID != 4 || line.ProductName != "Battery" || line.UnitPrice != 0.1 || line.Subtotal != 0.3 {
+		t.Errorf("Expected the line to snapshot the product, got %+v", line)
+	}
+	if order.Total != 40.28 {
+		t.Errorf("Expected a total of 40.28, got %v", order.Total)
+	}
+
+	order.Lines = append(order.Lines, OrderLine{ProductID: 9, Quantity: 1})
+	if err := order.Take(products); !errors.Is(err, ErrProductNotFound) {
+		t.Errorf("Expected ErrProductNotFound, got %v", err)
+	}
+}
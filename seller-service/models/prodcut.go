@@ -1,91 +1,167 @@
-// Package models - responsible for storing retrieving the data from mysql
+// Package models - contains the domain types of the service and the contracts of the stores persisting them
 package models
 
 import (
-	"database/sql"
+	"context"
 	"errors"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/db"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
 )
 
 // Product represents a product
 type Product struct {
-	ID          int     `json:"ID,omitempty"`
-	SellerID    int     `json:"sellerId"`
-	ProductName string  `json:"productName"`
-	Price       float64 `json:"price"`
-	Quantity    int     `json:"quantity"`
+	ID          int     `json:"id"`
+	SellerID    int     `json:"sellerId" validate:"required,min=1"`
+	ProductName string  `json:"productName" validate:"required,max=255"`
+	Price       float64 `json:"price" validate:"min=0,max=99999999.99"`
+	Quantity    int     `json:"quantity" validate:"min=1"`
+	Description string  `json:"description" validate:"max=2000"`
+	// CategoryID is the id of the category of the product, 0 when it has none
+	CategoryID int `json:"categoryId" validate:"min=0"`
+	// Attributes are the specs of the product by name, e.g. color or ram, see AttributeFilter
+	Attributes map[string]string `json:"attributes,omitempty"`
+	// Tags are free-form labels of the product, e.g. wireless
+	Tags []string `json:"tags,omitempty"`
+	// Score, Distance and Seller are set by the searches only, the stores do not save them
+	// Score is the relevance of the product to the full-text query of a search, 0 otherwise
+	Score float64 `json:"score,omitempty"`
+	// Distance is the distance in km from the seller to the near point of a search, nil otherwise
+	Distance *float64 `json:"distanceKm,omitempty"`
+	// Seller is the seller of the product in search results, nil otherwise
+	Seller *Seller `json:"seller,omitempty"`
 }
 
-// Save saves the product in the database
-func (p *Product) Save() error {
-	tx, err := db.DB.Begin()
-	if err != nil {
-		return err
+// seller returns the seller of the product, an empty one when it is not loaded
+func (p *Product) seller() Seller {
+	if p.Seller == nil {
+		return Seller{}
 	}
+	return *p.Seller
+}
 
-	stmt, err := tx.Prepare(`INSERT  INTO products (seller_id, product_name, price, quantity) VALUES (?,?,?,?)`)
-	if err != nil {
-		tx.Rollback()
-		return err
-	}
-	defer stmt.Close()
-	result, err := stmt.Exec(p.SellerID, p.ProductName, p.Price, p.Quantity)
-	if err != nil {
-		tx.Rollback()
-		return err
+// distance returns the distance of the product to the near point of a search, 0 when it has none
+func (p *Product) distance() float64 {
+	if p.Distance == nil {
+		return 0
 	}
+	return *p.Distance
+}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return err
+// Validate validates the product
+// Checks the fields and that the sellerId and categoryId are present in the stores, every
+// violation found is returned as validation.Errors
+func (p *Product) Validate(ctx context.Context, sellers SellerStore, categories CategoryStore) error {
+	errs := validation.Struct(p)
+	validateAttributes(p.Attributes, p.Tags, &errs)
+	if errs.Has("sellerId") {
+		return errs
 	}
-	p.ID = int(id)
-	err = tx.Commit()
-	if err != nil {
-		tx.Rollback()
+
+	// Check if SellerID is valid (existing seller)
+	_, err := sellers.GetSellerByID(ctx, p.SellerID)
+	if errors.Is(err, ErrSellerNotFound) {
+		errs.Add("sellerId", "seller does not exist")
+	} else if err != nil {
 		return err
 	}
-	return nil
-}
 
-// Validate validates the product
-// Checks if the sellerId is present or not in DB..
-// If not, it will through an error
-func (p *Product) Validate() error {
-	// Check if SellerID is valid (existing seller)
-	_, err := GetSellerByID(p.SellerID)
-	if err != nil {
-		return errors.New("invalid SellerID")
+	if p.CategoryID > 0 {
+		_, err = categories.GetCategoryByID(ctx, p.CategoryID)
+		if errors.Is(err, ErrCategoryNotFound) {
+			errs.Add("categoryId", "category does not exist")
+		} else if err != nil {
+			return err
+		}
 	}
+	return errs.Err()
+}
 
-	return nil
+// ProductInput holds the fields of a product given on its creation. The fields set by the
+// searches are left out, so a client sending them is refused
+type ProductInput struct {
+	// ID is accepted as the products have always been posted with it, the store sets it
+	ID          int               `json:"id"`
+	SellerID    int               `json:"sellerId"`
+	ProductName string            `json:"productName"`
+	Price       float64           `json:"price"`
+	Quantity    int               `json:"quantity"`
+	Description string            `json:"description"`
+	CategoryID  int               `json:"categoryId"`
+	Attributes  map[string]string `json:"attributes"`
+	Tags        []string          `json:"tags"`
 }
 
-// GetSellerByID retrieves a seller by ID from the database
-// Args:
-//
-//	id int: seller id
-//
-// Returns:
-//
-//	Seller: Seller Object
-//	error: root cause of error
-func GetSellerByID(id int) (Seller, error) {
-	var seller Seller
+// Product returns the product of the input, to be validated and saved
+func (in *ProductInput) Product() Product {
+	return Product{
+		SellerID:    in.SellerID,
+		ProductName: in.ProductName,
+		Price:       in.Price,
+		Quantity:    in.Quantity,
+		Description: in.Description,
+		CategoryID:  in.CategoryID,
+		Attributes:  in.Attributes,
+		Tags:        in.Tags,
+	}
+}
 
-	row := db.DB.QueryRow(`
-		SELECT id, name, location
-		FROM sellers
-		WHERE id = ?
-	`, id)
+// ProductUpdate holds the fields of a product that can be changed, nil fields are left unchanged
+type ProductUpdate struct {
+	ProductName *string  `json:"productName" validate:"required,max=255"`
+	Price       *float64 `json:"price" validate:"min=0,max=99999999.99"`
+	Quantity    *int     `json:"quantity" validate:"min=0"`
+	Description *string  `json:"description" validate:"max=2000"`
+	// CategoryID moves the product to another category, 0 removes it from its category
+	CategoryID *int `json:"categoryId" validate:"min=0"`
+	// Attributes and Tags replace those of the product when they are not nil, {} and []
+	// remove them all
+	Attributes map[string]string `json:"attributes"`
+	Tags       []string          `json:"tags"`
+}
 
-	err := row.Scan(&seller.ID, &seller.Name, &seller.Location)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return seller, errors.New("seller not found")
+// Validate checks the fields being updated, when complete is set every field but the description, category, attributes and tags must be present.
+// A quantity of 0 is allowed here so a product can be marked as sold out
+func (u *ProductUpdate) Validate(complete bool) error {
+	errs := validation.Struct(u)
+	validateAttributes(u.Attributes, u.Tags, &errs)
+	if complete {
+		if u.ProductName == nil {
+			errs.Add("productName", "is required")
+		}
+		if u.Price == nil {
+			errs.Add("price", "is required")
+		}
+		if u.Quantity == nil {
+			errs.Add("quantity", "is required")
 		}
-		return seller, err
 	}
+	if u.ProductName == nil && u.Price == nil && u.Quantity == nil && u.Description == nil && u.CategoryID == nil && u.Attributes == nil && u.Tags == nil && !complete {
+		return ErrNothingToUpdate
+	}
+	return errs.Err()
+}
 
-	return seller, nil
+// Apply copies the fields being updated onto the product
+func (u *ProductUpdate) Apply(p *Product) {
+	if u.ProductName != nil {
+		p.ProductName = *u.ProductName
+	}
+	if u.Price != nil {
+		p.Price = *u.Price
+	}
+	if u.Quantity != nil {
+		p.Quantity = *u.Quantity
+	}
+	if u.Description != nil {
+		p.Description = *u.Description
+	}
+	if u.CategoryID != nil {
+		p.CategoryID = *u.CategoryID
+	}
+	if u.Attributes != nil {
+		p.Attributes, _ = NormalizeAttributes(u.Attributes, nil)
+	}
+	if u.Tags != nil {
+		_, p.Tags = NormalizeAttributes(nil, u.Tags)
+	}
 }
@@ -1,21 +1,56 @@
 package models
 
 import (
-	"encoding/json"
-	"errors"
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/db"
+	"fmt"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
 )
 
-// ProductRequest represents the fields that are used to filter the records
+// ProductRequest represents the fields that are used to filter the records, a CategoryID
+// matches the products of the category and of its descendants and a Location the sellers
+// whose location is exactly this one, without regard to case
 type ProductRequest struct {
-	ProductName string  `json:"productName"`
-	DesiredQty  int     `json:"desiredQty"`
-	Location    string  `json:"location"`
-	MinPrice    float64 `json:"minPrice"`
-	MaxPrice    float64 `json:"maxPrice"`
+	SellerID    int     `json:"sellerId" validate:"min=0"`
+	ProductName string  `json:"productName" validate:"max=255"`
+	DesiredQty  int     `json:"desiredQty" validate:"min=0"`
+	Location    string  `json:"location" validate:"max=255"`
+	MinPrice    float64 `json:"minPrice" validate:"min=0"`
+	MaxPrice    float64 `json:"maxPrice" validate:"min=0"`
+	CategoryID  int     `json:"category" validate:"min=0"`
 	SortBy      string  `json:"sortBy"`
-	Page        uint64  `json:"page"`
-	PerPage     uint64  `json:"perPage"`
+	Page        uint64  `json:"page" validate:"min=1"`
+	PerPage     uint64  `json:"perPage" validate:"min=1,max=100"`
+	// Query is a full-text query on the name and description, see ParseTextQuery
+	Query string `json:"q" validate:"max=255"`
+	// Facets are the facets to count the matching products by, see FacetLocation
+	Facets []string `json:"facets"`
+	// Attributes are conditions on the attributes of the products, see ParseAttributeFilter
+	Attributes []AttributeFilter `json:"attributes"`
+	// Tags are the tags the products must all carry
+	Tags []string `json:"tags"`
+	// Countries are ISO 3166-1 alpha-2 codes, the products of sellers in any of them match
+	Countries []string `json:"country"`
+	// Near, when set, keeps the products of the sellers with coordinates and gives each its
+	// distance to the point, within RadiusKm when it is not 0
+	Near     *GeoPoint `json:"near"`
+	RadiusKm float64   `json:"radiusKm" validate:"min=0,max=20016"`
+	// Filter is a tree of conditions the products must also match, see Filter
+	Filter *Filter `json:"filter"`
+	// Cursor, when set, replaces Page: the page starts right after the product it marks
+	Cursor *Cursor `json:"-"`
+	// Synonyms expand the product name and the full-text query, see ProductNames and TextQuery
+	Synonyms Synonyms `json:"-"`
+}
+
+// ProductNames returns the names the product name filter matches, ProductName and its
+// expansions by the synonyms
+func (p *ProductRequest) ProductNames() []string {
+	return append([]string{p.ProductName}, p.Synonyms.ExpandName(p.ProductName)...)
+}
+
+// TextQuery returns the parsed full-text query, its terms expanded by the synonyms
+func (p *ProductRequest) TextQuery() TextQuery {
+	return p.Synonyms.ExpandQuery(ParseTextQuery(p.Query))
 }
 
 // NewProductRequest Creates a new ProductRequest
@@ -23,80 +58,65 @@ func NewProductRequest(productName string, desiredQty int, location string, minP
 	return &ProductRequest{ProductName: productName, DesiredQty: desiredQty, Location: location, MinPrice: minPrice, MaxPrice: maxPrice, SortBy: sortBy, Page: page, PerPage: perPage}
 }
 
-// SearchProducts - This frames the sql queries based on the fields supplied to ProductRequest
-// It filters the matching records and returns them and error if any
-//
-// Returns:
-//
-//	[]byte, error
-func (p *ProductRequest) SearchProducts() ([]byte, error) {
-	if p.MinPrice > p.MaxPrice {
-		return nil, errors.New("Minimum price cannot be greater than maximum price")
+// Validate checks the filters of the request and that they are consistent with each other,
+// every violation is returned as validation.Errors. A MaxPrice of 0 means no maximum
+func (p *ProductRequest) Validate() error {
+	errs := validation.Struct(p)
+	if p.MaxPrice > 0 && p.MinPrice > p.MaxPrice {
+		errs.Add("maxPrice", "cannot be less than minPrice")
 	}
-
-	offset := (p.Page - 1) * p.PerPage
-
-	var query = "SELECT p.* FROM products AS p INNER JOIN sellers AS s ON p.seller_id = s.id WHERE 1=1"
-	var args []interface{}
-
-	if p.ProductName != "" {
-		query += " AND p.product_name LIKE ? "
-		args = append(args, "%"+p.ProductName+"%")
+	if p.Query != "" && len(ParseTextQuery(p.Query)) == 0 {
+		errs.Add("q", "must hold a word of at least 3 characters that is not a stopword")
 	}
-	if p.DesiredQty > 0 {
-		query += " AND p.quantity >= ? "
-		args = append(args, p.DesiredQty)
+	if order, err := ParseSortOrder(p.SortBy); err != nil {
+		errs.Add("sortBy", err.Error())
+	} else if order.Has(SortRelevance) && p.Query == "" {
+		errs.Add("sortBy", "relevance requires a q query")
+	} else if order.Has(SortDistance) && p.Near == nil {
+		errs.Add("sortBy", "distance requires a near point")
 	}
-
-	if p.Location != "" {
-		query += " AND s.location LIKE ? "
-		args = append(args, "%"+p.Location+"%")
+	validateCountries(p.Countries, &errs)
+	if p.Near != nil && !p.Near.valid() {
+		errs.Add("near", "must have a latitude between -90 and 90 and a longitude between -180 and 180")
 	}
-	if p.MinPrice > 0 {
-		query += " AND p.price >= ?"
-		args = append(args, p.MinPrice)
+	if p.RadiusKm > 0 && p.Near == nil {
+		errs.Add("radiusKm", "requires a near point")
 	}
-	if p.MaxPrice > 0 {
-		query += " AND p.price <= ?"
-		args = append(args, p.MaxPrice)
+	for _, facet := range p.Facets {
+		if facet != FacetLocation && facet != FacetPriceRange && facet != FacetSellerID {
+			errs.Add("facets", "must be a list of location, priceRange and sellerId")
+			break
+		}
 	}
-
-	// Sort by the specified field
-	switch p.SortBy {
-	case "price":
-		query += " ORDER BY p.price"
-	case "productName":
-		query += " ORDER BY p.product_name"
-	case "sellerId":
-		query += " ORDER BY p.seller_id"
-	case "productId":
-		query += " ORDER BY p.id"
+	validateAttributeFilters(p.Attributes, &errs)
+	if len(p.Tags) > MaxProductTags {
+		errs.Add("tags", fmt.Sprintf("must hold at most %d tags", MaxProductTags))
 	}
-
-	// Add pagination to the query
-	query += " LIMIT ? OFFSET ?"
-	args = append(args, p.PerPage, offset)
-
-	rows, err := db.DB.Query(query, args...)
-	if err != nil {
-		return nil, err
+	if p.Filter != nil {
+		nodes := 0
+		p.Filter.validate("filter", 1, &nodes, &errs)
 	}
-	defer rows.Close()
-
-	// Process the result set and create a list of products
-	var products []Product
-	for rows.Next() {
-		var product Product
-		err := rows.Scan(&product.ID, &product.SellerID, &product.ProductName, &product.Price, &product.Quantity)
-		if err != nil {
-			return nil, err
+	if p.Cursor != nil {
+		if p.Cursor.SortBy != p.SortBy {
+			errs.Add("cursor", "was created for another sortBy")
+		}
+		if p.Page > 1 {
+			errs.Add("page", "cannot be combined with a cursor")
 		}
-		products = append(products, product)
 	}
-	// Convert the products to JSON
-	response, err := json.Marshal(&products)
-	if err != nil {
-		return nil, err
+	return errs.Err()
+}
+
+// SortOrder returns the parsed SortBy, the request must be valid
+func (p *ProductRequest) SortOrder() SortOrder {
+	order, _ := ParseSortOrder(p.SortBy)
+	return order
+}
+
+// Offset - number of records to skip to reach the requested page, pages start at 1
+func (p *ProductRequest) Offset() uint64 {
+	if p.Page == 0 || p.Cursor != nil {
+		return 0
 	}
-	return response, nil
+	return (p.Page - 1) * p.PerPage
 }
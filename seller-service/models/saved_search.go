@@ -0,0 +1,107 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// ErrSavedSearchNotFound is returned when a saved search id does not match any saved search
+var ErrSavedSearchNotFound = errors.New("saved search not found")
+
+// SavedSearch is a product search a buyer keeps to be alerted of the products that newly
+// match it, those created or restocked after it was saved. Its filters are those of a
+// ProductRequest with the same name
+type SavedSearch struct {
+	ID int `json:"id"`
+	// BuyerID identifies the buyer the alerts are for
+	BuyerID     int     `json:"buyerId" validate:"required,min=1"`
+	Name        string  `json:"name" validate:"max=100"`
+	ProductName string  `json:"productName" validate:"max=255"`
+	Location    string  `json:"location" validate:"max=255"`
+	MinPrice    float64 `json:"minPrice" validate:"min=0"`
+	MaxPrice    float64 `json:"maxPrice" validate:"min=0"`
+	DesiredQty  int     `json:"desiredQty" validate:"min=0"`
+	// CreatedAt is set by the store when the search is saved
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Validate checks the fields of the saved search, which must filter on at least the product
+// name, the location or the price so it does not match every product, and returns every
+// violation as validation.Errors. A MaxPrice of 0 means no maximum
+func (s *SavedSearch) Validate() error {
+	errs := validation.Struct(s)
+	if s.MaxPrice > 0 && s.MinPrice > s.MaxPrice {
+		errs.Add("maxPrice", "cannot be less than minPrice")
+	}
+	if strings.TrimSpace(s.ProductName) == "" && strings.TrimSpace(s.Location) == "" && s.MinPrice == 0 && s.MaxPrice == 0 {
+		errs.Add("productName", "is required unless the location or the price range is given")
+	}
+	return errs.Err()
+}
+
+// Request returns the product search the saved search stands for, its product name expanded
+// by the synonyms
+func (s *SavedSearch) Request(synonyms Synonyms) *ProductRequest {
+	req := NewProductRequest(s.ProductName, s.DesiredQty, s.Location, s.MinPrice, s.MaxPrice, "", 1, 1)
+	req.Synonyms = synonyms
+	return req
+}
+
+// Matches tells whether the product of the seller is found by the saved search, the way the
+// stores filter a ProductRequest: the name contains the product name or one of its synonyms
+// and the location is the same, without regard to case. Sold out products never match
+func (s *SavedSearch) Matches(p Product, seller Seller, synonyms Synonyms) bool {
+	if p.Quantity < s.wantedQty() {
+		return false
+	}
+	if s.MinPrice > 0 && p.Price < s.MinPrice {
+		return false
+	}
+	if s.MaxPrice > 0 && p.Price > s.MaxPrice {
+		return false
+	}
+	if s.Location != "" && !strings.EqualFold(seller.Location, s.Location) {
+		return false
+	}
+	if s.ProductName == "" {
+		return true
+	}
+	name := strings.ToLower(p.ProductName)
+	for _, wanted := range s.Request(synonyms).ProductNames() {
+		if strings.Contains(name, strings.ToLower(wanted)) {
+			return true
+		}
+	}
+	return false
+}
+
+// wantedQty is the stock a product needs to match, at least one unit
+func (s *SavedSearch) wantedQty() int {
+	if s.DesiredQty < 1 {
+		return 1
+	}
+	return s.DesiredQty
+}
+
+// SavedSearchFilter represents the fields that are used to list the saved searches of a buyer
+type SavedSearchFilter struct {
+	BuyerID int    `json:"buyerId" validate:"required,min=1"`
+	Page    uint64 `json:"page" validate:"min=1"`
+	PerPage uint64 `json:"perPage" validate:"min=1,max=100"`
+}
+
+// Validate checks the filter and returns every violation as validation.Errors
+func (f *SavedSearchFilter) Validate() error {
+	return validation.Struct(f).Err()
+}
+
+// Offset - number of records to skip to reach the requested page, pages start at 1
+func (f *SavedSearchFilter) Offset() uint64 {
+	if f.Page == 0 {
+		return 0
+	}
+	return (f.Page - 1) * f.PerPage
+}
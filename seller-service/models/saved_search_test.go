@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+func TestSavedSearch_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		search SavedSearch
+		fields []string
+	}{
+		{"valid", SavedSearch{BuyerID: 1, ProductName: "drone"}, nil},
+		{"price only", SavedSearch{BuyerID: 1, MaxPrice: 100}, nil},
+		{"no buyer", SavedSearch{ProductName: "drone"}, []string{"buyerId"}},
+		{"no filter", SavedSearch{BuyerID: 1, ProductName: "  ", DesiredQty: 2}, []string{"productName"}},
+		{"inverted prices", SavedSearch{BuyerID: 1, MinPrice: 50, MaxPrice: 10}, []string{"maxPrice"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.search.Validate()
+			var errs validation.Errors
+			if errors.As(err, &errs) != (tt.fields != nil) {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			for _, field := range tt.fields {
+				if !errs.Has(field) {
+					t.Errorf("Expected %s to be invalid, got %v", field, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSavedSearch_Matches(t *testing.T) {
+	search := SavedSearch{BuyerID: 1, ProductName: "earphones", Location: "ind", MaxPrice: 100, DesiredQty: 2}
+	seller := Seller{Location: "IND"}
+	synonyms := NewSynonyms([]SynonymGroup{{Terms: []string{"earphones", "earbuds"}}})
+	earbuds := Product{ProductName: "Wireless Earbuds", Price: 80, Quantity: 5}
+	tests := []struct {
+		name     string
+		product  Product
+		seller   Seller
+		synonyms Synonyms
+		want     bool
+	}{
+		{"synonym", earbuds, seller, synonyms, true},
+		{"without synonyms", earbuds, seller, nil, false},
+		{"other location", earbuds, Seller{Location: "US"}, synonyms, false},
+		{"too expensive", Product{ProductName: "Earphones", Price: 120, Quantity: 5}, seller, nil, false},
+		{"too few", Product{ProductName: "Earphones", Price: 80, Quantity: 1}, seller, nil, false},
+		{"contained", Product{ProductName: "Noise Cancelling EARPHONES", Price: 80, Quantity: 2}, seller, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := search.Matches(tt.product, tt.seller, tt.synonyms); got != tt.want {
+				t.Errorf("Matches = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if (&SavedSearch{MaxPrice: 100}).Matches(Product{ProductName: "Drone", Price: 80}, seller, nil) {
+		t.Error("Expected a sold out product not to match")
+	}
+}
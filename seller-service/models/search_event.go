@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// MaxSearchReportWindow is the longest time window a search report covers
+const MaxSearchReportWindow = 90 * 24 * time.Hour
+
+// SearchEvent is a product search served by the API, recorded for the search analytics
+type SearchEvent struct {
+	At time.Time
+	// Query is the text searched for, see ProductRequest.SearchText
+	Query string
+	// Filters are the names of the filters of the search, see ProductRequest.FilterNames
+	Filters []string
+	// Params are the parameters of the search as JSON
+	Params string
+	// Results is the number of products of the page returned
+	Results int
+	// FirstPage tells whether the page returned was the first one, an empty first page is a
+	// search without results while an empty later page is not
+	FirstPage bool
+	Latency   time.Duration
+}
+
+// NewSearchEvent describes the search of the request that returned results products
+func NewSearchEvent(req *ProductRequest, results int, at time.Time, latency time.Duration) SearchEvent {
+	params, _ := json.Marshal(req)
+	return SearchEvent{
+		At:        at,
+		Query:     req.SearchText(),
+		Filters:   req.FilterNames(),
+		Params:    string(params),
+		Results:   results,
+		FirstPage: req.Cursor == nil && req.Offset() == 0,
+		Latency:   latency,
+	}
+}
+
+// ZeroResults tells whether the search found no product at all
+func (e SearchEvent) ZeroResults() bool {
+	return e.FirstPage && e.Results == 0
+}
+
+// SearchText returns the text searched for, the full-text query or else the product name,
+// in lowercase with its spaces collapsed so the same query typed differently is counted once
+func (p *ProductRequest) SearchText() string {
+	text := p.Query
+	if text == "" {
+		text = p.ProductName
+	}
+	return strings.Join(strings.Fields(strings.ToLower(text)), " ")
+}
+
+// FilterNames returns the sorted names of the parameters narrowing the search, e.g.
+// [category maxPrice q], attribute filters are named attr.<name>
+func (p *ProductRequest) FilterNames() []string {
+	var names []string
+	add := func(given bool, name string) {
+		if given {
+			names = append(names, name)
+		}
+	}
+	add(p.Query != "", "q")
+	add(p.ProductName != "", "productName")
+	add(p.SellerID > 0, "sellerId")
+	add(p.DesiredQty > 1, "desiredQty")
+	add(p.Location != "", "location")
+	add(len(p.Countries) > 0, "country")
+	add(p.Near != nil, "near")
+	add(p.RadiusKm > 0, "radiusKm")
+	add(p.MinPrice > 0, "minPrice")
+	add(p.MaxPrice > 0, "maxPrice")
+	add(p.CategoryID > 0, "category")
+	add(len(p.Tags) > 0, "tags")
+	add(p.Filter != nil, "filter")
+	seen := make(map[string]bool)
+	for _, a := range p.Attributes {
+		add(!seen[a.Name], "attr."+a.Name)
+		seen[a.Name] = true
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SearchReportRequest selects the searches a report is computed over
+type SearchReportRequest struct {
+	// From and To bound the time window, From included and To excluded
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Limit int       `json:"limit" validate:"min=1,max=100"`
+	// ZeroResults keeps the searches without results
+	ZeroResults bool `json:"-"`
+}
+
+// Validate checks the request and returns every violation as validation.Errors
+func (r *SearchReportRequest) Validate() error {
+	errs := validation.Struct(r)
+	if !r.From.Before(r.To) {
+		errs.Add("to", "must be after from")
+	} else if r.To.Sub(r.From) > MaxSearchReportWindow {
+		errs.Add("from", "must be at most 90 days before to")
+	}
+	return errs.Err()
+}
+
+// SearchQueryStat summarizes the searches of a query over the window of a report
+type SearchQueryStat struct {
+	Query    string `json:"query"`
+	Searches int    `json:"searches"`
+	// ZeroResults is the number of the searches that found no product
+	ZeroResults    int       `json:"zeroResults"`
+	AvgLatencyMs   float64   `json:"avgLatencyMs"`
+	LastSearchedAt time.Time `json:"lastSearchedAt"`
+}
+
+// SearchFilterStat summarizes the searches using a combination of filters over the window
+// of a report
+type SearchFilterStat struct {
+	Filters     []string `json:"filters"`
+	Searches    int      `json:"searches"`
+	ZeroResults int      `json:"zeroResults"`
+}
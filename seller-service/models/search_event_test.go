@@ -0,0 +1,35 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestNewSearchEvent(t *testing.T) {
+	req := &ProductRequest{
+		ProductName: "  Wireless   MOUSE ",
+		DesiredQty:  1,
+		MaxPrice:    50,
+		CategoryID:  3,
+		Attributes:  []AttributeFilter{{Name: "color", Op: "=", Value: "red"}, {Name: "color", Op: "!=", Value: "blue"}, {Name: "dpi", Op: ">=", Value: "800"}},
+		Page:        1,
+		PerPage:     10,
+	}
+	at := time.Date(2024, 1, 31, 18, 0, 0, 0, time.UTC)
+	e := NewSearchEvent(req, 0, at, 3*time.Millisecond)
+	if e.Query != "wireless mouse" {
+		t.Errorf("Expected the query to be normalized, got %q", e.Query)
+	}
+	if want := []string{"attr.color", "attr.dpi", "category", "maxPrice", "productName"}; !reflect.DeepEqual(e.Filters, want) {
+		t.Errorf("Expected filters %v, got %v", want, e.Filters)
+	}
+	if !e.ZeroResults() || e.At != at || e.Params == "" {
+		t.Errorf("Unexpected event %+v", e)
+	}
+
+	req.Query, req.Page = "drone", 2
+	if e := NewSearchEvent(req, 0, at, 0); e.Query != "drone" || e.ZeroResults() {
+		t.Errorf("Expected an empty second page of drone not to be a search without results, got %+v", e)
+	}
+}
@@ -1,52 +1,137 @@
 package models
 
 import (
-	"github.com/ganesh-sai/buyer-seller-app/seller-service/db"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
 )
 
 // Seller represents a seller
 type Seller struct {
-	ID       int
-	Name     string
-	Location string
+	ID   int    `json:"id"`
+	Name string `json:"name" validate:"required,max=255"`
+	// Location is a free-text description of where the seller is, e.g. IND or Bengaluru
+	Location string `json:"location" validate:"required,max=255"`
+	// Address is the structured location of the seller, searches filter on its country and
+	// measure distances to its coordinates
+	Address *Address `json:"address,omitempty"`
 }
 
-// Save saves the seller in the database using a transaction and returns the inserted object and last inserted ID
-func (s *Seller) Save() error {
-	tx, err := db.DB.Begin()
-	if err != nil {
-		return err
+// Validate checks the fields of the seller and returns every violation as validation.Errors
+func (s *Seller) Validate() error {
+	errs := validation.Struct(s)
+	if s.Address != nil {
+		s.Address.validate(&errs)
 	}
+	return errs.Err()
+}
 
-	stmt, err := tx.Prepare(`
-		INSERT INTO sellers (name, location)
-		VALUES (?, ?)
-	`)
-	if err != nil {
-		tx.Rollback()
-		return err
+// Normalize prepares the seller to be saved: the country of its address in uppercase, or when
+// it has no address one holding the country its location names, see CountryCode, so sellers
+// created with a free-text location only are found by the country filters
+func (s *Seller) Normalize() {
+	if s.Address != nil {
+		s.Address = s.Address.normalized()
+		return
+	}
+	if code, ok := CountryCode(s.Location); ok {
+		s.Address = &Address{Country: code}
 	}
-	defer stmt.Close()
+}
+
+// SellerFilter represents the fields that are used to list sellers
+type SellerFilter struct {
+	// Location matches the sellers whose location is exactly this one, without regard to case
+	Location string `json:"location" validate:"max=255"`
+	// Countries are ISO 3166-1 alpha-2 codes, the sellers whose address is in any of them match
+	Countries []string `json:"country"`
+	Page      uint64   `json:"page" validate:"min=1"`
+	PerPage   uint64   `json:"perPage" validate:"min=1,max=100"`
+}
+
+// Validate checks the filter and returns every violation as validation.Errors
+func (f *SellerFilter) Validate() error {
+	errs := validation.Struct(f)
+	validateCountries(f.Countries, &errs)
+	return errs.Err()
+}
+
+// validateCountries records in errs the codes of a country filter that are not ISO 3166-1
+// alpha-2 codes
+func validateCountries(countries []string, errs *validation.Errors) {
+	for _, code := range countries {
+		if !IsCountryCode(code) {
+			errs.Add("country", "must be a list of ISO 3166-1 alpha-2 country codes, "+code+" is not one")
+			return
+		}
+	}
+}
 
-	result, err := stmt.Exec(s.Name, s.Location)
-	if err != nil {
-		tx.Rollback()
-		return err
+// MatchesCountry tells whether the address of the seller is in one of the countries, without
+// regard to case
+func (s Seller) MatchesCountry(countries []string) bool {
+	if s.Address == nil {
+		return false
 	}
+	for _, code := range countries {
+		if strings.EqualFold(s.Address.Country, code) {
+			return true
+		}
+	}
+	return false
+}
 
-	// Retrieve the last inserted ID
-	id, err := result.LastInsertId()
-	if err != nil {
-		tx.Rollback()
-		return err
+// Offset - number of records to skip to reach the requested page, pages start at 1
+func (f *SellerFilter) Offset() uint64 {
+	if f.Page == 0 {
+		return 0
 	}
+	return (f.Page - 1) * f.PerPage
+}
 
-	err = tx.Commit()
-	if err != nil {
-		tx.Rollback()
-		return err
+// SellerUpdate holds the fields of a seller that can be changed, nil fields are left unchanged
+type SellerUpdate struct {
+	Name     *string `json:"name" validate:"required,max=255"`
+	Location *string `json:"location" validate:"required,max=255"`
+	// Address replaces the address of the seller when it is not nil
+	Address *Address `json:"address"`
+}
+
+// Validate checks the fields being updated, when complete is set every field must be present
+func (u *SellerUpdate) Validate(complete bool) error {
+	errs := validation.Struct(u)
+	if u.Address != nil {
+		u.Address.validate(&errs)
+	}
+	if complete {
+		if u.Name == nil {
+			errs.Add("name", "is required")
+		}
+		if u.Location == nil {
+			errs.Add("location", "is required")
+		}
 	}
+	if u.Name == nil && u.Location == nil && u.Address == nil && !complete {
+		return ErrNothingToUpdate
+	}
+	return errs.Err()
+}
 
-	s.ID = int(id)
-	return nil
+// Apply copies the fields being updated onto the seller and normalizes it, see Seller.Normalize.
+// A new location without an address replaces an address holding only a country, which is
+// the one derived from the previous location
+func (u *SellerUpdate) Apply(s *Seller) {
+	if u.Name != nil {
+		s.Name = *u.Name
+	}
+	if u.Location != nil {
+		s.Location = *u.Location
+		if u.Address == nil && s.Address != nil && *s.Address == (Address{Country: s.Address.Country}) {
+			s.Address = nil
+		}
+	}
+	if u.Address != nil {
+		s.Address = u.Address
+	}
+	s.Normalize()
 }
@@ -0,0 +1,123 @@
+package models
+
+import (
+	"errors"
+	"strings"
+)
+
+// Fields products can be sorted on
+const (
+	SortPrice       = "price"
+	SortProductName = "productName"
+	SortSellerID    = "sellerId"
+	SortProductID   = "productId"
+	SortSellerName  = "sellerName"
+	SortLocation    = "location"
+	SortRelevance   = "relevance"
+	SortDistance    = "distance"
+)
+
+// SortFields lists the fields products can be sorted on, in the order they are documented
+var SortFields = []string{SortPrice, SortProductName, SortSellerID, SortProductID, SortSellerName, SortLocation, SortRelevance, SortDistance}
+
+// SortKey is a field of a sort order and its direction
+type SortKey struct {
+	Field      string
+	Descending bool
+}
+
+// SortOrder is the list of keys products are sorted on, the first one first. Products that
+// are equal on every key are sorted on their ID so the order, and so pages, are stable
+type SortOrder []SortKey
+
+// ParseSortOrder parses a comma separated list of fields, each sorted in ascending order or
+// in descending order when prefixed with -, e.g. -price,productName. The relevance field
+// lists the most relevant products first, -relevance reverses it, and the distance field the
+// closest products first
+func ParseSortOrder(s string) (SortOrder, error) {
+	var order SortOrder
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		key := SortKey{Field: strings.TrimPrefix(field, "-"), Descending: strings.HasPrefix(field, "-")}
+		if !isSortField(key.Field) {
+			return nil, errors.New("must be a comma separated list of " + strings.Join(SortFields, ", ") + ", prefixed with - for descending order")
+		}
+		if seen[key.Field] {
+			return nil, errors.New("lists " + key.Field + " more than once")
+		}
+		seen[key.Field] = true
+		order = append(order, key)
+	}
+	return order, nil
+}
+
+// Has tells whether the order sorts on the field
+func (o SortOrder) Has(field string) bool {
+	for _, k := range o {
+		if k.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func isSortField(field string) bool {
+	for _, f := range SortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareProducts orders two products the way a search sorted by order does: on each key,
+// strings without regard to case as under the mysql collation, relevance the most relevant
+// first and distance the closest first, then on the ID. The seller fields are read from the Seller of the products
+func CompareProducts(a, b Product, order SortOrder) int {
+	for _, key := range order {
+		var c int
+		switch key.Field {
+		case SortPrice:
+			c = compare(a.Price, b.Price)
+		case SortProductName:
+			c = compareFold(a.ProductName, b.ProductName)
+		case SortSellerID:
+			c = compare(a.SellerID, b.SellerID)
+		case SortProductID:
+			c = compare(a.ID, b.ID)
+		case SortSellerName:
+			c = compareFold(a.seller().Name, b.seller().Name)
+		case SortLocation:
+			c = compareFold(a.seller().Location, b.seller().Location)
+		case SortRelevance:
+			c = compare(b.Score, a.Score)
+		case SortDistance:
+			c = compare(a.distance(), b.distance())
+		}
+		if key.Descending {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return compare(a.ID, b.ID)
+}
+
+func compare[T int | float64](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareFold(a, b string) int {
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSortOrder(t *testing.T) {
+	tests := []struct {
+		sortBy  string
+		want    SortOrder
+		wantErr bool
+	}{
+		{"", nil, false},
+		{"price", SortOrder{{Field: "price"}}, false},
+		{"-price, productName", SortOrder{{Field: "price", Descending: true}, {Field: "productName"}}, false},
+		{"location,-sellerName,", SortOrder{{Field: "location"}, {Field: "sellerName", Descending: true}}, false},
+		{"rating", nil, true},
+		{"--price", nil, true},
+		{"price,-price", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSortOrder(tt.sortBy)
+		if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseSortOrder(%q) = %v, %v, want %v, error %v", tt.sortBy, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestCompareProducts(t *testing.T) {
+	a := Product{ID: 1, ProductName: "b", Price: 10, Seller: &Seller{Name: "Seller A", Location: "US"}}
+	b := Product{ID: 2, ProductName: "A", Price: 10, Seller: &Seller{Name: "seller b", Location: "IND"}}
+	tests := []struct {
+		order SortOrder
+		want  int
+	}{
+		{nil, -1},
+		{SortOrder{{Field: "price"}}, -1},
+		{SortOrder{{Field: "price"}, {Field: "productName"}}, 1},
+		{SortOrder{{Field: "productName", Descending: true}}, -1},
+		{SortOrder{{Field: "sellerName"}}, -1},
+		{SortOrder{{Field: "location"}}, 1},
+		{SortOrder{{Field: "productId", Descending: true}}, 1},
+	}
+	for _, tt := range tests {
+		if got := CompareProducts(a, b, tt.order); got != tt.want {
+			t.Errorf("CompareProducts(%v) = %d, want %d", tt.order, got, tt.want)
+		}
+	}
+}
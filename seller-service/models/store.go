@@ -0,0 +1,208 @@
+package models
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSellerNotFound is returned when a seller id does not match any seller
+var ErrSellerNotFound = errors.New("seller not found")
+
+// ErrSellerHasProducts is returned when deleting a seller that still has products without cascading
+var ErrSellerHasProducts = errors.New("seller still has products")
+
+// ErrProductNotFound is returned when a product id does not match any product
+var ErrProductNotFound = errors.New("product not found")
+
+// ErrNothingToUpdate is returned when validating a partial update that changes no field
+var ErrNothingToUpdate = errors.New("nothing to update")
+
+// ErrCategoryNotFound is returned when a category id does not match any category
+var ErrCategoryNotFound = errors.New("category not found")
+
+// ErrCategoryParentNotFound is returned when the parent of a category does not exist
+var ErrCategoryParentNotFound = errors.New("parent category not found")
+
+// ErrCategoryCycle is returned when moving a category under itself or one of its descendants
+var ErrCategoryCycle = errors.New("category cannot be moved under itself")
+
+// ErrCategoryTooDeep is returned when a category would be nested deeper than MaxCategoryDepth
+var ErrCategoryTooDeep = errors.New("category tree is too deep")
+
+// ErrCategoryNotEmpty is returned when deleting a category that still has subcategories or products
+var ErrCategoryNotEmpty = errors.New("category still has subcategories or products")
+
+// ProductStore is the persistence contract for products
+type ProductStore interface {
+	// CreateProduct saves the product and sets its ID. Returns ErrSellerNotFound or
+	// ErrCategoryNotFound if the product references an unknown seller or category
+	CreateProduct(ctx context.Context, p *Product) error
+	// GetProductByID retrieves a product by ID, returns ErrProductNotFound if it does not exist
+	GetProductByID(ctx context.Context, id int) (Product, error)
+	// UpdateProduct applies the update to the product and returns the updated product.
+	// Returns ErrProductNotFound if it does not exist, ErrCategoryNotFound if the update
+	// references an unknown category
+	UpdateProduct(ctx context.Context, id int, update ProductUpdate) (Product, error)
+	// DeleteProduct deletes the product, returns ErrProductNotFound if it does not exist
+	DeleteProduct(ctx context.Context, id int) error
+	// SearchProducts returns the page of products matching the request, sorted by req.SortBy
+	// then by ID. The page starts after req.Cursor when it is set, at req.Offset() otherwise
+	SearchProducts(ctx context.Context, req *ProductRequest) (ProductPage, error)
+	// CountProducts returns the number of products matching the filters of the request
+	CountProducts(ctx context.Context, req *ProductRequest) (int, error)
+	// FacetProducts counts the products matching the request by each of req.Facets. The
+	// counts of a facet ignore its own filter, see ProductRequest.WithoutFacetFilter
+	FacetProducts(ctx context.Context, req *ProductRequest) (Facets, error)
+	// ListAttributes summarizes the attributes of all the products, see SummarizeAttributes
+	ListAttributes(ctx context.Context) ([]AttributeSummary, error)
+	// CountProductNames returns the number of products of each name, names differing only in
+	// case are counted together under one of them
+	CountProductNames(ctx context.Context) (map[string]int, error)
+}
+
+// SellerStore is the persistence contract for sellers
+type SellerStore interface {
+	// CreateSeller saves the seller and sets its ID
+	CreateSeller(ctx context.Context, s *Seller) error
+	// GetSellerByID retrieves a seller by ID, returns ErrSellerNotFound if it does not exist
+	GetSellerByID(ctx context.Context, id int) (Seller, error)
+	// ListSellers returns the page of sellers matching the filter, ordered by ID
+	ListSellers(ctx context.Context, filter SellerFilter) ([]Seller, error)
+	// UpdateSeller applies the update to the seller and returns the updated seller.
+	// Returns ErrSellerNotFound if it does not exist
+	UpdateSeller(ctx context.Context, id int, update SellerUpdate) (Seller, error)
+	// DeleteSeller deletes the seller. When the seller still has products it returns
+	// ErrSellerHasProducts, unless cascade is set in which case the products are deleted too
+	DeleteSeller(ctx context.Context, id int, cascade bool) error
+}
+
+// CategoryStore is the persistence contract for the category tree
+type CategoryStore interface {
+	// CreateCategory saves the category and sets its ID. Returns ErrCategoryParentNotFound
+	// if its parent does not exist and ErrCategoryTooDeep if it is nested too deep
+	CreateCategory(ctx context.Context, c *Category) error
+	// GetCategoryByID retrieves a category by ID, returns ErrCategoryNotFound if it does not exist
+	GetCategoryByID(ctx context.Context, id int) (Category, error)
+	// UpdateCategory applies the update to the category, moving it and its descendants when
+	// the parent changes, and returns the updated category. Returns ErrCategoryNotFound if it
+	// does not exist, ErrCategoryParentNotFound, ErrCategoryCycle or ErrCategoryTooDeep if it
+	// cannot be moved under the new parent
+	UpdateCategory(ctx context.Context, id int, update CategoryUpdate) (Category, error)
+	// DeleteCategory deletes the category, returns ErrCategoryNotFound if it does not exist
+	// and ErrCategoryNotEmpty if it still has subcategories or products
+	DeleteCategory(ctx context.Context, id int) error
+	// CategoryTree returns the roots of the category tree, see CategoryTree
+	CategoryTree(ctx context.Context) ([]*CategoryNode, error)
+}
+
+// SearchLogStore is the persistence contract for the search analytics
+type SearchLogStore interface {
+	// RecordSearches saves the events
+	RecordSearches(ctx context.Context, events []SearchEvent) error
+	// TopSearchQueries returns the queries searched most within the window of the request,
+	// or with req.ZeroResults those searched most without results, at most req.Limit of them.
+	// Searches without query are left out
+	TopSearchQueries(ctx context.Context, req *SearchReportRequest) ([]SearchQueryStat, error)
+	// TopSearchFilters returns the combinations of filters used most within the window of the
+	// request, at most req.Limit of them. Searches without filter are left out
+	TopSearchFilters(ctx context.Context, req *SearchReportRequest) ([]SearchFilterStat, error)
+}
+
+// SynonymStore is the persistence contract for the synonym dictionary
+type SynonymStore interface {
+	// ListSynonymGroups returns every synonym group, ordered by ID
+	ListSynonymGroups(ctx context.Context) ([]SynonymGroup, error)
+	// CreateSynonymGroup saves the group and sets its ID
+	CreateSynonymGroup(ctx context.Context, g *SynonymGroup) error
+	// UpdateSynonymGroup replaces the terms of the group, returns ErrSynonymGroupNotFound if
+	// it does not exist
+	UpdateSynonymGroup(ctx context.Context, g *SynonymGroup) error
+	// DeleteSynonymGroup deletes the group, returns ErrSynonymGroupNotFound if it does not exist
+	DeleteSynonymGroup(ctx context.Context, id int) error
+}
+
+// SavedSearchStore is the persistence contract for the saved searches of the buyers
+type SavedSearchStore interface {
+	// CreateSavedSearch saves the search and sets its ID and CreatedAt
+	CreateSavedSearch(ctx context.Context, s *SavedSearch) error
+	// GetSavedSearchByID retrieves a saved search by ID, returns ErrSavedSearchNotFound if it
+	// does not exist
+	GetSavedSearchByID(ctx context.Context, id int) (SavedSearch, error)
+	// ListSavedSearches returns the page of saved searches of the buyer of the filter, ordered by ID
+	ListSavedSearches(ctx context.Context, filter SavedSearchFilter) ([]SavedSearch, error)
+	// UpdateSavedSearch replaces the name and filters of the search, its buyer and creation
+	// time are kept. Returns ErrSavedSearchNotFound if it does not exist
+	UpdateSavedSearch(ctx context.Context, s *SavedSearch) error
+	// DeleteSavedSearch deletes the saved search, returns ErrSavedSearchNotFound if it does not exist
+	DeleteSavedSearch(ctx context.Context, id int) error
+	// CandidateSavedSearches returns the saved searches whose location, price range and
+	// desired quantity accept the product of the seller, whatever their product name, which
+	// SavedSearch.Matches checks along with the synonyms
+	CandidateSavedSearches(ctx context.Context, p Product, seller Seller) ([]SavedSearch, error)
+}
+
+// BuyerStore is the persistence contract for buyers and their address books
+type BuyerStore interface {
+	// CreateBuyer saves the buyer and sets its ID and CreatedAt. Returns ErrBuyerEmailTaken if
+	// another buyer has its email
+	CreateBuyer(ctx context.Context, b *Buyer) error
+	// GetBuyerByID retrieves a buyer by ID, returns ErrBuyerNotFound if it does not exist
+	GetBuyerByID(ctx context.Context, id int) (Buyer, error)
+	// UpdateBuyer applies the update to the buyer and returns the updated buyer. Returns
+	// ErrBuyerNotFound if it does not exist, ErrBuyerEmailTaken if another buyer has the new email
+	UpdateBuyer(ctx context.Context, id int, update BuyerUpdate) (Buyer, error)
+	// DeleteBuyer deletes the buyer along with its addresses, saved searches and cart, returns
+	// ErrBuyerNotFound if it does not exist
+	DeleteBuyer(ctx context.Context, id int) error
+	// ListBuyerAddresses returns the address book of the buyer ordered by ID, returns
+	// ErrBuyerNotFound if the buyer does not exist
+	ListBuyerAddresses(ctx context.Context, buyerID int) ([]BuyerAddress, error)
+	// CreateBuyerAddress adds the address to the book of a.BuyerID and sets its ID. The first
+	// address of a buyer becomes its default address, as does one marked Default. Returns
+	// ErrBuyerNotFound if the buyer does not exist and ErrTooManyBuyerAddresses if its book
+	// is full
+	CreateBuyerAddress(ctx context.Context, a *BuyerAddress) error
+	// UpdateBuyerAddress replaces the address a.ID of the book of a.BuyerID, an address marked
+	// Default becomes the default one and the default address stays so otherwise. Returns
+	// ErrBuyerAddressNotFound if the buyer has no such address
+	UpdateBuyerAddress(ctx context.Context, a *BuyerAddress) error
+	// DeleteBuyerAddress deletes the address of the buyer, the oldest remaining one becomes the
+	// default address when it was. Returns ErrBuyerAddressNotFound if the buyer has no such address
+	DeleteBuyerAddress(ctx context.Context, buyerID, id int) error
+}
+
+// CartStore is the persistence contract for the carts of the buyers
+type CartStore interface {
+	// CartItems returns the items of the cart of the buyer in the order they were added, each
+	// with its live product and the seller of the product. Returns ErrBuyerNotFound if the
+	// buyer does not exist
+	CartItems(ctx context.Context, buyerID int) ([]CartItem, error)
+	// AddCartItem puts item.Quantity of the product in the cart of item.BuyerID, on top of the
+	// quantity already in it, sets item.Quantity to the quantity in the cart and records the
+	// current price and stock of the product. Returns ErrBuyerNotFound or ErrProductNotFound
+	// if the buyer or the product does not exist, ErrInsufficientStock if the cart would hold
+	// more than the stock and ErrTooManyCartItems if the cart is full
+	AddCartItem(ctx context.Context, item *CartItem) error
+	// UpdateCartItem sets the quantity of the product in the cart of item.BuyerID and records
+	// the current price and stock of the product. Returns ErrCartItemNotFound if the cart does
+	// not hold the product and ErrInsufficientStock if the quantity is more than the stock
+	UpdateCartItem(ctx context.Context, item *CartItem) error
+	// DeleteCartItem takes the product out of the cart of the buyer, returns ErrCartItemNotFound
+	// if the cart does not hold it
+	DeleteCartItem(ctx context.Context, buyerID, productID int) error
+	// ClearCart empties the cart of the buyer, returns ErrBuyerNotFound if the buyer does not exist
+	ClearCart(ctx context.Context, buyerID int) error
+}
+
+// OrderStore is the persistence contract for the orders of the buyers
+type OrderStore interface {
+	// PlaceOrder takes the quantities of the lines off the stock of their products and saves the
+	// order at once, so two orders never sell the same unit. It sets the lines from the products,
+	// see Order.Take, and the ID, Total and CreatedAt of the order, and takes the products out of
+	// the cart of the buyer. Returns ErrBuyerNotFound if the buyer does not exist and an
+	// *OrderLineError if a line cannot be placed, in which case nothing is changed
+	PlaceOrder(ctx context.Context, o *Order) error
+	// GetOrderByID retrieves an order with its lines by ID, returns ErrOrderNotFound if it does
+	// not exist
+	GetOrderByID(ctx context.Context, id int) (Order, error)
+}
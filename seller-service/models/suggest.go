@@ -0,0 +1,16 @@
+package models
+
+import (
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// SuggestRequest asks for the product names completing the beginning of a name
+type SuggestRequest struct {
+	Query string `json:"q" validate:"required,max=255"`
+	Limit int    `json:"limit" validate:"min=1,max=20"`
+}
+
+// Validate checks the request and returns every violation as validation.Errors
+func (r *SuggestRequest) Validate() error {
+	return validation.Struct(r).Err()
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+// ErrSynonymGroupNotFound is returned when a synonym group id does not match any group
+var ErrSynonymGroupNotFound = errors.New("synonym group not found")
+
+// MaxSynonymTerms is the number of terms a synonym group holds at most
+const MaxSynonymTerms = 20
+
+// MaxNameSynonyms bounds the names a product name filter is expanded to
+const MaxNameSynonyms = 20
+
+// SynonymGroup is a set of terms buyers use for the same thing, a search for one of them also
+// finds the products named with the others
+type SynonymGroup struct {
+	ID int `json:"id"`
+	// Terms are words or runs of words, e.g. earbuds or ear buds
+	Terms []string `json:"terms"`
+}
+
+// Normalize lowercases the terms and collapses their spaces, drops the duplicates and sorts them
+func (g *SynonymGroup) Normalize() {
+	seen := make(map[string]bool)
+	terms := make([]string, 0, len(g.Terms))
+	for _, term := range g.Terms {
+		term = normalizeTerm(term)
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	sort.Strings(terms)
+	g.Terms = terms
+}
+
+// Validate checks the group has between 2 and MaxSynonymTerms terms of at most 64 characters,
+// once normalized, and returns every violation as validation.Errors
+func (g *SynonymGroup) Validate() error {
+	var errs validation.Errors
+	distinct := make(map[string]bool)
+	for i, term := range g.Terms {
+		field := "terms[" + strconv.Itoa(i) + "]"
+		term = normalizeTerm(term)
+		switch {
+		case term == "":
+			errs.Add(field, "is required")
+		case len([]rune(term)) > 64:
+			errs.Add(field, "must be at most 64 characters")
+		}
+		distinct[term] = true
+	}
+	if len(distinct) < 2 {
+		errs.Add("terms", "must hold at least 2 distinct terms")
+	} else if len(g.Terms) > MaxSynonymTerms {
+		errs.Add("terms", "must hold at most "+strconv.Itoa(MaxSynonymTerms)+" terms")
+	}
+	return errs.Err()
+}
+
+// normalizeTerm is the form terms are saved and looked up in
+func normalizeTerm(term string) string {
+	return strings.Join(strings.Fields(strings.ToLower(term)), " ")
+}
+
+// Synonyms maps each term of a dictionary to the other terms of its groups
+type Synonyms map[string][]string
+
+// NewSynonyms builds the dictionary of the groups, a term of several groups has the terms of
+// all of them as synonyms
+func NewSynonyms(groups []SynonymGroup) Synonyms {
+	sets := make(map[string]map[string]bool)
+	for _, g := range groups {
+		for _, term := range g.Terms {
+			term = normalizeTerm(term)
+			if sets[term] == nil {
+				sets[term] = make(map[string]bool)
+			}
+			for _, other := range g.Terms {
+				if other = normalizeTerm(other); other != term {
+					sets[term][other] = true
+				}
+			}
+		}
+	}
+	synonyms := make(Synonyms, len(sets))
+	for term, set := range sets {
+		others := make([]string, 0, len(set))
+		for other := range set {
+			others = append(others, other)
+		}
+		sort.Strings(others)
+		synonyms[term] = others
+	}
+	return synonyms
+}
+
+// Has tells whether the term is in the dictionary
+func (s Synonyms) Has(term string) bool {
+	_, ok := s[normalizeTerm(term)]
+	return ok
+}
+
+// ExpandName returns the other names a product name filter matches: the name with one of its
+// words, or runs of words, replaced by a synonym. There are at most MaxNameSynonyms of them
+func (s Synonyms) ExpandName(name string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	words := strings.Fields(strings.ToLower(name))
+	seen := map[string]bool{strings.Join(words, " "): true}
+	var names []string
+	for i := range words {
+		for j := i + 1; j <= len(words); j++ {
+			for _, synonym := range s[strings.Join(words[i:j], " ")] {
+				expanded := strings.Join(append(append(append([]string{}, words[:i]...), synonym), words[j:]...), " ")
+				if seen[expanded] {
+					continue
+				}
+				if len(names) == MaxNameSynonyms {
+					return names
+				}
+				seen[expanded] = true
+				names = append(names, expanded)
+			}
+		}
+	}
+	return names
+}
+
+// ExpandQuery returns the query with the synonyms of its words and phrases as alternatives
+// of their terms, prefixes are not expanded
+func (s Synonyms) ExpandQuery(query TextQuery) TextQuery {
+	if len(s) == 0 {
+		return query
+	}
+	expanded := make(TextQuery, len(query))
+	for i, term := range query {
+		expanded[i] = term
+		if term.Prefix {
+			continue
+		}
+		for _, synonym := range s[strings.Join(term.Words, " ")] {
+			var words []string
+			for _, word := range Tokenize(synonym) {
+				if Indexed(word) {
+					words = append(words, word)
+				}
+			}
+			if len(words) > 0 {
+				expanded[i].Synonyms = append(expanded[i].Synonyms, TextTerm{Words: words, Phrase: len(words) > 1})
+			}
+		}
+	}
+	return expanded
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/validation"
+)
+
+func TestSynonymGroup_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		terms  []string
+		fields []string
+	}{
+		{"valid", []string{"Earphones", "earbuds"}, nil},
+		{"one term", []string{"earbuds"}, []string{"terms"}},
+		{"same term twice", []string{"Earbuds", " earbuds "}, []string{"terms"}},
+		{"empty term", []string{"earbuds", " ", "earphones"}, []string{"terms[1]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := SynonymGroup{Terms: tt.terms}
+			var errs validation.Errors
+			errors.As(g.Validate(), &errs)
+			var fields []string
+			for _, fe := range errs {
+				fields = append(fields, fe.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("Expected errors on %v, got %v", tt.fields, fields)
+			}
+		})
+	}
+
+	g := SynonymGroup{Terms: []string{"Ear  Buds", "earphones", "ear buds"}}
+	g.Normalize()
+	if !reflect.DeepEqual(g.Terms, []string{"ear buds", "earphones"}) {
+		t.Errorf("Unexpected normalized terms %v", g.Terms)
+	}
+}
+
+func TestSynonyms(t *testing.T) {
+	synonyms := NewSynonyms([]SynonymGroup{
+		{Terms: []string{"earphones", "earbuds", "ear buds"}},
+		{Terms: []string{"earphones", "headphones"}},
+		{Terms: []string{"tv", "television"}},
+	})
+	if want := []string{"ear buds", "earbuds", "headphones"}; !reflect.DeepEqual(synonyms["earphones"], want) {
+		t.Errorf("Expected the synonyms of both groups %v, got %v", want, synonyms["earphones"])
+	}
+	if !synonyms.Has("EarBuds") || synonyms.Has("mouse") {
+		t.Error("Has does not match the terms of the dictionary")
+	}
+
+	names := synonyms.ExpandName("Smart TV")
+	if !reflect.DeepEqual(names, []string{"smart television"}) {
+		t.Errorf("Unexpected expansions of Smart TV %v", names)
+	}
+	names = synonyms.ExpandName("wireless ear buds")
+	if !reflect.DeepEqual(names, []string{"wireless earbuds", "wireless earphones"}) {
+		t.Errorf("Unexpected expansions of wireless ear buds %v", names)
+	}
+	if names := synonyms.ExpandName("laptop"); len(names) != 0 {
+		t.Errorf("Expected no expansion, got %v", names)
+	}
+
+	query := synonyms.ExpandQuery(ParseTextQuery(`wireless earphones tele*`))
+	if got, want := query.BooleanMode(), `+wireless +(earphones "ear buds" earbuds headphones) +tele*`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+	if n := query[1].Matches(Tokenize("Wireless Earbuds")); n != 1 {
+		t.Errorf("Expected a synonym to match, got %d", n)
+	}
+}
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"strings"
+	"unicode"
+)
+
+// MinTokenLength is the length under which words are not indexed, innodb_ft_min_token_size
+const MinTokenLength = 3
+
+// stopwords are not indexed either, this is the default InnoDB stopword list
+var stopwords = map[string]bool{
+	"a": true, "about": true, "an": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"com": true, "de": true, "en": true, "for": true, "from": true, "how": true, "i": true, "in": true,
+	"is": true, "it": true, "la": true, "of": true, "on": true, "or": true, "that": true, "the": true,
+	"this": true, "to": true, "was": true, "what": true, "when": true, "where": true, "who": true,
+	"will": true, "with": true, "und": true, "www": true,
+}
+
+// TextQuery is a parsed full-text query, a product matches it when it matches every term
+type TextQuery []TextTerm
+
+// TextTerm is a word, a word prefix written wire* or a phrase written "wireless mouse"
+type TextTerm struct {
+	// Words are lower case, there is more than one only in phrases
+	Words  []string
+	Prefix bool
+	Phrase bool
+	// Synonyms are terms matching in place of this one, see Synonyms.ExpandQuery
+	Synonyms []TextTerm
+}
+
+// Tokenize splits text into the lower case words of letters and digits the full-text index holds
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Indexed tells whether the word is long enough and not a stopword, others never match
+func Indexed(word string) bool {
+	return len([]rune(word)) >= MinTokenLength && !stopwords[word]
+}
+
+// ParseTextQuery parses a query of words, prefixes and phrases. Punctuation separates words
+// like whitespace does, so "Mouse, Wireless" and "wireless mouse" are the same two words.
+// Words that are not indexed are dropped as they would match nothing
+func ParseTextQuery(q string) TextQuery {
+	var query TextQuery
+	for i, part := range strings.Split(q, `"`) {
+		if i%2 == 1 {
+			// between quotes
+			var words []string
+			for _, word := range Tokenize(part) {
+				if Indexed(word) {
+					words = append(words, word)
+				}
+			}
+			switch len(words) {
+			case 0:
+			case 1:
+				query = append(query, TextTerm{Words: words})
+			default:
+				query = append(query, TextTerm{Words: words, Phrase: true})
+			}
+			continue
+		}
+		for _, chunk := range strings.Fields(part) {
+			words := Tokenize(chunk)
+			for j, word := range words {
+				prefix := j == len(words)-1 && strings.HasSuffix(chunk, "*")
+				if prefix || Indexed(word) {
+					query = append(query, TextTerm{Words: []string{word}, Prefix: prefix})
+				}
+			}
+		}
+	}
+	return query
+}
+
+// BooleanMode renders the query for MATCH ... AGAINST (? IN BOOLEAN MODE), every term required.
+// A term with synonyms requires any of them, e.g. +(earphones earbuds "ear buds")
+func (q TextQuery) BooleanMode() string {
+	terms := make([]string, len(q))
+	for i, t := range q {
+		if len(t.Synonyms) == 0 {
+			terms[i] = "+" + t.booleanMode()
+			continue
+		}
+		alternatives := []string{t.booleanMode()}
+		for _, synonym := range t.Synonyms {
+			alternatives = append(alternatives, synonym.booleanMode())
+		}
+		terms[i] = "+(" + strings.Join(alternatives, " ") + ")"
+	}
+	return strings.Join(terms, " ")
+}
+
+// booleanMode renders the term without operator
+func (t TextTerm) booleanMode() string {
+	switch {
+	case t.Phrase:
+		return `"` + strings.Join(t.Words, " ") + `"`
+	case t.Prefix:
+		return t.Words[0] + "*"
+	}
+	return t.Words[0]
+}
+
+// Matches tells whether the words, as returned by Tokenize, hold the term or one of its synonyms
+// and how many times
+func (t TextTerm) Matches(words []string) int {
+	count := 0
+	for _, synonym := range t.Synonyms {
+		count += synonym.Matches(words)
+	}
+	for i := range words {
+		if i+len(t.Words) > len(words) {
+			break
+		}
+		matched := true
+		for j, w := range t.Words {
+			if t.Prefix && !strings.HasPrefix(words[i+j], w) || !t.Prefix && words[i+j] != w {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			count++
+		}
+	}
+	return count
+}
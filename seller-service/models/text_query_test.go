@@ -0,0 +1,52 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTextQuery(t *testing.T) {
+	tests := []struct {
+		q           string
+		booleanMode string
+	}{
+		{"wireless mouse", "+wireless +mouse"},
+		{"Mouse, Wireless", "+mouse +wireless"},
+		{"wire* mouse", "+wire* +mouse"},
+		{`"Wireless Mouse" pad`, `+"wireless mouse" +pad`},
+		{`the mouse for a PC`, "+mouse"},
+		{`"usb-c cable"`, `+"usb cable"`},
+		{`"mouse"`, "+mouse"},
+		{"usb-c*", "+usb +c*"},
+		{`+mouse -(keyboard) @2 ~x`, "+mouse +keyboard"},
+		{`"unterminated phrase`, `+"unterminated phrase"`},
+		{"a of", ""},
+	}
+	for _, tt := range tests {
+		if got := ParseTextQuery(tt.q).BooleanMode(); got != tt.booleanMode {
+			t.Errorf("ParseTextQuery(%q) = %q, want %q", tt.q, got, tt.booleanMode)
+		}
+	}
+}
+
+func TestTextTermMatches(t *testing.T) {
+	words := Tokenize("Wireless mouse with a wireless dongle")
+	tests := []struct {
+		term TextTerm
+		want int
+	}{
+		{TextTerm{Words: []string{"wireless"}}, 2},
+		{TextTerm{Words: []string{"wire"}}, 0},
+		{TextTerm{Words: []string{"wire"}, Prefix: true}, 2},
+		{TextTerm{Words: []string{"wireless", "mouse"}, Phrase: true}, 1},
+		{TextTerm{Words: []string{"mouse", "wireless"}, Phrase: true}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.term.Matches(words); got != tt.want {
+			t.Errorf("%+v.Matches = %d, want %d", tt.term, got, tt.want)
+		}
+	}
+	if got := Tokenize("Mouse, Wireless (2.4GHz)"); !reflect.DeepEqual(got, []string{"mouse", "wireless", "2", "4ghz"}) {
+		t.Errorf("Unexpected tokens %v", got)
+	}
+}
@@ -0,0 +1,36 @@
+// Package detach - contexts for the work following a change that is already saved, such as
+// refreshing an in-memory copy of the store, which must neither be cut short by the request
+// ending nor outlive a stuck database
+package detach
+
+import (
+	"context"
+	"time"
+)
+
+// WithTimeout returns a context carrying the values of ctx, e.g. its logger, but neither its
+// cancellation nor its deadline, cancelled after the timeout instead
+func WithTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(values{ctx}, timeout)
+}
+
+// values is a context that is never cancelled and holds the values of its parent
+type values struct {
+	parent context.Context
+}
+
+func (values) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (values) Done() <-chan struct{} {
+	return nil
+}
+
+func (values) Err() error {
+	return nil
+}
+
+func (v values) Value(key interface{}) interface{} {
+	return v.parent.Value(key)
+}
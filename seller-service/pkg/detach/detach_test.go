@@ -0,0 +1,31 @@
+package detach
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type key struct{}
+
+func TestWithTimeout(t *testing.T) {
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), key{}, "request"))
+	cancel()
+
+	ctx, stop := WithTimeout(parent, 20*time.Millisecond)
+	defer stop()
+	if err := ctx.Err(); err != nil {
+		t.Fatalf("Expected the cancellation of the parent to be ignored, got %v", err)
+	}
+	if got := ctx.Value(key{}); got != "request" {
+		t.Errorf("Expected the values of the parent, got %v", got)
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("Expected the context to have a deadline")
+	}
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Expected the timeout to cancel the context, got %v", ctx.Err())
+	}
+}
@@ -1,10 +1,26 @@
 // Package logging - responsible for logging related activities
+//
+// Records are written to Config.Output in logfmt or JSON, one per line, with the
+// time, level, prefix, message and any key/value fields bound to the logger:
+//
+//	time=2023-07-01T10:00:00Z level=info service=seller-service msg="Server started" addr=:8080
+//
+// When Output is a syslog writer each record is sent with the syslog priority
+// matching its level instead of the priority the writer was dialed with.
 package logging
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
 )
 
 const (
@@ -14,7 +30,13 @@ const (
 	ERROR
 )
 
-var logger *Logger
+// Output formats
+const (
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+)
+
+var logger = New(Config{Output: os.Stderr, LogLevel: INFO})
 var LogPrefixes = map[int]string{
 	DEBUG: "DEBUG",
 	INFO:  "INFO ",
@@ -22,10 +44,25 @@ var LogPrefixes = map[int]string{
 	ERROR: "ERROR",
 }
 
-//Logger ...
+// SyslogWriter is the part of *syslog.Writer used to log with the priority of each level
+type SyslogWriter interface {
+	Debug(m string) error
+	Info(m string) error
+	Warning(m string) error
+	Err(m string) error
+}
 
+// Logger writes structured records, child loggers created with With share the output of their parent
 type Logger struct {
 	Config
+	fields []interface{}
+	sink   *sink
+}
+
+// sink serializes the writes of a logger and all its children
+type sink struct {
+	mu  sync.Mutex
+	now func() time.Time
 }
 
 // GetLogger - gives a logger instance to log
@@ -38,10 +75,28 @@ type Config struct {
 	LogLevel int       // The LogLevel you want to log
 	Output   io.Writer // Where exactly you want to log
 	Prefix   string    // Any prefix that you want your log to have
+	Format   string    // FormatLogfmt (default) or FormatJSON
 }
 
-// Debugf - Logs formatted string with DEBUG severity
+// New creates a logger from the config, it does not change the logger returned by GetLogger
+func New(config Config) *Logger {
+	if config.Output == nil {
+		config.Output = os.Stderr
+	}
+	if config.Format == "" {
+		config.Format = FormatLogfmt
+	}
+	return &Logger{Config: config, sink: &sink{now: time.Now}}
+}
+
+// With - returns a child logger that adds the key/value pairs to every record it writes
+func (l *Logger) With(keyvals ...interface{}) *Logger {
+	child := *l
+	child.fields = append(append([]interface{}{}, l.fields...), keyvals...)
+	return &child
+}
 
+// Debugf - Logs formatted string with DEBUG severity
 func (l *Logger) Debugf(format string, n ...interface{}) {
 	l.Logf(DEBUG, format, n...)
 }
@@ -64,7 +119,7 @@ func (l *Logger) Errorf(format string, n ...interface{}) {
 // Logf - logs the formatted message
 func (l *Logger) Logf(level int, s string, n ...interface{}) {
 	if level >= l.LogLevel {
-		log.Println(l.LogPrefix(level), fmt.Sprintf(s, n...))
+		l.write(level, fmt.Sprintf(s, n...), nil)
 	}
 }
 
@@ -88,6 +143,40 @@ func (l *Logger) Error(n ...interface{}) {
 	l.Log(ERROR, n...)
 }
 
+// Log - logs the operands joined by spaces
+func (l *Logger) Log(level int, n ...interface{}) {
+	if level >= l.LogLevel {
+		l.write(level, strings.TrimSuffix(fmt.Sprintln(n...), "\n"), nil)
+	}
+}
+
+// Debugw - Logs the message and key/value pairs with DEBUG severity
+func (l *Logger) Debugw(msg string, keyvals ...interface{}) {
+	l.Logw(DEBUG, msg, keyvals...)
+}
+
+// Infow - Logs the message and key/value pairs with INFO severity
+func (l *Logger) Infow(msg string, keyvals ...interface{}) {
+	l.Logw(INFO, msg, keyvals...)
+}
+
+// Warnw - Logs the message and key/value pairs with WARN severity
+func (l *Logger) Warnw(msg string, keyvals ...interface{}) {
+	l.Logw(WARN, msg, keyvals...)
+}
+
+// Errorw - Logs the message and key/value pairs with ERROR severity
+func (l *Logger) Errorw(msg string, keyvals ...interface{}) {
+	l.Logw(ERROR, msg, keyvals...)
+}
+
+// Logw - logs the message with the key/value pairs in addition to the fields bound to the logger
+func (l *Logger) Logw(level int, msg string, keyvals ...interface{}) {
+	if level >= l.LogLevel {
+		l.write(level, msg, keyvals)
+	}
+}
+
 // LogPrefix - Appends any prefix that you wanted to have, like app name, component name
 func (l *Logger) LogPrefix(i int) (s string) {
 	if l.Prefix != "" {
@@ -103,14 +192,151 @@ func (l *Logger) LogLevelPrefix(level int) (s string) {
 	return prefix
 }
 
-func (l *Logger) Log(level int, n ...interface{}) {
-	if level >= l.LogLevel {
-		all := append([]interface{}{l.LogPrefix(level) + ":"}, n...)
-		log.Println(all...)
+// ParseLevel - converts a level name (debug, info, warn or error) to its LogLevel
+func ParseLevel(name string) (int, error) {
+	for level, prefix := range LogPrefixes {
+		if strings.EqualFold(strings.TrimSpace(prefix), name) {
+			return level, nil
+		}
 	}
+	return 0, fmt.Errorf("unknown log level %q", name)
 }
 
 // Init Creates a logger
 func Init(config Config) {
-	logger = &Logger{config}
+	logger = New(config)
+}
+
+func (l *Logger) write(level int, msg string, keyvals []interface{}) {
+	record := []interface{}{
+		"time", l.sink.now().UTC().Format(time.RFC3339Nano),
+		"level", strings.ToLower(strings.TrimSpace(LogPrefixes[level])),
+	}
+	if l.Prefix != "" {
+		record = append(record, "service", l.Prefix)
+	}
+	record = append(record, "msg", msg)
+	record = append(record, l.fields...)
+	record = append(record, keyvals...)
+
+	var buf bytes.Buffer
+	if l.Format == FormatJSON {
+		encodeJSON(&buf, record)
+	} else {
+		encodeLogfmt(&buf, record)
+	}
+
+	l.sink.mu.Lock()
+	defer l.sink.mu.Unlock()
+	if w, ok := l.Output.(SyslogWriter); ok {
+		writeSyslog(w, level, buf.String())
+		return
+	}
+	buf.WriteByte('\n')
+	_, _ = l.Output.Write(buf.Bytes())
+}
+
+// writeSyslog sends the line with the syslog priority of the level
+func writeSyslog(w SyslogWriter, level int, line string) {
+	switch level {
+	case DEBUG:
+		_ = w.Debug(line)
+	case INFO:
+		_ = w.Info(line)
+	case WARN:
+		_ = w.Warning(line)
+	default:
+		_ = w.Err(line)
+	}
+}
+
+// pairs calls fn for every key/value pair, a key without value gets "(MISSING)"
+func pairs(keyvals []interface{}, fn func(key string, value interface{})) {
+	for i := 0; i < len(keyvals); i += 2 {
+		var value interface{} = "(MISSING)"
+		if i+1 < len(keyvals) {
+			value = keyvals[i+1]
+		}
+		fn(fmt.Sprint(keyvals[i]), value)
+	}
+}
+
+func encodeLogfmt(buf *bytes.Buffer, keyvals []interface{}) {
+	pairs(keyvals, func(key string, value interface{}) {
+		if buf.Len() > 0 {
+			buf.WriteByte(' ')
+		}
+		buf.WriteString(logfmtKey(key))
+		buf.WriteByte('=')
+		buf.WriteString(logfmtValue(value))
+	})
+}
+
+func logfmtKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' {
+			return '_'
+		}
+		return r
+	}, key)
+}
+
+func logfmtValue(value interface{}) string {
+	s := stringify(value)
+	if s == "" || strings.IndexFunc(s, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || !unicode.IsPrint(r)
+	}) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func encodeJSON(buf *bytes.Buffer, keyvals []interface{}) {
+	buf.WriteByte('{')
+	pairs(keyvals, func(key string, value interface{}) {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		buf.Write(k)
+		buf.WriteByte(':')
+		switch value.(type) {
+		case error, fmt.Stringer:
+			value = stringify(value)
+		}
+		v, err := json.Marshal(value)
+		if err != nil {
+			v, _ = json.Marshal(fmt.Sprint(value))
+		}
+		buf.Write(v)
+	})
+	buf.WriteByte('}')
+}
+
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+	return fmt.Sprint(value)
+}
+
+// contextKey is the key of the logger stored in a context
+type contextKey struct{}
+
+// NewContext - returns a copy of ctx carrying the logger
+func NewContext(ctx context.Context, l *Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, l)
+}
+
+// FromContext - returns the logger carried by ctx, or the logger returned by GetLogger if there is none
+func FromContext(ctx context.Context) *Logger {
+	if l, ok := ctx.Value(contextKey{}).(*Logger); ok {
+		return l
+	}
+	return GetLogger()
 }
@@ -0,0 +1,113 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+func newTestLogger(buf *bytes.Buffer, format string) *Logger {
+	l := New(Config{Output: buf, Prefix: "test", LogLevel: INFO, Format: format})
+	l.sink.now = func() time.Time { return time.Date(2023, 7, 1, 10, 0, 0, 0, time.UTC) }
+	return l
+}
+
+func TestLogger_Logfmt(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, FormatLogfmt)
+	l.Infow("Server started", "addr", ":8080", "err", errors.New("bad thing"), "empty", "", "odd")
+	want := `time=2023-07-01T10:00:00Z level=info service=test msg="Server started" addr=:8080 err="bad thing" empty="" odd=(MISSING)` + "\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, FormatJSON).With("request_id", "abc")
+	l.Warnw("slow query", "ms", 120, "err", errors.New("timeout"))
+	var record map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", buf.String(), err)
+	}
+	want := map[string]interface{}{
+		"time": "2023-07-01T10:00:00Z", "level": "warn", "service": "test", "msg": "slow query",
+		"request_id": "abc", "ms": float64(120), "err": "timeout",
+	}
+	for k, v := range want {
+		if record[k] != v {
+			t.Errorf("Expected %s=%v, got %v", k, v, record[k])
+		}
+	}
+}
+
+func TestLogger_WithDoesNotLeakIntoParent(t *testing.T) {
+	var buf bytes.Buffer
+	parent := newTestLogger(&buf, FormatLogfmt)
+	child := parent.With("component", "search")
+	child.With("request_id", "1").Info("child")
+	parent.Info("parent")
+	want := "time=2023-07-01T10:00:00Z level=info service=test msg=child component=search request_id=1\n" +
+		"time=2023-07-01T10:00:00Z level=info service=test msg=parent\n"
+	if buf.String() != want {
+		t.Errorf("Expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestLogger_Level(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, FormatLogfmt)
+	l.Debugf("hidden %d", 1)
+	if buf.Len() != 0 {
+		t.Errorf("Expected debug records to be dropped, got %q", buf.String())
+	}
+	l.Errorf("shown %d", 2)
+	if !bytes.Contains(buf.Bytes(), []byte(`level=error service=test msg="shown 2"`)) {
+		t.Errorf("Unexpected record %q", buf.String())
+	}
+}
+
+type fakeSyslog struct {
+	bytes.Buffer
+	priorities []string
+}
+
+func (f *fakeSyslog) Debug(string) error { f.priorities = append(f.priorities, "debug"); return nil }
+func (f *fakeSyslog) Info(string) error  { f.priorities = append(f.priorities, "info"); return nil }
+func (f *fakeSyslog) Warning(string) error {
+	f.priorities = append(f.priorities, "warning")
+	return nil
+}
+func (f *fakeSyslog) Err(string) error { f.priorities = append(f.priorities, "err"); return nil }
+
+func TestLogger_SyslogPriorities(t *testing.T) {
+	out := &fakeSyslog{}
+	l := New(Config{Output: out, LogLevel: DEBUG})
+	l.Debug("d")
+	l.Info("i")
+	l.Warn("w")
+	l.Error("e")
+	want := []string{"debug", "info", "warning", "err"}
+	if len(out.priorities) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, out.priorities)
+	}
+	for i := range want {
+		if out.priorities[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, out.priorities)
+		}
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing written through Write, got %q", out.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	if level, err := ParseLevel("warn"); err != nil || level != WARN {
+		t.Errorf("ParseLevel(warn) = %d, %v", level, err)
+	}
+	if _, err := ParseLevel("trace"); err == nil {
+		t.Error("Expected an error for an unknown level")
+	}
+}
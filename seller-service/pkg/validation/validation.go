@@ -0,0 +1,172 @@
+// Package validation - checks structs against the rules declared in their `validate` tags
+//
+//	type Seller struct {
+//		Name string `json:"name" validate:"required,max=255"`
+//	}
+//
+// The rules of a tag are separated by commas:
+//
+//	required   strings must not be blank, other values must not be their zero value
+//	min=N      numbers must be at least N, strings must have at least N characters
+//	max=N      numbers must be at most N, strings must have at most N characters
+//	oneof=a b  the value must be one of the space separated values, an empty string passes
+//
+// The rules of a pointer field apply to the value it points to and a nil pointer is not
+// checked, so the optional fields of partial updates are only validated when they are given.
+// Violations are reported by the json name of the field and all of them are returned at once.
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError tells which field is invalid and why
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Errors are all the violations found in a value, it is an error when it is not empty
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, fe := range e {
+		messages[i] = fe.Field + " " + fe.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add records that the field is invalid
+func (e *Errors) Add(field, message string) {
+	*e = append(*e, FieldError{Field: field, Message: message})
+}
+
+// Has tells whether a violation was recorded for the field
+func (e Errors) Has(field string) bool {
+	for _, fe := range e {
+		if fe.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Err returns the errors as an error, nil when there are none
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Struct checks the fields of v, a struct or a pointer to one, against their `validate` tags
+// and returns every violation found. It panics when a tag is malformed
+func Struct(v interface{}) Errors {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	rt := rv.Type()
+	var errs Errors
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag := field.Tag.Get("validate")
+		if tag == "" || !field.IsExported() {
+			continue
+		}
+		value := rv.Field(i)
+		if value.Kind() == reflect.Pointer {
+			if value.IsNil() {
+				continue
+			}
+			value = value.Elem()
+		}
+		name := jsonName(field)
+		for _, rule := range strings.Split(tag, ",") {
+			if msg := check(value, rule); msg != "" {
+				errs.Add(name, msg)
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// check applies one rule to the value and returns the violation message, "" when it passes
+func check(value reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(rule, "=")
+	switch name {
+	case "required":
+		if value.Kind() == reflect.String {
+			if strings.TrimSpace(value.String()) == "" {
+				return "is required"
+			}
+		} else if value.IsZero() {
+			return "is required"
+		}
+	case "min":
+		limit := parseLimit(rule, arg)
+		if value.Kind() == reflect.String {
+			if float64(utf8.RuneCountInString(value.String())) < limit {
+				return fmt.Sprintf("must be at least %s characters long", arg)
+			}
+		} else if number(value, rule) < limit {
+			return "must be at least " + arg
+		}
+	case "max":
+		limit := parseLimit(rule, arg)
+		if value.Kind() == reflect.String {
+			if float64(utf8.RuneCountInString(value.String())) > limit {
+				return fmt.Sprintf("must be at most %s characters long", arg)
+			}
+		} else if number(value, rule) > limit {
+			return "must be at most " + arg
+		}
+	case "oneof":
+		allowed := strings.Fields(arg)
+		s := fmt.Sprint(value.Interface())
+		if s == "" {
+			return ""
+		}
+		for _, a := range allowed {
+			if s == a {
+				return ""
+			}
+		}
+		return "must be one of " + strings.Join(allowed, ", ")
+	default:
+		panic(fmt.Sprintf("validation: unknown rule %q", rule))
+	}
+	return ""
+}
+
+func parseLimit(rule, arg string) float64 {
+	limit, err := strconv.ParseFloat(arg, 64)
+	if err != nil {
+		panic(fmt.Sprintf("validation: invalid limit in %q", rule))
+	}
+	return limit
+}
+
+func number(value reflect.Value, rule string) float64 {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint())
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	}
+	panic(fmt.Sprintf("validation: %q cannot be applied to a %s", rule, value.Kind()))
+}
+
+// jsonName is the name of the field in the JSON documents of the API
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
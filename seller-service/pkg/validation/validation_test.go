@@ -0,0 +1,109 @@
+package validation
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	Name     string   `json:"name" validate:"required,max=5"`
+	Code     string   `json:"code,omitempty" validate:"min=2"`
+	Price    float64  `json:"price" validate:"min=0,max=99.5"`
+	Quantity int      `json:"quantity" validate:"required,min=1"`
+	Page     uint64   `validate:"min=1,max=100"`
+	Sort     string   `json:"sort" validate:"oneof=price name"`
+	Note     *string  `json:"note" validate:"required,max=3"`
+	Discount *float64 `json:"discount" validate:"max=1"`
+	ignored  string   `validate:"required"`
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestStruct(t *testing.T) {
+	tests := []struct {
+		name string
+		item item
+		want Errors
+	}{
+		{
+			"valid",
+			item{Name: "pen", Code: "ab", Price: 99.5, Quantity: 1, Page: 100, Sort: "price", Note: ptr("abc")},
+			nil,
+		},
+		{
+			"nil pointers and an empty oneof are not checked",
+			item{Name: "pen", Code: "ab", Quantity: 1, Page: 1},
+			nil,
+		},
+		{
+			"every violation is reported once per field",
+			item{Name: "  ", Code: "a", Price: -1, Quantity: 0, Page: 101, Sort: "id", Note: ptr(""), Discount: ptr(1.5)},
+			Errors{
+				{"name", "is required"},
+				{"code", "must be at least 2 characters long"},
+				{"price", "must be at least 0"},
+				{"quantity", "is required"},
+				{"Page", "must be at most 100"},
+				{"sort", "must be one of price, name"},
+				{"note", "is required"},
+				{"discount", "must be at most 1"},
+			},
+		},
+		{
+			"lengths are counted in characters",
+			item{Name: "éééééé", Code: "éé", Quantity: -1, Page: 1, Note: ptr("ééé")},
+			Errors{
+				{"name", "must be at most 5 characters long"},
+				{"quantity", "must be at least 1"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Struct(&tt.item)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestErrors(t *testing.T) {
+	var errs Errors
+	if errs.Err() != nil {
+		t.Error("Expected no error without violations")
+	}
+	errs.Add("price", "must be at least 0")
+	errs.Add("name", "is required")
+	if !errs.Has("name") || errs.Has("quantity") {
+		t.Errorf("Unexpected Has result for %v", errs)
+	}
+	if got := errs.Err().Error(); got != "price must be at least 0; name is required" {
+		t.Errorf("Unexpected message %q", got)
+	}
+}
+
+func TestStruct_PanicsOnMalformedTags(t *testing.T) {
+	tests := []interface{}{
+		&struct {
+			A string `validate:"unknown"`
+		}{},
+		&struct {
+			A int `validate:"min=x"`
+		}{},
+		&struct {
+			A bool `validate:"max=1"`
+		}{},
+	}
+	for _, v := range tests {
+		func() {
+			defer func() {
+				if r := recover(); r == nil || !strings.HasPrefix(r.(string), "validation: ") {
+					t.Errorf("Expected a validation panic for %T, got %v", v, r)
+				}
+			}()
+			Struct(v)
+		}()
+	}
+}
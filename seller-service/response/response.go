@@ -0,0 +1,132 @@
+// Package response - writes every response of the API in the same JSON envelope
+//
+// Successful responses carry their payload under "data", and optionally "meta":
+//
+//	{"data": {"id": 1, "name": "Seller Name", "location": "IND"}}
+//
+// Errors carry a machine readable code, a message, the per-field details of validation
+// errors and the id of the request:
+//
+//	{"error": {"code": "validation_failed", "message": "...", "details": [{"field": "price", "message": "..."}], "requestId": "..."}}
+package response
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+)
+
+// RequestIDHeader is the header carrying the id of a request from the client and back in the
+// response
+const RequestIDHeader = "X-Request-ID"
+
+// requestIDKey is the key of the request id stored in a context
+type requestIDKey struct{}
+
+// WithRequestID - returns a copy of ctx carrying the id of the request, reported by the error
+// responses. The id travels in the context because http.TimeoutHandler hides the response
+// headers already set from the handler it wraps
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID - returns the id of the request carried by ctx, empty if there is none
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// Error codes shared by the whole API
+const (
+	CodeInvalidBody      = "invalid_body"
+	CodeInvalidID        = "invalid_id"
+	CodeValidationFailed = "validation_failed"
+	CodeUnauthorized     = "unauthorized"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeTimeout          = "timeout"
+	CodeInternal         = "internal_error"
+)
+
+// Error is an error returned to the client
+type Error struct {
+	Status    int          `json:"-"`
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Details   []FieldError `json:"details,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+}
+
+// FieldError tells which field of the request is invalid and why
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// envelope is the body of every response, exactly one of Data and Error is set
+type envelope struct {
+	Data  interface{} `json:"data,omitempty"`
+	Meta  interface{} `json:"meta,omitempty"`
+	Error *Error      `json:"error,omitempty"`
+}
+
+// NewError creates an error responded with the status
+func NewError(status int, code, message string, details ...FieldError) *Error {
+	return &Error{Status: status, Code: code, Message: message, Details: details}
+}
+
+// BadRequest creates a 400 error
+func BadRequest(code, message string, details ...FieldError) *Error {
+	return NewError(http.StatusBadRequest, code, message, details...)
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// JSON responds with the status and data in the envelope
+func JSON(w http.ResponseWriter, status int, data interface{}) {
+	write(w, status, envelope{Data: data})
+}
+
+// JSONWithMeta responds with the status, data and metadata such as pagination in the envelope
+func JSONWithMeta(w http.ResponseWriter, status int, data, meta interface{}) {
+	write(w, status, envelope{Data: data, Meta: meta})
+}
+
+// NoContent responds with 204 and no body
+func NoContent(w http.ResponseWriter) {
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Err responds with the error in the envelope along with the id of the request, see
+// WithRequestID. An error that is not an *Error is logged and answered with a 500 that does
+// not leak its message
+func Err(w http.ResponseWriter, r *http.Request, err error) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		logging.FromContext(r.Context()).Errorw("Request failed", "err", err)
+		apiErr = NewError(http.StatusInternalServerError, CodeInternal, "Internal Server Error")
+	}
+	body := *apiErr
+	body.RequestID = RequestID(r.Context())
+	write(w, body.Status, envelope{Error: &body})
+}
+
+// Body returns the JSON envelope of an error without writing it, for handlers that
+// must produce the body upfront such as http.TimeoutHandler
+func Body(apiErr *Error, requestID string) string {
+	body := *apiErr
+	body.RequestID = requestID
+	b, _ := json.Marshal(envelope{Error: &body})
+	return string(b)
+}
+
+func write(w http.ResponseWriter, status int, body envelope) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,82 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	JSONWithMeta(recorder, http.StatusCreated, map[string]int{"id": 1}, map[string]int{"total": 1})
+	if recorder.Code != http.StatusCreated {
+		t.Errorf("Expected status 201, got %d", recorder.Code)
+	}
+	if got := recorder.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Unexpected Content-Type %q", got)
+	}
+	want := `{"data":{"id":1},"meta":{"total":1}}`
+	if got := strings.TrimSpace(recorder.Body.String()); got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestErr(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		want   string
+	}{
+		{
+			"api error",
+			BadRequest(CodeValidationFailed, "invalid product", FieldError{Field: "price", Message: "cannot be negative"}),
+			http.StatusBadRequest,
+			`{"error":{"code":"validation_failed","message":"invalid product","details":[{"field":"price","message":"cannot be negative"}],"requestId":"abc"}}`,
+		},
+		{
+			"wrapped api error",
+			errors.Join(errors.New("context"), NewError(http.StatusNotFound, CodeNotFound, "Not found")),
+			http.StatusNotFound,
+			`{"error":{"code":"not_found","message":"Not found","requestId":"abc"}}`,
+		},
+		{
+			"internal error is not leaked",
+			errors.New("connection refused"),
+			http.StatusInternalServerError,
+			`{"error":{"code":"internal_error","message":"Internal Server Error","requestId":"abc"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			Err(recorder, req.WithContext(WithRequestID(req.Context(), "abc")), tt.err)
+			if recorder.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, recorder.Code)
+			}
+			if got := strings.TrimSpace(recorder.Body.String()); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBody(t *testing.T) {
+	apiErr := NewError(http.StatusServiceUnavailable, CodeTimeout, "Request timed out")
+	var body struct {
+		Error Error `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(Body(apiErr, "abc")), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Error.Code != CodeTimeout || body.Error.RequestID != "abc" {
+		t.Errorf("Unexpected body %+v", body.Error)
+	}
+	if apiErr.RequestID != "" {
+		t.Error("Body must not change the error it is given")
+	}
+}
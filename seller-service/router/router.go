@@ -0,0 +1,226 @@
+// Package router - dispatches requests on their method and path, with {name} path parameters
+//
+//	r := router.New()
+//	v1 := r.Group("/api/v1")
+//	v1.Get("/product/{id}", getProduct)
+//
+// Static segments take precedence over parameters, so /product/search is matched before
+// /product/{id}. A path matching a route registered for other methods is answered with a
+// 405 and an Allow header listing them. Trailing slashes are ignored.
+package router
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/middleware"
+)
+
+// Router is an http.Handler dispatching requests to the handlers registered for their method and path
+type Router struct {
+	root *node
+
+	// NotFound responds to requests matching no route, http.NotFound by default
+	NotFound http.Handler
+	// MethodNotAllowed responds to requests whose path matches a route registered for other
+	// methods, the Allow header is already set when it is called
+	MethodNotAllowed http.Handler
+}
+
+// node is a path segment in the routing tree
+type node struct {
+	static    map[string]*node
+	param     *node
+	paramName string
+	handlers  map[string]http.Handler
+}
+
+// Group registers routes under a common path prefix and middlewares, e.g. an api version
+type Group struct {
+	router      *Router
+	prefix      string
+	middlewares []middleware.Middleware
+}
+
+type paramsKey struct{}
+
+// params are the values of the path parameters of the matched route
+type params map[string]string
+
+// New creates an empty Router
+func New() *Router {
+	return &Router{
+		root:     &node{},
+		NotFound: http.HandlerFunc(http.NotFound),
+		MethodNotAllowed: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		}),
+	}
+}
+
+// Param returns the value of the path parameter name of the route matched for r, "" if there is none
+func Param(r *http.Request, name string) string {
+	p, _ := r.Context().Value(paramsKey{}).(params)
+	return p[name]
+}
+
+// WithParams returns a copy of r carrying the path parameters, it lets tests call a handler
+// expecting parameters without going through the router
+func WithParams(r *http.Request, keyvals ...string) *http.Request {
+	p := make(params)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		p[keyvals[i]] = keyvals[i+1]
+	}
+	return r.WithContext(context.WithValue(r.Context(), paramsKey{}, p))
+}
+
+// Handle registers the handler for the method and pattern, it panics when the route
+// conflicts with one already registered
+func (rt *Router) Handle(method, pattern string, h http.Handler) {
+	n := rt.root
+	for _, segment := range split(pattern) {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			name := segment[1 : len(segment)-1]
+			if n.param == nil {
+				n.param = &node{paramName: name}
+			} else if n.param.paramName != name {
+				panic(fmt.Sprintf("router: %s conflicts with parameter {%s} of another route", pattern, n.param.paramName))
+			}
+			n = n.param
+			continue
+		}
+		if n.static == nil {
+			n.static = make(map[string]*node)
+		}
+		child, ok := n.static[segment]
+		if !ok {
+			child = &node{}
+			n.static[segment] = child
+		}
+		n = child
+	}
+	if n.handlers == nil {
+		n.handlers = make(map[string]http.Handler)
+	}
+	if _, ok := n.handlers[method]; ok {
+		panic(fmt.Sprintf("router: %s %s is already registered", method, pattern))
+	}
+	n.handlers[method] = h
+}
+
+// HandleFunc registers the handler function for the method and pattern
+func (rt *Router) HandleFunc(method, pattern string, h http.HandlerFunc) {
+	rt.Handle(method, pattern, h)
+}
+
+// Group returns a group registering its routes under prefix, wrapped with the middlewares
+func (rt *Router) Group(prefix string, middlewares ...middleware.Middleware) *Group {
+	return &Group{router: rt, prefix: prefix, middlewares: middlewares}
+}
+
+// ServeHTTP dispatches the request to the handler of the matching route
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p := make(params)
+	n := rt.root.match(split(r.URL.Path), p)
+	if n == nil {
+		rt.NotFound.ServeHTTP(w, r)
+		return
+	}
+	h, ok := n.handlers[r.Method]
+	if !ok && r.Method == http.MethodHead {
+		h, ok = n.handlers[http.MethodGet]
+	}
+	if !ok {
+		w.Header().Set("Allow", strings.Join(n.allowed(), ", "))
+		if r.Method == http.MethodOptions {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		rt.MethodNotAllowed.ServeHTTP(w, r)
+		return
+	}
+	if len(p) > 0 {
+		r = r.WithContext(context.WithValue(r.Context(), paramsKey{}, p))
+	}
+	h.ServeHTTP(w, r)
+}
+
+// match finds the node with handlers for the segments, preferring static segments over
+// parameters, and records the parameter values in p
+func (n *node) match(segments []string, p params) *node {
+	if len(segments) == 0 {
+		if len(n.handlers) == 0 {
+			return nil
+		}
+		return n
+	}
+	if child, ok := n.static[segments[0]]; ok {
+		if found := child.match(segments[1:], p); found != nil {
+			return found
+		}
+	}
+	if n.param != nil {
+		if found := n.param.match(segments[1:], p); found != nil {
+			p[n.param.paramName] = segments[0]
+			return found
+		}
+	}
+	return nil
+}
+
+// allowed lists the methods of the node for the Allow header
+func (n *node) allowed() []string {
+	methods := []string{http.MethodOptions}
+	for method := range n.handlers {
+		methods = append(methods, method)
+	}
+	if _, ok := n.handlers[http.MethodGet]; ok {
+		if _, ok := n.handlers[http.MethodHead]; !ok {
+			methods = append(methods, http.MethodHead)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+func split(path string) []string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	return segments
+}
+
+// Handle registers the handler for the method and the pattern under the group prefix
+func (g *Group) Handle(method, pattern string, h http.Handler) {
+	g.router.Handle(method, g.prefix+pattern, middleware.Chain(h, g.middlewares...))
+}
+
+// Group returns a sub group, its prefix and middlewares are added to the ones of g
+func (g *Group) Group(prefix string, middlewares ...middleware.Middleware) *Group {
+	return &Group{
+		router:      g.router,
+		prefix:      g.prefix + prefix,
+		middlewares: append(append([]middleware.Middleware{}, g.middlewares...), middlewares...),
+	}
+}
+
+// Get registers the handler for GET requests
+func (g *Group) Get(pattern string, h http.HandlerFunc) { g.Handle(http.MethodGet, pattern, h) }
+
+// Post registers the handler for POST requests
+func (g *Group) Post(pattern string, h http.HandlerFunc) { g.Handle(http.MethodPost, pattern, h) }
+
+// Put registers the handler for PUT requests
+func (g *Group) Put(pattern string, h http.HandlerFunc) { g.Handle(http.MethodPut, pattern, h) }
+
+// Patch registers the handler for PATCH requests
+func (g *Group) Patch(pattern string, h http.HandlerFunc) { g.Handle(http.MethodPatch, pattern, h) }
+
+// Delete registers the handler for DELETE requests
+func (g *Group) Delete(pattern string, h http.HandlerFunc) { g.Handle(http.MethodDelete, pattern, h) }
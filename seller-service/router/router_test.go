@@ -0,0 +1,135 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/middleware"
+)
+
+// echo responds with the name of the route and its id parameter
+func echo(name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name + ":" + Param(r, "id")))
+	}
+}
+
+func newTestRouter() *Router {
+	r := New()
+	v1 := r.Group("/api/v1")
+	v1.Post("/product", echo("create"))
+	v1.Get("/product/search", echo("search"))
+	v1.Get("/product/{id}", echo("get"))
+	v1.Delete("/product/{id}", echo("delete"))
+	v1.Get("/seller/{id}/products", echo("seller-products"))
+	return r
+}
+
+func TestRouter_Dispatch(t *testing.T) {
+	r := newTestRouter()
+	tests := []struct {
+		method, path string
+		status       int
+		body         string
+	}{
+		{http.MethodPost, "/api/v1/product", http.StatusOK, "create:"},
+		{http.MethodPost, "/api/v1/product/", http.StatusOK, "create:"},
+		{http.MethodGet, "/api/v1/product/search", http.StatusOK, "search:"},
+		{http.MethodGet, "/api/v1/product/42", http.StatusOK, "get:42"},
+		{http.MethodDelete, "/api/v1/product/42", http.StatusOK, "delete:42"},
+		{http.MethodHead, "/api/v1/product/42", http.StatusOK, ""},
+		{http.MethodGet, "/api/v1/seller/7/products", http.StatusOK, "seller-products:7"},
+		{http.MethodGet, "/api/v1/seller/7", http.StatusNotFound, ""},
+		{http.MethodGet, "/api/v2/product", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if rec.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, rec.Code)
+			}
+			if tt.status == http.StatusOK && tt.method != http.MethodHead && rec.Body.String() != tt.body {
+				t.Errorf("Expected body %q, got %q", tt.body, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	r := newTestRouter()
+	tests := map[string]string{
+		"/api/v1/product":        "OPTIONS, POST",
+		"/api/v1/product/search": "GET, HEAD, OPTIONS",
+		"/api/v1/product/1":      "DELETE, GET, HEAD, OPTIONS",
+	}
+	for path, allow := range tests {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, path, nil))
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("%s: expected status 405, got %d", path, rec.Code)
+		}
+		if got := rec.Header().Get("Allow"); got != allow {
+			t.Errorf("%s: expected Allow %q, got %q", path, allow, got)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodOptions, "/api/v1/product", nil))
+	if rec.Code != http.StatusNoContent || rec.Header().Get("Allow") != "OPTIONS, POST" {
+		t.Errorf("Unexpected OPTIONS response %d %q", rec.Code, rec.Header().Get("Allow"))
+	}
+}
+
+func TestRouter_GroupMiddlewares(t *testing.T) {
+	tag := func(value string) middleware.Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Add("X-Tag", value)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+	r := New()
+	admin := r.Group("/api/v1", tag("v1")).Group("/admin", tag("admin"))
+	admin.Get("/stats", echo("stats"))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/admin/stats", nil))
+	if rec.Body.String() != "stats:" {
+		t.Fatalf("Unexpected body %q", rec.Body.String())
+	}
+	if tags := rec.Header().Values("X-Tag"); len(tags) != 2 || tags[0] != "v1" || tags[1] != "admin" {
+		t.Errorf("Expected the group middlewares outermost first, got %v", tags)
+	}
+}
+
+func TestRouter_Conflicts(t *testing.T) {
+	for name, register := range map[string]func(r *Router){
+		"duplicate route": func(r *Router) {
+			r.HandleFunc(http.MethodGet, "/product/{id}", echo("a"))
+			r.HandleFunc(http.MethodGet, "/product/{id}/", echo("b"))
+		},
+		"parameter names": func(r *Router) {
+			r.HandleFunc(http.MethodGet, "/product/{id}", echo("a"))
+			r.HandleFunc(http.MethodDelete, "/product/{productId}", echo("b"))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected a panic")
+				}
+			}()
+			register(New())
+		})
+	}
+}
+
+func TestWithParams(t *testing.T) {
+	r := WithParams(httptest.NewRequest(http.MethodGet, "/", nil), "id", "3")
+	if Param(r, "id") != "3" || Param(r, "missing") != "" {
+		t.Errorf("Unexpected params %q %q", Param(r, "id"), Param(r, "missing"))
+	}
+}
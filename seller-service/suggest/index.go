@@ -0,0 +1,242 @@
+// Package suggest - completes the beginning of a product name typed by a buyer with the names
+// of the products, from an index held in memory so suggestions are answered without a query.
+//
+// The index is built from the store at startup by Rebuild and kept up to date by the products
+// created, renamed and deleted through a TrackingStore.
+package suggest
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// Suggestion is a product name completing a prefix
+type Suggestion struct {
+	Name string `json:"name"`
+	// ProductCount is the number of products with the name
+	ProductCount int `json:"productCount"`
+}
+
+// entry is a name of the index, names differing only in case are one entry
+type entry struct {
+	// name is the name as it was first added
+	name  string
+	count int
+}
+
+// term is a suffix of a normalized name starting at one of its words, e.g. "smart tv" and
+// "tv" for Smart TV, so a prefix of any word finds the name
+type term struct {
+	key string
+	// name is the normalized name the term belongs to
+	name string
+	// word is the index of the word the term starts at, 0 for the whole name
+	word int
+}
+
+// Index is a prefix index of the product names, safe for concurrent use
+type Index struct {
+	mu    sync.RWMutex
+	names map[string]*entry
+	// terms are sorted by key then name, the terms starting with a prefix are contiguous
+	terms []term
+	// words counts the products whose name holds each word, the vocabulary Correct draws from
+	words map[string]int
+}
+
+// New creates an empty Index
+func New() *Index {
+	return &Index{names: make(map[string]*entry), words: make(map[string]int)}
+}
+
+// normalize lowercases the name and collapses its spaces, the form names are indexed and
+// prefixes looked up in
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// Add counts a product with the name
+func (ix *Index) Add(name string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	key := normalize(name)
+	if key == "" {
+		return
+	}
+	countWords(ix.words, key, 1)
+	if e, ok := ix.names[key]; ok {
+		e.count++
+		return
+	}
+	ix.names[key] = &entry{name: strings.TrimSpace(name), count: 1}
+	for _, t := range termsOf(key) {
+		i, _ := ix.find(t)
+		ix.terms = append(ix.terms, term{})
+		copy(ix.terms[i+1:], ix.terms[i:])
+		ix.terms[i] = t
+	}
+}
+
+// Remove uncounts a product with the name, the name leaves the index with its last product
+func (ix *Index) Remove(name string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	key := normalize(name)
+	e, ok := ix.names[key]
+	if !ok {
+		return
+	}
+	countWords(ix.words, key, -1)
+	if e.count--; e.count > 0 {
+		return
+	}
+	delete(ix.names, key)
+	for _, t := range termsOf(key) {
+		if i, found := ix.find(t); found {
+			ix.terms = append(ix.terms[:i], ix.terms[i+1:]...)
+		}
+	}
+}
+
+// Rename moves a product from one name to another
+func (ix *Index) Rename(from, to string) {
+	if normalize(from) == normalize(to) {
+		return
+	}
+	ix.Remove(from)
+	ix.Add(to)
+}
+
+// find returns the position of the term in ix.terms, or where it would be inserted
+func (ix *Index) find(t term) (int, bool) {
+	i := sort.Search(len(ix.terms), func(i int) bool {
+		k := ix.terms[i]
+		return k.key > t.key || k.key == t.key && k.name >= t.name
+	})
+	return i, i < len(ix.terms) && ix.terms[i] == t
+}
+
+// termsOf returns the terms of a normalized name, one per word. Words start after a space or
+// a punctuation mark, e.g. book in e-book
+func termsOf(name string) []term {
+	var terms []term
+	inWord := false
+	for i, r := range name {
+		letter := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if letter && !inWord {
+			terms = append(terms, term{key: name[i:], name: name, word: len(terms)})
+		}
+		inWord = letter
+	}
+	return terms
+}
+
+// Suggest returns at most limit names completing the prefix, without regard to case. Names
+// starting with the prefix come before those with a later word starting with it, then the
+// names of the most products, then the shortest, then in alphabetical order
+func (ix *Index) Suggest(prefix string, limit int) []Suggestion {
+	prefix = normalize(prefix)
+	if prefix == "" || limit <= 0 {
+		return []Suggestion{}
+	}
+	ix.mu.RLock()
+	// the first word of each name found, names are found once per word starting with the prefix
+	words := make(map[string]int)
+	start := sort.Search(len(ix.terms), func(i int) bool { return ix.terms[i].key >= prefix })
+	for _, t := range ix.terms[start:] {
+		if !strings.HasPrefix(t.key, prefix) {
+			break
+		}
+		if word, ok := words[t.name]; !ok || t.word < word {
+			words[t.name] = t.word
+		}
+	}
+	type match struct {
+		Suggestion
+		key    string
+		inName bool
+	}
+	matches := make([]match, 0, len(words))
+	for key, word := range words {
+		e := ix.names[key]
+		matches = append(matches, match{Suggestion: Suggestion{Name: e.name, ProductCount: e.count}, key: key, inName: word == 0})
+	}
+	ix.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		switch {
+		case a.inName != b.inName:
+			return a.inName
+		case a.ProductCount != b.ProductCount:
+			return a.ProductCount > b.ProductCount
+		case len(a.key) != len(b.key):
+			return len(a.key) < len(b.key)
+		}
+		return a.key < b.key
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	suggestions := make([]Suggestion, len(matches))
+	for i, m := range matches {
+		suggestions[i] = m.Suggestion
+	}
+	return suggestions
+}
+
+// Rebuild replaces the content of the index with the names of the products of the store
+func (ix *Index) Rebuild(ctx context.Context, products models.ProductStore) error {
+	counts, err := products.CountProductNames(ctx)
+	if err != nil {
+		return err
+	}
+	// the terms are sorted once rather than inserted one at a time
+	names := make(map[string]*entry, len(counts))
+	words := make(map[string]int)
+	var terms []term
+	for name, count := range counts {
+		key := normalize(name)
+		if key == "" {
+			continue
+		}
+		countWords(words, key, count)
+		if e, ok := names[key]; ok {
+			e.count += count
+			continue
+		}
+		names[key] = &entry{name: strings.TrimSpace(name), count: count}
+		terms = append(terms, termsOf(key)...)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		a, b := terms[i], terms[j]
+		return a.key < b.key || a.key == b.key && a.name < b.name
+	})
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.names, ix.terms, ix.words = names, terms, words
+	return nil
+}
+
+// countWords adds n products to the count of each distinct word of the normalized name,
+// the words reaching 0 are removed
+func countWords(words map[string]int, name string, n int) {
+	seen := make(map[string]bool)
+	for _, word := range models.Tokenize(name) {
+		if seen[word] {
+			continue
+		}
+		seen[word] = true
+		if words[word] += n; words[word] <= 0 {
+			delete(words, word)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/memstore"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+func names(suggestions []Suggestion) []string {
+	names := make([]string, len(suggestions))
+	for i, s := range suggestions {
+		names[i] = s.Name
+	}
+	return names
+}
+
+func TestIndex_Suggest(t *testing.T) {
+	ix := New()
+	for _, name := range []string{"Smart TV", "Smartphone", "smartphone", "Wireless Smartwatch", "E-book Reader", "Laptop"} {
+		ix.Add(name)
+	}
+
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []string
+	}{
+		// the name of two products first, then the shortest, then the later word
+		{"sma", 10, []string{"Smartphone", "Smart TV", "Wireless Smartwatch"}},
+		{"  SMART   T", 10, []string{"Smart TV"}},
+		{"sma", 1, []string{"Smartphone"}},
+		{"book", 10, []string{"E-book Reader"}},
+		{"wireless smartw", 10, []string{"Wireless Smartwatch"}},
+		{"tablet", 10, []string{}},
+		{" ", 10, []string{}},
+	}
+	for _, tt := range tests {
+		if got := names(ix.Suggest(tt.prefix, tt.limit)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Suggest(%q, %d) = %v, want %v", tt.prefix, tt.limit, got, tt.want)
+		}
+	}
+	if got := ix.Suggest("smartp", 10); got[0].ProductCount != 2 {
+		t.Errorf("Expected names differing in case to be counted together, got %+v", got)
+	}
+}
+
+func TestIndex_RemoveRename(t *testing.T) {
+	ix := New()
+	ix.Add("Drone")
+	ix.Add("Drone")
+
+	ix.Remove("drone")
+	if got := ix.Suggest("dr", 10); !reflect.DeepEqual(got, []Suggestion{{Name: "Drone", ProductCount: 1}}) {
+		t.Errorf("Expected one product left named Drone, got %+v", got)
+	}
+	ix.Rename("Drone", "Camera Drone")
+	if got := names(ix.Suggest("dr", 10)); !reflect.DeepEqual(got, []string{"Camera Drone"}) {
+		t.Errorf("Expected the renamed product, got %v", got)
+	}
+	ix.Remove("Camera Drone")
+	ix.Remove("Unknown")
+	if got := ix.Suggest("c", 10); len(got) != 0 || len(ix.terms) != 0 {
+		t.Errorf("Expected an empty index, got %+v", got)
+	}
+}
+
+func TestTrackingStore(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	seller := models.Seller{Name: "Seller A", Location: "IND"}
+	if err := store.CreateSeller(ctx, &seller); err != nil {
+		t.Fatalf("CreateSeller: %v", err)
+	}
+	existing := models.Product{SellerID: seller.ID, ProductName: "Smartphone", Price: 10, Quantity: 1}
+	if err := store.CreateProduct(ctx, &existing); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+
+	ix := New()
+	if err := ix.Rebuild(ctx, store); err != nil {
+		t.Fatalf("Rebuild: %v", err)
+	}
+	tracked := Track(store, ix)
+	product := models.Product{SellerID: seller.ID, ProductName: "Smartwatch", Price: 20, Quantity: 1}
+	if err := tracked.CreateProduct(ctx, &product); err != nil {
+		t.Fatalf("CreateProduct: %v", err)
+	}
+	if got := names(ix.Suggest("smart", 10)); !reflect.DeepEqual(got, []string{"Smartphone", "Smartwatch"}) {
+		t.Errorf("Expected the rebuilt and created names, got %v", got)
+	}
+
+	name := "Smart TV"
+	if _, err := tracked.UpdateProduct(ctx, product.ID, models.ProductUpdate{ProductName: &name}); err != nil {
+		t.Fatalf("UpdateProduct: %v", err)
+	}
+	if got := names(ix.Suggest("smart", 10)); !reflect.DeepEqual(got, []string{"Smart TV", "Smartphone"}) {
+		t.Errorf("Expected the renamed product, got %v", got)
+	}
+
+	if err := tracked.DeleteSeller(ctx, seller.ID, true); err != nil {
+		t.Fatalf("DeleteSeller: %v", err)
+	}
+	if got := ix.Suggest("smart", 10); len(got) != 0 {
+		t.Errorf("Expected the products of the deleted seller to be gone, got %+v", got)
+	}
+}
+
+// failingCounts is a Backend whose product names cannot be counted
+type failingCounts struct {
+	*memstore.Store
+}
+
+func (failingCounts) CountProductNames(context.Context) (map[string]int, error) {
+	return nil, errors.New("connection lost")
+}
+
+func TestTrackingStore_DeleteSellerRebuildFails(t *testing.T) {
+	store := memstore.New()
+	seller := models.Seller{Name: "Seller A", Location: "IND"}
+	if err := store.CreateSeller(context.Background(), &seller); err != nil {
+		t.Fatalf("CreateSeller: %v", err)
+	}
+	ix := New()
+	ix.Add("Smartphone")
+	tracked := Track(failingCounts{store}, ix)
+
+	// the request is gone by the time of the rebuild, the seller is deleted all the same
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := tracked.DeleteSeller(ctx, seller.ID, true); err != nil {
+		t.Fatalf("Expected the committed delete to succeed, got %v", err)
+	}
+	if _, err := store.GetSellerByID(context.Background(), seller.ID); !errors.Is(err, models.ErrSellerNotFound) {
+		t.Errorf("Expected the seller to be deleted, got %v", err)
+	}
+	if got := names(ix.Suggest("smart", 10)); !reflect.DeepEqual(got, []string{"Smartphone"}) {
+		t.Errorf("Expected the index to keep its names until the next rebuild, got %v", got)
+	}
+}
@@ -0,0 +1,120 @@
+package suggest
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// Correct returns the text with its misspelled words replaced by the closest words of the
+// product names, or "" when it has none. A word is misspelled when it is indexed, see
+// models.Indexed, holds no digit, is in no product name and is not accepted by known. It is
+// replaced by the word at the smallest edit distance, at most 1 for words of up to 4 letters
+// and 2 for longer ones, the word of the most products winning a tie. Prefixes such as wire*
+// are left as they are
+func (ix *Index) Correct(text string, known func(word string) bool) string {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	runes := []rune(strings.ToLower(text))
+	var b strings.Builder
+	corrected := false
+	for i := 0; i < len(runes); {
+		if !isWordRune(runes[i]) {
+			b.WriteRune(runes[i])
+			i++
+			continue
+		}
+		j := i
+		for j < len(runes) && isWordRune(runes[j]) {
+			j++
+		}
+		word := string(runes[i:j])
+		prefix := j < len(runes) && runes[j] == '*'
+		if c, ok := ix.closest(word, known); ok && !prefix {
+			word, corrected = c, true
+		}
+		b.WriteString(word)
+		i = j
+	}
+	if !corrected {
+		return ""
+	}
+	return b.String()
+}
+
+// closest returns the word of the vocabulary replacing a misspelled word, false when the word
+// is not misspelled or nothing is close enough. Callers hold the lock
+func (ix *Index) closest(word string, known func(word string) bool) (string, bool) {
+	if !models.Indexed(word) || strings.IndexFunc(word, unicode.IsDigit) >= 0 || ix.words[word] > 0 || known != nil && known(word) {
+		return "", false
+	}
+	maxDistance := 1
+	if len([]rune(word)) > 4 {
+		maxDistance = 2
+	}
+	best, bestDistance, bestCount := "", maxDistance+1, 0
+	for candidate, count := range ix.words {
+		d := editDistance(word, candidate, maxDistance)
+		if d > maxDistance {
+			continue
+		}
+		if d < bestDistance || d == bestDistance && (count > bestCount || count == bestCount && candidate < best) {
+			best, bestDistance, bestCount = candidate, d, count
+		}
+	}
+	return best, best != ""
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions
+// of adjacent letters turning a into b, or max+1 as soon as it exceeds max
+func editDistance(a, b string, max int) int {
+	s, t := []rune(a), []rune(b)
+	if d := len(s) - len(t); d > max || -d > max {
+		return max + 1
+	}
+	// rows i-2, i-1 and i of the distances between the prefixes of s and t
+	prev2 := make([]int, len(t)+1)
+	prev := make([]int, len(t)+1)
+	cur := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		cur[0] = i
+		rowMin := cur[0]
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d := prev[j-1] + cost
+			if prev[j]+1 < d {
+				d = prev[j] + 1
+			}
+			if cur[j-1]+1 < d {
+				d = cur[j-1] + 1
+			}
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] && prev2[j-2]+1 < d {
+				d = prev2[j-2] + 1
+			}
+			cur[j] = d
+			if d < rowMin {
+				rowMin = d
+			}
+		}
+		if rowMin > max {
+			return max + 1
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	if prev[len(t)] > max {
+		return max + 1
+	}
+	return prev[len(t)]
+}
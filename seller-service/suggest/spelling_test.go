@@ -0,0 +1,55 @@
+package suggest
+
+import "testing"
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"smartfone", "smartphone", 2},
+		{"laptop", "latpop", 1},
+		{"drone", "drone", 0},
+		{"tablet", "table", 1},
+		{"camera", "drone", 3},
+		{"héadphones", "headphones", 1},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, 2); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestIndex_Correct(t *testing.T) {
+	ix := New()
+	for _, name := range []string{"Smartphone", "Smartphone", "Smart TV", "Wireless Earbuds", "Wired Mouse", "Tablet", "Table"} {
+		ix.Add(name)
+	}
+	earphones := func(word string) bool { return word == "earphones" }
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"smartfone", "smartphone"},
+		{"wireles earbud", "wireless earbuds"},
+		{`"wirless mouse"`, `"wireless mouse"`},
+		// known words, prefixes, short words and numbers are left alone
+		{"smartphone", ""},
+		{"earphones", ""},
+		{"smartfone*", ""},
+		{"tblet", "tablet"},
+		{"mousr 1080", "mouse 1080"},
+		{"zzzzzzz", ""},
+	}
+	for _, tt := range tests {
+		if got := ix.Correct(tt.text, earphones); got != tt.want {
+			t.Errorf("Correct(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+
+	ix.Remove("Tablet")
+	if got := ix.Correct("tablet", nil); got != "table" {
+		t.Errorf("Expected a removed word to be corrected, got %q", got)
+	}
+}
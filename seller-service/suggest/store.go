@@ -0,0 +1,109 @@
+package suggest
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/detach"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+)
+
+// rebuildTimeout bounds the rebuild of the index after the products of a seller are deleted,
+// which holds up the response of the delete
+const rebuildTimeout = 10 * time.Second
+
+// Backend is the store a TrackingStore wraps, sellers are wrapped too as deleting one can
+// delete its products
+type Backend interface {
+	models.ProductStore
+	models.SellerStore
+}
+
+// TrackingStore is a Backend keeping an Index up to date with the names of the products
+// created, renamed and deleted through it
+type TrackingStore struct {
+	Backend
+	index *Index
+	// mu serializes the writes reading the name of a product before changing it, so two of
+	// them cannot both take the same name out of the index, and the rebuilds. Creations only
+	// need to not run during a rebuild, which could miss them
+	mu sync.RWMutex
+}
+
+// Track wraps the store so the writes through it update the index
+func Track(store Backend, index *Index) *TrackingStore {
+	return &TrackingStore{Backend: store, index: index}
+}
+
+// CreateProduct saves the product and adds its name to the index
+func (s *TrackingStore) CreateProduct(ctx context.Context, p *models.Product) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if err := s.Backend.CreateProduct(ctx, p); err != nil {
+		return err
+	}
+	s.index.Add(p.ProductName)
+	return nil
+}
+
+// UpdateProduct applies the update and moves the product to its new name in the index
+func (s *TrackingStore) UpdateProduct(ctx context.Context, id int, update models.ProductUpdate) (models.Product, error) {
+	if update.ProductName == nil {
+		return s.Backend.UpdateProduct(ctx, id, update)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, err := s.Backend.GetProductByID(ctx, id)
+	if err != nil {
+		return models.Product{}, err
+	}
+	product, err := s.Backend.UpdateProduct(ctx, id, update)
+	if err != nil {
+		return product, err
+	}
+	s.index.Rename(previous.ProductName, product.ProductName)
+	return product, nil
+}
+
+// DeleteProduct deletes the product and removes its name from the index
+func (s *TrackingStore) DeleteProduct(ctx context.Context, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	product, err := s.Backend.GetProductByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.Backend.DeleteProduct(ctx, id); err != nil {
+		return err
+	}
+	s.index.Remove(product.ProductName)
+	return nil
+}
+
+// DeleteSeller deletes the seller and rebuilds the index when its products are deleted along
+// with it, which is rare enough not to track them one by one. The seller is gone once the delete
+// returns, so a failed rebuild is logged and the index keeps the names until the next one
+func (s *TrackingStore) DeleteSeller(ctx context.Context, id int, cascade bool) error {
+	if !cascade {
+		return s.Backend.DeleteSeller(ctx, id, cascade)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.Backend.DeleteSeller(ctx, id, cascade); err != nil {
+		return err
+	}
+	// the client may be gone once the seller is deleted, the rebuild still runs to the end
+	// unless it takes longer than rebuildTimeout
+	ctx, cancel := detach.WithTimeout(ctx, rebuildTimeout)
+	defer cancel()
+	if err := s.index.Rebuild(ctx, s.Backend); err != nil {
+		logging.FromContext(ctx).Errorw("Failed to rebuild the product name index", "sellerId", id, "err", err)
+	}
+	return nil
+}
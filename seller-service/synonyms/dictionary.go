@@ -0,0 +1,98 @@
+// Package synonyms - holds the synonym dictionary applied to the product searches in memory, so
+// searches do not read it from the store.
+//
+// The dictionary is loaded at startup and reloaded after every change made through it, a change
+// whose reload fails is kept and applied by the next reload.
+package synonyms
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/detach"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/pkg/logging"
+)
+
+// reloadTimeout bounds the reload of the dictionary after a change made through it
+const reloadTimeout = 5 * time.Second
+
+// Dictionary is a models.SynonymStore keeping the synonyms of the store in memory
+type Dictionary struct {
+	models.SynonymStore
+	// writes serializes the changes with their reload, so a reload never replaces a later one
+	writes   sync.Mutex
+	mu       sync.RWMutex
+	synonyms models.Synonyms
+}
+
+// New creates a Dictionary of the synonyms of the store, empty until Load
+func New(store models.SynonymStore) *Dictionary {
+	return &Dictionary{SynonymStore: store}
+}
+
+// Synonyms returns the current dictionary, it must not be modified
+func (d *Dictionary) Synonyms() models.Synonyms {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.synonyms
+}
+
+// Load reads the synonym groups of the store into the dictionary
+func (d *Dictionary) Load(ctx context.Context) error {
+	groups, err := d.SynonymStore.ListSynonymGroups(ctx)
+	if err != nil {
+		return err
+	}
+	synonyms := models.NewSynonyms(groups)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.synonyms = synonyms
+	return nil
+}
+
+// reload loads the dictionary after a change. The change is saved by then, so a failed load is
+// logged and the dictionary serves the previous synonyms until a later one. The load ignores the
+// admin request going away but gives up after reloadTimeout, so a slow synonym table cannot hold
+// up the response to a saved change
+func (d *Dictionary) reload(ctx context.Context) {
+	ctx, cancel := detach.WithTimeout(ctx, reloadTimeout)
+	defer cancel()
+	if err := d.Load(ctx); err != nil {
+		logging.FromContext(ctx).Errorw("Failed to reload the synonym dictionary", "err", err)
+	}
+}
+
+// CreateSynonymGroup saves the group and reloads the dictionary
+func (d *Dictionary) CreateSynonymGroup(ctx context.Context, g *models.SynonymGroup) error {
+	d.writes.Lock()
+	defer d.writes.Unlock()
+	if err := d.SynonymStore.CreateSynonymGroup(ctx, g); err != nil {
+		return err
+	}
+	d.reload(ctx)
+	return nil
+}
+
+// UpdateSynonymGroup replaces the terms of the group and reloads the dictionary
+func (d *Dictionary) UpdateSynonymGroup(ctx context.Context, g *models.SynonymGroup) error {
+	d.writes.Lock()
+	defer d.writes.Unlock()
+	if err := d.SynonymStore.UpdateSynonymGroup(ctx, g); err != nil {
+		return err
+	}
+	d.reload(ctx)
+	return nil
+}
+
+// DeleteSynonymGroup deletes the group and reloads the dictionary
+func (d *Dictionary) DeleteSynonymGroup(ctx context.Context, id int) error {
+	d.writes.Lock()
+	defer d.writes.Unlock()
+	if err := d.SynonymStore.DeleteSynonymGroup(ctx, id); err != nil {
+		return err
+	}
+	d.reload(ctx)
+	return nil
+}
@@ -0,0 +1,53 @@
+package synonyms
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/memstore"
+	"github.com/ganesh-sai/buyer-seller-app/seller-service/models"
+)
+
+// flakyStore is a store whose synonym groups cannot be listed while down
+type flakyStore struct {
+	*memstore.Store
+	down bool
+}
+
+func (s *flakyStore) ListSynonymGroups(ctx context.Context) ([]models.SynonymGroup, error) {
+	if s.down {
+		return nil, errors.New("connection lost")
+	}
+	return s.Store.ListSynonymGroups(ctx)
+}
+
+func TestDictionary_ReloadFails(t *testing.T) {
+	ctx := context.Background()
+	store := &flakyStore{Store: memstore.New()}
+	d := New(store)
+	if err := d.CreateSynonymGroup(ctx, &models.SynonymGroup{Terms: []string{"earbuds", "earphones"}}); err != nil {
+		t.Fatalf("CreateSynonymGroup: %v", err)
+	}
+	if got := d.Synonyms()["earbuds"]; !reflect.DeepEqual(got, []string{"earphones"}) {
+		t.Fatalf("Expected the created group to be loaded, got %v", got)
+	}
+
+	store.down = true
+	group := models.SynonymGroup{Terms: []string{"tv", "television"}}
+	if err := d.CreateSynonymGroup(ctx, &group); err != nil {
+		t.Fatalf("Expected the saved group to be created, got %v", err)
+	}
+	if d.Synonyms().Has("tv") || !d.Synonyms().Has("earbuds") {
+		t.Errorf("Expected the previous synonyms until a reload, got %v", d.Synonyms())
+	}
+
+	store.down = false
+	if err := d.Load(ctx); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if !d.Synonyms().Has("tv") {
+		t.Errorf("Expected the reload to apply the saved group, got %v", d.Synonyms())
+	}
+}